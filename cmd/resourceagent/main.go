@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"resourceagent/internal/collector"
@@ -44,6 +45,7 @@ func main() {
 		fmt.Printf("ResourceAgent %s (built %s)\n", version, buildTime)
 		os.Exit(0)
 	}
+	collector.AgentVersion = version
 
 	// Derive basePath from config path and change working directory.
 	// When running as a Windows service, the cwd is C:\Windows\System32.
@@ -144,7 +146,7 @@ func setupInfrastructure(ctx context.Context, cfg *config.Config, agentID string
 		timeDiffFunc: func() int64 { return 0 },
 	}
 
-	if strings.ToLower(cfg.SenderType) == "file" {
+	if cfg.NetworkSenderType() == "" {
 		return result, nil
 	}
 
@@ -202,8 +204,7 @@ func setupWithProxy(ctx context.Context, cfg *config.Config, result *infraResult
 		return nil, fmt.Errorf("ServiceDiscovery (index=0) failed: %w", err)
 	}
 
-	_, krErr := discovery.GetKafkaRestAddress(services)
-	if krErr != nil {
+	if _, krErr := discovery.GetKafkaRestAddress(services); krErr != nil && needsKafkaRest(cfg) {
 		return nil, fmt.Errorf("failed to get KafkaRest address from initial ServiceDiscovery: %w", krErr)
 	}
 
@@ -255,12 +256,9 @@ func setupWithProxy(ctx context.Context, cfg *config.Config, result *infraResult
 		return nil, fmt.Errorf("ServiceDiscovery (index=%s) failed: %w", info.Index, err)
 	}
 
-	kafkaRestAddr, err := discovery.GetKafkaRestAddress(services)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get KafkaRest address: %w", err)
+	if err := applyKafkaRestAddress(cfg, services); err != nil {
+		return nil, err
 	}
-	cfg.KafkaRestAddress = kafkaRestAddr
-	log.Info().Str("kafkarest_addr", kafkaRestAddr).Msg("KafkaRest address resolved")
 
 	// 7. TimeDiff Syncer
 	return startTimeDiffSyncer(ctx, cfg, result, redisAddress, dialFunc)
@@ -314,15 +312,37 @@ func setupWithoutProxy(ctx context.Context, cfg *config.Config, result *infraRes
 		return nil, fmt.Errorf("ServiceDiscovery failed: %w", err)
 	}
 
+	if err := applyKafkaRestAddress(cfg, services); err != nil {
+		return nil, err
+	}
+
+	// 6. TimeDiff Syncer
+	return startTimeDiffSyncer(ctx, cfg, result, redisAddress, nil)
+}
+
+// needsKafkaRest reports whether the configured senders use the discovered
+// KafkaRest address (kafkarest, or kafka via the broker on the same host).
+// OTLP, InfluxDB and MQTT only need the Redis EqpInfo.
+func needsKafkaRest(cfg *config.Config) bool {
+	nst := cfg.NetworkSenderType()
+	return nst == "kafkarest" || nst == "kafka"
+}
+
+// applyKafkaRestAddress sets cfg.KafkaRestAddress from ServiceDiscovery.
+// A missing KafkaRest entry is an error only if needsKafkaRest.
+func applyKafkaRestAddress(cfg *config.Config, services map[string]string) error {
+	log := logger.WithComponent("main")
 	kafkaRestAddr, err := discovery.GetKafkaRestAddress(services)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get KafkaRest address: %w", err)
+		if needsKafkaRest(cfg) {
+			return fmt.Errorf("failed to get KafkaRest address: %w", err)
+		}
+		log.Info().Err(err).Msg("KafkaRest address not resolved (not used by the configured sender)")
+		return nil
 	}
 	cfg.KafkaRestAddress = kafkaRestAddr
 	log.Info().Str("kafkarest_addr", kafkaRestAddr).Msg("KafkaRest address resolved")
-
-	// 6. TimeDiff Syncer
-	return startTimeDiffSyncer(ctx, cfg, result, redisAddress, nil)
+	return nil
 }
 
 // applyEqpInfo sets EqpInfo on config and result from Redis lookup.
@@ -396,11 +416,59 @@ func buildCandidatesNoProxy(detectedIP string, allIPs []string) []eqpinfo.IPCand
 	return candidates
 }
 
-// setupCollectors creates the collector registry and configures it from MonitorConfig.
-func setupCollectors(mc *config.MonitorConfig) *collector.Registry {
+// setupCollectors creates the collector registry, registers the plugin
+// collectors declared in MonitorConfig, and fills in defaults. The plugin
+// collectors are returned so their daemons can be stopped on shutdown.
+func setupCollectors(mc *config.MonitorConfig) (*collector.Registry, []*collector.PluginCollector) {
+	log := logger.WithComponent("main")
+
 	registry := collector.DefaultRegistry()
+	plugins, errs := collector.RegisterPlugins(registry, mc.Collectors)
+	for _, err := range errs {
+		log.Warn().Err(err).Msg("Plugin collector not registered")
+	}
+	for _, p := range plugins {
+		log.Info().Str("plugin", p.Name()).Msg("Plugin collector registered")
+	}
 	mc.ApplyDefaults(registry.DefaultConfigs())
-	return registry
+	return registry, plugins
+}
+
+// setupProbeNetwork gives the Probe collector the SOCKS dialer and the
+// agent's own Redis, ServiceDiscovery and KafkaRest addresses. kafkaRestAddr
+// holds the latest KafkaRest address announced by ServiceDiscovery.
+func setupProbeNetwork(registry *collector.Registry, cfg *config.Config, infra *infraResult, kafkaRestAddr *atomic.Value) {
+	c, ok := registry.Get("Probe")
+	if !ok {
+		return
+	}
+	probe, ok := c.(*collector.ProbeCollector)
+	if !ok {
+		return
+	}
+
+	// The file sender skips setupInfrastructure, so build the dialer here
+	// for probes that ask for the proxy.
+	dialFunc := infra.dialFunc
+	if dialFunc == nil && cfg.SOCKSProxy.Host != "" && cfg.SOCKSProxy.Port > 0 {
+		df, err := network.DialerFunc(cfg.SOCKSProxy.Host, cfg.SOCKSProxy.Port)
+		if err != nil {
+			log := logger.WithComponent("main")
+			log.Warn().Err(err).Msg("SOCKS dialer unavailable, Probe targets will be dialed directly")
+		}
+		dialFunc = df
+	}
+
+	var endpoints collector.ProbeEndpoints
+	if infra.virtualIP != "" {
+		endpoints.Redis = fmt.Sprintf("%s:%d", infra.virtualIP, cfg.Redis.Port)
+		endpoints.ServiceDiscovery = fmt.Sprintf("%s:%d", infra.virtualIP, cfg.ServiceDiscoveryPort)
+	}
+	probe.SetNetwork(dialFunc, func() collector.ProbeEndpoints {
+		e := endpoints
+		e.KafkaRest, _ = kafkaRestAddr.Load().(string)
+		return e
+	})
 }
 
 // setupSender creates the sender and logs sender-specific information.
@@ -426,7 +494,29 @@ func setupSender(cfg *config.Config, lc *logger.Config, timeDiffFunc func() int6
 		log.Info().
 			Str("kafkarest_addr", cfg.KafkaRestAddress).
 			Str("topic", topic).
+			Str("api_version", cfg.KafkaRest.APIVersion).
+			Str("embedded_format", cfg.KafkaRest.EmbeddedFormat).
+			Str("compression", cfg.KafkaRest.Compression).
 			Msg("Using KafkaRest sender")
+	case "otlp":
+		log.Info().
+			Str("endpoint", cfg.OTLP.Endpoint).
+			Msg("Using OTLP sender")
+	case "influx":
+		log.Info().
+			Str("url", cfg.Influx.URL).
+			Str("bucket", cfg.Influx.Bucket).
+			Msg("Using InfluxDB sender")
+	case "mqtt":
+		log.Info().
+			Str("broker", cfg.MQTT.Broker).
+			Str("topic_template", cfg.MQTT.TopicTemplate).
+			Msg("Using MQTT sender")
+	case "composite":
+		log.Info().
+			Int("destinations", len(sender.Unwrap(snd))).
+			Str("network_sender", cfg.NetworkSenderType()).
+			Msg("Using composite sender")
 	default:
 		topic := config.ResolveTopic(cfg.ResourceMonitorTopic, cfg.EqpInfo)
 		log.Info().
@@ -437,6 +527,133 @@ func setupSender(cfg *config.Config, lc *logger.Config, timeDiffFunc func() int6
 	return snd, nil
 }
 
+// kafkaSenderOf returns the KafkaSender behind snd (directly or as a
+// composite destination), or nil.
+func kafkaSenderOf(snd sender.Sender) *sender.KafkaSender {
+	for _, s := range sender.Unwrap(snd) {
+		if ks, ok := s.(*sender.KafkaSender); ok {
+			return ks
+		}
+	}
+	return nil
+}
+
+// statsSenderOf returns the sender behind snd whose buffer and delivery
+// stats feed SelfMetrics and the heartbeat: a Kafka-family sender if
+// present, else the first OTLP, InfluxDB or MQTT sender, or nil.
+func statsSenderOf(snd sender.Sender) sender.Sender {
+	if ks := kafkaSenderOf(snd); ks != nil {
+		return ks
+	}
+	for _, s := range sender.Unwrap(snd) {
+		switch s.(type) {
+		case *sender.OTLPSender, *sender.InfluxSender, *sender.MQTTSender:
+			return s
+		}
+	}
+	return nil
+}
+
+// compositeStats exposes the stats of statsSenderOf(snd) for a composite
+// sender, resolving it on every read: a destination created by a background
+// retry after startup is picked up as soon as it exists. Each method reports
+// zero / not attached while there is no stats sender.
+type compositeStats struct {
+	sender.Sender
+}
+
+func (c compositeStats) BufferStats() (count, dropped, hwm int64) {
+	if p, ok := statsSenderOf(c.Sender).(sender.BufferStatsProvider); ok {
+		return p.BufferStats()
+	}
+	return 0, 0, 0
+}
+
+func (c compositeStats) SpoolStats() (depth, bytes, replayed, expired int64, ok bool) {
+	if p, ok := statsSenderOf(c.Sender).(sender.SpoolStatsProvider); ok {
+		return p.SpoolStats()
+	}
+	return 0, 0, 0, 0, false
+}
+
+func (c compositeStats) BreakerStats() (state, opens, failures int64, ok bool) {
+	if p, ok := statsSenderOf(c.Sender).(sender.BreakerStatsProvider); ok {
+		return p.BreakerStats()
+	}
+	return 0, 0, 0, false
+}
+
+func (c compositeStats) DeliveryStats() (acked, failed, inFlight int64, lastErr string, ok bool) {
+	if p, ok := statsSenderOf(c.Sender).(sender.DeliveryStatsProvider); ok {
+		return p.DeliveryStats()
+	}
+	return 0, 0, 0, "", false
+}
+
+func (c compositeStats) CompressionStats() (rawBytes, wireBytes int64, ok bool) {
+	if p, ok := statsSenderOf(c.Sender).(sender.CompressionStatsProvider); ok {
+		return p.CompressionStats()
+	}
+	return 0, 0, false
+}
+
+// newAddressRefresher builds the refresher that follows KafkaRest address
+// changes from ServiceDiscovery and swaps kafkaSender's transport.
+func newAddressRefresher(cfg *config.Config, infra *infraResult, kafkaSender *sender.KafkaSender,
+	kafkaRestAddr *atomic.Value) *discovery.Refresher {
+	refresher := discovery.NewRefresher(discovery.RefresherConfig{
+		Interval: cfg.UpdateServerAddressInterval,
+	})
+
+	switch cfg.NetworkSenderType() {
+	case "kafkarest":
+		spool := kafkaSender.Spool()
+		refresher.SetTransportFactory(func(addr string) (discovery.Closeable, error) {
+			return sender.NewSpooledHTTPTransport(addr, cfg.SOCKSProxy, cfg.Batch, cfg.KafkaRest, spool)
+		})
+	case "kafka":
+		refresher.SetTransportFactory(func(addr string) (discovery.Closeable, error) {
+			brokerAddr, err := sender.ResolveBrokerAddr(addr, cfg.Kafka.BrokerPort)
+			if err != nil {
+				return nil, err
+			}
+			return sender.NewSaramaTransport([]string{brokerAddr}, cfg.Kafka, cfg.Batch, cfg.SOCKSProxy)
+		})
+	}
+
+	refresher.SetFetchAddr(func(fetchCtx context.Context) (string, error) {
+		services, err := infra.discClient.FetchServices(fetchCtx, infra.virtualIP,
+			cfg.ServiceDiscoveryPort, cfg.EqpInfo.Index)
+		if err != nil {
+			return "", err
+		}
+		addr, err := discovery.GetKafkaRestAddress(services)
+		if err == nil {
+			kafkaRestAddr.Store(addr)
+		}
+		return addr, err
+	})
+
+	refresher.SetSwapTransport(func(newT discovery.Closeable) (discovery.Closeable, error) {
+		kt, ok := newT.(sender.KafkaTransport)
+		if !ok {
+			return nil, fmt.Errorf("invalid transport type")
+		}
+		return kafkaSender.SwapTransport(kt)
+	})
+	return refresher
+}
+
+// fileSenderOf returns the FileSender behind snd, or nil.
+func fileSenderOf(snd sender.Sender) *sender.FileSender {
+	for _, s := range sender.Unwrap(snd) {
+		if fs, ok := s.(*sender.FileSender); ok {
+			return fs
+		}
+	}
+	return nil
+}
+
 // setupWatchers creates hot-reload watchers for Monitor.json and Logging.json.
 // Returns a cleanup function that stops all started watchers.
 func setupWatchers(registry *collector.Registry, sched *scheduler.Scheduler, snd sender.Sender,
@@ -491,7 +708,7 @@ func setupWatchers(registry *collector.Registry, sched *scheduler.Scheduler, snd
 			return
 		}
 
-		if fs, ok := snd.(*sender.FileSender); ok {
+		if fs := fileSenderOf(snd); fs != nil {
 			fs.SetConsole(newLC.Console)
 			log.Info().Bool("console", newLC.Console).Msg("FileSender console updated")
 		}
@@ -570,16 +787,23 @@ func run(ctx context.Context, cfg *config.Config, mc *config.MonitorConfig, lc *
 	defer lhmProvider.Stop()
 
 	// Phase 3: Collectors
-	registry := setupCollectors(mc)
+	registry, plugins := setupCollectors(mc)
+	for _, p := range plugins {
+		defer p.Stop()
+	}
 	if err := registry.Configure(mc.Collectors); err != nil {
 		return fmt.Errorf("failed to configure collectors: %w", err)
 	}
+	var kafkaRestAddr atomic.Value
+	kafkaRestAddr.Store(cfg.KafkaRestAddress)
+	setupProbeNetwork(registry, cfg, infra, &kafkaRestAddr)
 
 	// Phase 4: Sender
 	snd, err := setupSender(cfg, lc, infra.timeDiffFunc)
 	if err != nil {
 		return err
 	}
+	composite, _ := snd.(*sender.CompositeSender)
 	defer func() {
 		log.Info().Msg("Closing sender")
 		if err := snd.Close(); err != nil {
@@ -587,53 +811,54 @@ func run(ctx context.Context, cfg *config.Config, mc *config.MonitorConfig, lc *
 		}
 	}()
 
-	// Phase 4.5: Address Refresher
-	if cfg.UpdateServerAddressInterval > 0 && strings.ToLower(cfg.SenderType) != "file" {
-		if kafkaSender, ok := snd.(*sender.KafkaSender); ok {
-			refresher := discovery.NewRefresher(discovery.RefresherConfig{
-				Interval: cfg.UpdateServerAddressInterval,
-			})
+	// Phase 4.1: Prometheus exporter (pull) alongside the configured sender.
+	// A bind failure only disables the exporter.
+	if cfg.Prometheus.Enabled {
+		exporter := sender.NewPrometheusExporter(cfg.Prometheus)
+		if err := exporter.Start(); err != nil {
+			log.Warn().Err(err).Msg("Prometheus exporter disabled")
+		} else {
+			snd = sender.NewTeeSender(snd, exporter)
+		}
+	}
 
-			senderType := strings.ToLower(cfg.SenderType)
-			switch senderType {
-			case "kafkarest":
-				refresher.SetTransportFactory(func(addr string) (discovery.Closeable, error) {
-					return sender.NewBufferedHTTPTransport(addr, cfg.SOCKSProxy, cfg.Batch)
-				})
-			case "kafka":
-				refresher.SetTransportFactory(func(addr string) (discovery.Closeable, error) {
-					brokerAddr, err := sender.ResolveBrokerAddr(addr, cfg.Kafka.BrokerPort)
-					if err != nil {
-						return nil, err
-					}
-					return sender.NewSaramaTransport([]string{brokerAddr}, cfg.Kafka, cfg.Batch, cfg.SOCKSProxy)
-				})
+	// Phase 4.5: Address Refresher
+	// A composite destination that was down at startup gets its refresher
+	// when the background retry creates it.
+	if cfg.UpdateServerAddressInterval > 0 && cfg.NetworkSenderType() != "" {
+		var (
+			refresherMu sync.Mutex
+			refresher   *discovery.Refresher
+		)
+		startRefresher := func(kafkaSender *sender.KafkaSender) {
+			refresherMu.Lock()
+			defer refresherMu.Unlock()
+			if refresher != nil || ctx.Err() != nil {
+				return
 			}
-
-			refresher.SetFetchAddr(func(fetchCtx context.Context) (string, error) {
-				services, err := infra.discClient.FetchServices(fetchCtx, infra.virtualIP,
-					cfg.ServiceDiscoveryPort, cfg.EqpInfo.Index)
-				if err != nil {
-					return "", err
-				}
-				return discovery.GetKafkaRestAddress(services)
-			})
-
-			refresher.SetSwapTransport(func(newT discovery.Closeable) (discovery.Closeable, error) {
-				kt, ok := newT.(sender.KafkaTransport)
-				if !ok {
-					return nil, fmt.Errorf("invalid transport type")
-				}
-				return kafkaSender.SwapTransport(kt)
-			})
-
+			refresher = newAddressRefresher(cfg, infra, kafkaSender, &kafkaRestAddr)
 			refresher.Start(ctx, cfg.KafkaRestAddress)
-			defer refresher.Stop()
-
 			log.Info().
 				Dur("interval", cfg.UpdateServerAddressInterval).
 				Msg("Address refresher started")
 		}
+		defer func() {
+			refresherMu.Lock()
+			defer refresherMu.Unlock()
+			if refresher != nil {
+				refresher.Stop()
+			}
+		}()
+
+		if composite != nil {
+			composite.OnDestinationReady(func(s sender.Sender) {
+				if kafkaSender := kafkaSenderOf(s); kafkaSender != nil {
+					startRefresher(kafkaSender)
+				}
+			})
+		} else if kafkaSender := kafkaSenderOf(snd); kafkaSender != nil {
+			startRefresher(kafkaSender)
+		}
 	}
 
 	// Phase 4.6: SelfMetrics collector (Phase 2.5-1)
@@ -641,8 +866,10 @@ func run(ctx context.Context, cfg *config.Config, mc *config.MonitorConfig, lc *
 	// the sender is a KafkaSender backed by BufferedHTTPTransport.
 	{
 		var bufStats collector.BufferStatsProvider
-		if ks, ok := snd.(*sender.KafkaSender); ok {
-			bufStats = ks
+		if composite != nil && cfg.NetworkSenderType() != "" {
+			bufStats = compositeStats{snd}
+		} else if ss, ok := statsSenderOf(snd).(collector.BufferStatsProvider); ok {
+			bufStats = ss
 		}
 		selfMetrics := collector.NewSelfMetricsCollector(collector.NewDefaultRuntimeStats(), bufStats)
 		if err := registry.Register(selfMetrics); err != nil {
@@ -669,7 +896,16 @@ func run(ctx context.Context, cfg *config.Config, mc *config.MonitorConfig, lc *
 	}
 
 	// Connect heartbeat watchdog to scheduler activity
+	// and to the sender's delivery health (circuit breaker / kafka acks).
 	if hb != nil {
+		var delivery *sender.DeliveryHealth
+		if composite != nil && cfg.NetworkSenderType() != "" {
+			delivery = sender.NewDeliveryHealth(compositeStats{snd})
+		} else if ss := statsSenderOf(snd); ss != nil {
+			delivery = sender.NewDeliveryHealth(ss)
+		} else {
+			delivery = sender.NewDeliveryHealth(snd)
+		}
 		hb.SetHealthCheck(func() (string, string) {
 			last := sched.LastActivity()
 			if last.IsZero() {
@@ -678,7 +914,7 @@ func run(ctx context.Context, cfg *config.Config, mc *config.MonitorConfig, lc *
 			if time.Since(last) > heartbeat.StalenessThreshold {
 				return "WARN", "no_collection"
 			}
-			return "OK", ""
+			return delivery.Check()
 		})
 	}
 
@@ -13,7 +13,8 @@ type Collector interface {
 	// Name returns the unique identifier for this collector.
 	Name() string
 
-	// Collect gathers metrics and returns the collected data.
+	// Collect gathers metrics and returns the collected data. A nil result
+	// without error means there is nothing to send this cycle.
 	Collect(ctx context.Context) (*MetricData, error)
 
 	// Configure applies the given configuration to the collector.
@@ -29,6 +30,15 @@ type Collector interface {
 	DefaultConfig() config.CollectorConfig
 }
 
+// SentNotifier is implemented by collectors that persist what they have
+// reported and send only what is new (Inventory, Software, LogWatch). The
+// scheduler calls Sent once the sender has accepted data returned by
+// Collect; until then the collector keeps its previous state, so a lost
+// send is reported again on the next cycle.
+type SentNotifier interface {
+	Sent(data *MetricData)
+}
+
 // BaseCollector provides common functionality for all collectors.
 type BaseCollector struct {
 	name     string
@@ -0,0 +1,198 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"resourceagent/internal/config"
+)
+
+// tcpStates are reported every cycle, 0 included, so that a state such as
+// CLOSE_WAIT has a continuous series. Names follow /proc/net/tcp.
+var tcpStates = []string{
+	"ESTABLISHED", "SYN_SENT", "SYN_RECV", "FIN_WAIT1", "FIN_WAIT2",
+	"TIME_WAIT", "CLOSE", "CLOSE_WAIT", "LAST_ACK", "LISTEN", "CLOSING",
+}
+
+// tcpStateAliases maps Windows (GetExtendedTcpTable) state names onto tcpStates.
+var tcpStateAliases = map[string]string{
+	"SYN_RECEIVED": "SYN_RECV",
+	"FIN_WAIT_1":   "FIN_WAIT1",
+	"FIN_WAIT_2":   "FIN_WAIT2",
+	"CLOSED":       "CLOSE",
+	"DELETE":       "CLOSE",
+}
+
+// ConnectionsCollector breaks TCP sockets down by state, lists listening
+// ports with their owning process, and counts established connections per
+// remote host for the ports in WatchPorts.
+type ConnectionsCollector struct {
+	BaseCollector
+	watchPorts []uint32
+}
+
+// NewConnectionsCollector creates a new connections collector.
+func NewConnectionsCollector() *ConnectionsCollector {
+	return &ConnectionsCollector{
+		BaseCollector: NewBaseCollector("Connections"),
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the connections collector.
+func (c *ConnectionsCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 60 * time.Second
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *ConnectionsCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+	ports := make([]uint32, 0, len(cfg.WatchPorts))
+	for _, p := range cfg.WatchPorts {
+		ports = append(ports, uint32(p))
+	}
+	c.watchPorts = ports
+	return nil
+}
+
+// Collect gathers the TCP connection breakdown.
+//
+// On Linux, socket owners are resolved by scanning /proc/[pid]/fd, which
+// only covers the agent's own user unless it runs as root; listeners of
+// other users are reported with PID 0.
+func (c *ConnectionsCollector) Collect(ctx context.Context) (*MetricData, error) {
+	conns, err := net.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      summarizeConnections(conns, c.watchPorts, processNameLookup(ctx)),
+	}, nil
+}
+
+// processNameLookup returns a PID → name function that caches results for
+// one cycle.
+func processNameLookup(ctx context.Context) func(pid int32) string {
+	names := make(map[int32]string)
+	return func(pid int32) string {
+		if pid <= 0 {
+			return ""
+		}
+		if name, ok := names[pid]; ok {
+			return name
+		}
+		var name string
+		if p, err := process.NewProcessWithContext(ctx, pid); err == nil {
+			name, _ = p.NameWithContext(ctx)
+		}
+		names[pid] = name
+		return name
+	}
+}
+
+// summarizeConnections builds ConnectionsData from a socket table.
+// Connections on a watched port are matched by either side, so both
+// outbound connections to a server port and inbound connections to a
+// local service port are counted per remote IP.
+func summarizeConnections(conns []net.ConnectionStat, watchPorts []uint32, nameOf func(pid int32) string) ConnectionsData {
+	stateCounts := make(map[string]int, len(tcpStates))
+	watched := make(map[uint32]int, len(watchPorts))
+	for _, p := range watchPorts {
+		watched[p] = 0
+	}
+	type endpointKey struct {
+		port uint32
+		ip   string
+	}
+	endpoints := make(map[endpointKey]int)
+	type listenKey struct {
+		addr string
+		port uint32
+	}
+	listeners := make(map[listenKey]ListeningPort)
+
+	for _, conn := range conns {
+		state := conn.Status
+		if alias, ok := tcpStateAliases[state]; ok {
+			state = alias
+		}
+		stateCounts[state]++
+
+		if state == "LISTEN" {
+			key := listenKey{addr: conn.Laddr.IP, port: conn.Laddr.Port}
+			if _, ok := listeners[key]; !ok {
+				listeners[key] = ListeningPort{
+					Protocol:    "tcp",
+					Address:     conn.Laddr.IP,
+					Port:        conn.Laddr.Port,
+					PID:         conn.Pid,
+					ProcessName: nameOf(conn.Pid),
+				}
+			}
+			continue
+		}
+
+		if state != "ESTABLISHED" || len(watched) == 0 {
+			continue
+		}
+		for _, port := range []uint32{conn.Raddr.Port, conn.Laddr.Port} {
+			if _, ok := watched[port]; ok {
+				watched[port]++
+				endpoints[endpointKey{port: port, ip: conn.Raddr.IP}]++
+				break
+			}
+		}
+	}
+
+	var data ConnectionsData
+	for _, s := range tcpStates {
+		data.States = append(data.States, TCPStateCount{State: s, Count: stateCounts[s]})
+		delete(stateCounts, s)
+	}
+	extra := make([]string, 0, len(stateCounts))
+	for s := range stateCounts {
+		extra = append(extra, s)
+	}
+	sort.Strings(extra)
+	for _, s := range extra {
+		data.States = append(data.States, TCPStateCount{State: s, Count: stateCounts[s]})
+	}
+
+	data.Listeners = make([]ListeningPort, 0, len(listeners))
+	for _, l := range listeners {
+		data.Listeners = append(data.Listeners, l)
+	}
+	sort.Slice(data.Listeners, func(i, j int) bool {
+		a, b := data.Listeners[i], data.Listeners[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Address < b.Address
+	})
+
+	for _, p := range watchPorts {
+		data.Ports = append(data.Ports, PortSummary{Port: p, Established: watched[p]})
+	}
+	for k, n := range endpoints {
+		data.Endpoints = append(data.Endpoints, EndpointCount{Port: k.port, RemoteIP: k.ip, Count: n})
+	}
+	sort.Slice(data.Endpoints, func(i, j int) bool {
+		a, b := data.Endpoints[i], data.Endpoints[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.RemoteIP < b.RemoteIP
+	})
+	return data
+}
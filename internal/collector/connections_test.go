@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	"resourceagent/internal/config"
+)
+
+func tcpConn(status, lip string, lport uint32, rip string, rport uint32, pid int32) net.ConnectionStat {
+	return net.ConnectionStat{
+		Status: status,
+		Laddr:  net.Addr{IP: lip, Port: lport},
+		Raddr:  net.Addr{IP: rip, Port: rport},
+		Pid:    pid,
+	}
+}
+
+func TestSummarizeConnections_States(t *testing.T) {
+	conns := []net.ConnectionStat{
+		tcpConn("ESTABLISHED", "10.0.0.5", 50001, "10.0.0.9", 443, 1),
+		tcpConn("CLOSE_WAIT", "10.0.0.5", 50002, "10.0.0.9", 443, 1),
+		tcpConn("CLOSE_WAIT", "10.0.0.5", 50003, "10.0.0.9", 443, 1),
+		// Windows state names
+		tcpConn("FIN_WAIT_2", "10.0.0.5", 50004, "10.0.0.9", 443, 1),
+		tcpConn("SYN_RECEIVED", "10.0.0.5", 5000, "10.0.0.7", 40000, 1),
+		tcpConn("BOUND", "0.0.0.0", 0, "", 0, 1),
+	}
+
+	data := summarizeConnections(conns, nil, func(int32) string { return "" })
+
+	counts := make(map[string]int)
+	for _, s := range data.States {
+		counts[s.State] = s.Count
+	}
+	want := map[string]int{
+		"ESTABLISHED": 1, "CLOSE_WAIT": 2, "FIN_WAIT2": 1, "SYN_RECV": 1,
+		"TIME_WAIT": 0, "LISTEN": 0, "BOUND": 1,
+	}
+	for state, n := range want {
+		got, ok := counts[state]
+		if !ok || got != n {
+			t.Errorf("state %s = %d (present %v), want %d", state, got, ok, n)
+		}
+	}
+	if len(data.States) != len(tcpStates)+1 {
+		t.Errorf("len(States) = %d, want %d fixed states + BOUND", len(data.States), len(tcpStates)+1)
+	}
+	if data.States[0].State != "ESTABLISHED" || data.States[len(data.States)-1].State != "BOUND" {
+		t.Errorf("States order = %+v, want fixed states first, unknown last", data.States)
+	}
+}
+
+func TestSummarizeConnections_Listeners(t *testing.T) {
+	conns := []net.ConnectionStat{
+		tcpConn("LISTEN", "0.0.0.0", 5000, "", 0, 100),
+		tcpConn("LISTEN", "0.0.0.0", 5000, "", 0, 100), // SO_REUSEPORT duplicate
+		tcpConn("LISTEN", "::", 5000, "", 0, 100),
+		tcpConn("LISTEN", "127.0.0.1", 22, "", 0, 200),
+	}
+	lookups := 0
+	names := map[int32]string{100: "EqpHost.exe", 200: "sshd"}
+
+	data := summarizeConnections(conns, nil, func(pid int32) string {
+		lookups++
+		return names[pid]
+	})
+
+	if len(data.Listeners) != 3 {
+		t.Fatalf("len(Listeners) = %d, want 3: %+v", len(data.Listeners), data.Listeners)
+	}
+	first := data.Listeners[0]
+	if first.Port != 22 || first.ProcessName != "sshd" || first.PID != 200 || first.Protocol != "tcp" {
+		t.Errorf("Listeners[0] = %+v, want tcp 127.0.0.1:22 sshd", first)
+	}
+	if data.Listeners[1].Address != "0.0.0.0" || data.Listeners[2].Address != "::" {
+		t.Errorf("Listeners on port 5000 = %+v, want sorted by address", data.Listeners[1:])
+	}
+	if lookups != 3 {
+		t.Errorf("name lookups = %d, want one per distinct listener", lookups)
+	}
+}
+
+func TestSummarizeConnections_WatchPorts(t *testing.T) {
+	conns := []net.ConnectionStat{
+		// outbound to a Kafka broker
+		tcpConn("ESTABLISHED", "10.0.0.5", 50001, "10.0.1.1", 9092, 1),
+		tcpConn("ESTABLISHED", "10.0.0.5", 50002, "10.0.1.1", 9092, 1),
+		tcpConn("ESTABLISHED", "10.0.0.5", 50003, "10.0.1.2", 9092, 1),
+		// inbound to a local service
+		tcpConn("ESTABLISHED", "10.0.0.5", 5000, "10.0.2.7", 41000, 2),
+		// not established: ignored
+		tcpConn("TIME_WAIT", "10.0.0.5", 50004, "10.0.1.1", 9092, 0),
+		// unwatched port
+		tcpConn("ESTABLISHED", "10.0.0.5", 50005, "10.0.3.3", 443, 1),
+	}
+
+	data := summarizeConnections(conns, []uint32{9092, 5000, 8080}, func(int32) string { return "" })
+
+	wantPorts := []PortSummary{{Port: 9092, Established: 3}, {Port: 5000, Established: 1}, {Port: 8080, Established: 0}}
+	if len(data.Ports) != len(wantPorts) {
+		t.Fatalf("Ports = %+v, want %+v", data.Ports, wantPorts)
+	}
+	for i, p := range wantPorts {
+		if data.Ports[i] != p {
+			t.Errorf("Ports[%d] = %+v, want %+v", i, data.Ports[i], p)
+		}
+	}
+
+	wantEndpoints := []EndpointCount{
+		{Port: 5000, RemoteIP: "10.0.2.7", Count: 1},
+		{Port: 9092, RemoteIP: "10.0.1.1", Count: 2},
+		{Port: 9092, RemoteIP: "10.0.1.2", Count: 1},
+	}
+	if len(data.Endpoints) != len(wantEndpoints) {
+		t.Fatalf("Endpoints = %+v, want %+v", data.Endpoints, wantEndpoints)
+	}
+	for i, e := range wantEndpoints {
+		if data.Endpoints[i] != e {
+			t.Errorf("Endpoints[%d] = %+v, want %+v", i, data.Endpoints[i], e)
+		}
+	}
+}
+
+func TestConnectionsCollector_Collect(t *testing.T) {
+	c := NewConnectionsCollector()
+	if err := c.Configure(config.CollectorConfig{
+		Enabled:    true,
+		Interval:   60 * time.Second,
+		WatchPorts: []int{443},
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metric, err := c.Collect(ctx)
+	if err != nil {
+		t.Skipf("connection table unavailable in this environment: %v", err)
+	}
+	if metric.Type != "Connections" {
+		t.Errorf("Type = %q, want %q", metric.Type, "Connections")
+	}
+	data, ok := metric.Data.(ConnectionsData)
+	if !ok {
+		t.Fatalf("Data is not ConnectionsData")
+	}
+	if len(data.States) < len(tcpStates) {
+		t.Errorf("len(States) = %d, want at least %d", len(data.States), len(tcpStates))
+	}
+	if len(data.Ports) != 1 || data.Ports[0].Port != 443 {
+		t.Errorf("Ports = %+v, want a summary for 443", data.Ports)
+	}
+}
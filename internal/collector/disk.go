@@ -2,7 +2,9 @@ package collector
 
 import (
 	"context"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/disk"
@@ -10,16 +12,36 @@ import (
 	"resourceagent/internal/config"
 )
 
-// DiskCollector collects disk usage and I/O metrics.
+// DiskCollector collects disk usage per partition and I/O rates per
+// physical device. I/O rates are computed from the counter deltas between
+// cycles, like NetworkCollector.
 type DiskCollector struct {
 	BaseCollector
 	disks []string // Specific disks to monitor; empty means all
+
+	mu          sync.Mutex
+	lastIO      map[string]diskIOCounters
+	lastCollect time.Time
+}
+
+// diskIOCounters are the cumulative I/O counters of one physical device,
+// normalized across platforms by readDiskIO. Times are in milliseconds.
+type diskIOCounters struct {
+	readBytes  uint64
+	writeBytes uint64
+	readOps    uint64
+	writeOps   uint64
+	readTime   float64 // Total time spent on completed reads
+	writeTime  float64 // Total time spent on completed writes
+	busyTime   float64 // Time with at least one I/O in flight
+	queueDepth uint64  // I/Os in flight right now (not cumulative)
 }
 
 // NewDiskCollector creates a new disk collector.
 func NewDiskCollector() *DiskCollector {
 	return &DiskCollector{
 		BaseCollector: NewBaseCollector("Disk"),
+		lastIO:        make(map[string]diskIOCounters),
 	}
 }
 
@@ -51,12 +73,18 @@ func (c *DiskCollector) Collect(ctx context.Context) (*MetricData, error) {
 	ioCounters, _ := disk.IOCountersWithContext(ctx) // Ignore error, I/O stats may not be available
 
 	var diskPartitions []DiskPartition
+	parents := make(map[string]bool) // disks behind the configured partitions
 
 	for _, p := range partitions {
 		// Skip if specific disks are configured and this one isn't in the list
 		if len(c.disks) > 0 && !c.shouldInclude(p.Device, p.Mountpoint) {
 			continue
 		}
+		if len(c.disks) > 0 {
+			for _, d := range parentDisks(p.Device) {
+				parents[d] = true
+			}
+		}
 
 		// Skip pseudo filesystems
 		if c.isPseudoFS(p.Fstype) {
@@ -87,7 +115,8 @@ func (c *DiskCollector) Collect(ctx context.Context) (*MetricData, error) {
 			InodesPercent: usage.InodesUsedPercent,
 		}
 
-		// Add I/O stats if available
+		// Add cumulative I/O stats if available (rates are per physical
+		// device, see collectDevices)
 		if ioCounters != nil {
 			deviceName := c.getDeviceName(p.Device)
 			if io, ok := ioCounters[deviceName]; ok {
@@ -103,13 +132,92 @@ func (c *DiskCollector) Collect(ctx context.Context) (*MetricData, error) {
 		diskPartitions = append(diskPartitions, partition)
 	}
 
+	now := time.Now()
+	devices := c.collectDevices(ctx, now, parents)
+
 	return &MetricData{
 		Type:      c.Name(),
-		Timestamp: time.Now(),
-		Data:      DiskData{Partitions: diskPartitions},
+		Timestamp: now,
+		Data:      DiskData{Partitions: diskPartitions, Devices: devices},
 	}, nil
 }
 
+// collectDevices returns I/O rates for physical devices. A device appears
+// from its second sample on; a device whose counters went backwards
+// (driver reload, counter wrap) is skipped for one cycle. parents are the
+// disks behind the partitions and mountpoints selected by Disks.
+func (c *DiskCollector) collectDevices(ctx context.Context, now time.Time, parents map[string]bool) []DiskDevice {
+	counters, err := readDiskIO(ctx)
+	if err != nil {
+		counters = nil // Non-fatal: I/O stats may not be available
+	}
+
+	// Snapshot previous state under lock
+	c.mu.Lock()
+	prevIO := c.lastIO
+	prevCollect := c.lastCollect
+	c.mu.Unlock()
+
+	elapsed := now.Sub(prevCollect).Seconds()
+
+	var devices []DiskDevice
+	newIO := make(map[string]diskIOCounters, len(counters))
+	for name, cur := range counters {
+		if len(c.disks) > 0 && !c.shouldIncludeDevice(name, parents) {
+			continue
+		}
+		newIO[name] = cur
+
+		prev, ok := prevIO[name]
+		if !ok || prevCollect.IsZero() {
+			continue
+		}
+		if dev, ok := diskIORates(name, prev, cur, elapsed); ok {
+			devices = append(devices, dev)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+
+	// Update state under lock
+	c.mu.Lock()
+	c.lastIO = newIO
+	c.lastCollect = now
+	c.mu.Unlock()
+
+	return devices
+}
+
+// diskIORates derives per-second rates, average latency and utilization
+// from two counter snapshots taken elapsed seconds apart. ok is false when
+// elapsed is not positive or a cumulative counter decreased.
+func diskIORates(name string, prev, cur diskIOCounters, elapsed float64) (DiskDevice, bool) {
+	if elapsed <= 0 ||
+		cur.readBytes < prev.readBytes || cur.writeBytes < prev.writeBytes ||
+		cur.readOps < prev.readOps || cur.writeOps < prev.writeOps ||
+		cur.readTime < prev.readTime || cur.writeTime < prev.writeTime ||
+		cur.busyTime < prev.busyTime {
+		return DiskDevice{}, false
+	}
+
+	ops := float64(cur.readOps-prev.readOps) + float64(cur.writeOps-prev.writeOps)
+	dev := DiskDevice{
+		Name:             name,
+		ReadBytesPerSec:  float64(cur.readBytes-prev.readBytes) / elapsed,
+		WriteBytesPerSec: float64(cur.writeBytes-prev.writeBytes) / elapsed,
+		ReadOpsPerSec:    float64(cur.readOps-prev.readOps) / elapsed,
+		WriteOpsPerSec:   float64(cur.writeOps-prev.writeOps) / elapsed,
+		UtilPercent:      (cur.busyTime - prev.busyTime) / (elapsed * 1000) * 100,
+		QueueDepth:       cur.queueDepth,
+	}
+	if ops > 0 {
+		dev.AwaitMs = ((cur.readTime - prev.readTime) + (cur.writeTime - prev.writeTime)) / ops
+	}
+	if dev.UtilPercent > 100 {
+		dev.UtilPercent = 100 // busy time and wall clock are sampled at slightly different instants
+	}
+	return dev, true
+}
+
 func (c *DiskCollector) shouldInclude(device, mountpoint string) bool {
 	for _, d := range c.disks {
 		if d == device || d == mountpoint {
@@ -119,6 +227,22 @@ func (c *DiskCollector) shouldInclude(device, mountpoint string) bool {
 	return false
 }
 
+// shouldIncludeDevice matches a physical device name against Disks. Entries
+// may be bare names ("sda", "PhysicalDrive0") or device paths ("/dev/sda");
+// entries naming a partition or mountpoint ("/dev/sda1", "/", "C:") select
+// their parent disk through parents.
+func (c *DiskCollector) shouldIncludeDevice(name string, parents map[string]bool) bool {
+	if parents[name] {
+		return true
+	}
+	for _, d := range c.disks {
+		if d == name || c.getDeviceName(d) == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *DiskCollector) isPseudoFS(fstype string) bool {
 	pseudoFS := []string{
 		"sysfs", "proc", "devtmpfs", "devpts", "tmpfs", "securityfs",
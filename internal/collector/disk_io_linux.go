@@ -0,0 +1,83 @@
+//go:build linux
+
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// sysBlockPath is the sysfs block device directory (overridden in tests).
+var sysBlockPath = "/sys/block"
+
+// readDiskIO reads /proc/diskstats via gopsutil and keeps whole physical
+// disks only: partitions (sda1) are not listed under /sys/block, and
+// virtual devices (loop, dm-*, md*, zram) have no "device" link there.
+func readDiskIO(ctx context.Context) (map[string]diskIOCounters, error) {
+	stats, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]diskIOCounters, len(stats))
+	for name, s := range stats {
+		if !isPhysicalBlockDevice(sysBlockPath, name) {
+			continue
+		}
+		out[name] = diskIOCounters{
+			readBytes:  s.ReadBytes,
+			writeBytes: s.WriteBytes,
+			readOps:    s.ReadCount,
+			writeOps:   s.WriteCount,
+			readTime:   float64(s.ReadTime),
+			writeTime:  float64(s.WriteTime),
+			busyTime:   float64(s.IoTime),
+			queueDepth: s.IopsInProgress,
+		}
+	}
+	return out, nil
+}
+
+func isPhysicalBlockDevice(root, name string) bool {
+	_, err := os.Stat(filepath.Join(root, name, "device"))
+	return err == nil
+}
+
+// sysClassBlockPath lists every block device, partitions included
+// (overridden in tests).
+var sysClassBlockPath = "/sys/class/block"
+
+// parentDisks returns the physical disks backing a partition device such as
+// "/dev/sda1" or "/dev/mapper/vg-root", for matching Disks entries against
+// device rates. Symlinks are resolved first; partitions map to their disk
+// and device-mapper / md devices to the disks behind their slaves.
+func parentDisks(device string) []string {
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+	return blockParents(sysClassBlockPath, filepath.Base(device), 0)
+}
+
+// blockParents walks sysfs from name down to whole disks. A partition's
+// sysfs directory sits inside its disk's; stacked devices list their
+// members under "slaves". depth guards against unexpected loops.
+func blockParents(root, name string, depth int) []string {
+	dir := filepath.Join(root, name)
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err == nil {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return []string{filepath.Base(filepath.Dir(real))}
+		}
+	}
+	if depth < 8 {
+		if slaves, err := os.ReadDir(filepath.Join(dir, "slaves")); err == nil && len(slaves) > 0 {
+			var out []string
+			for _, s := range slaves {
+				out = append(out, blockParents(root, s.Name(), depth+1)...)
+			}
+			return out
+		}
+	}
+	return []string{name}
+}
@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsPhysicalBlockDevice(t *testing.T) {
+	root := t.TempDir()
+	// Physical disks carry a "device" link; loop/dm devices do not.
+	for _, dir := range []string{"sda/device", "nvme0n1/device", "loop0", "dm-0"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]bool{
+		"sda":       true,
+		"nvme0n1":   true,
+		"sda1":      false, // partitions are not listed under /sys/block
+		"loop0":     false,
+		"dm-0":      false,
+		"nvme0n1p1": false,
+	} {
+		if got := isPhysicalBlockDevice(root, name); got != want {
+			t.Errorf("isPhysicalBlockDevice(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestBlockParents(t *testing.T) {
+	root := t.TempDir()
+	devices := filepath.Join(root, "devices")
+	class := filepath.Join(root, "class")
+	// Real sysfs: /sys/class/block/<name> links into the device tree, where
+	// a partition's directory sits inside its disk's.
+	for _, dir := range []string{"sda/sda1", "nvme0n1/nvme0n1p2", "sdb", "dm-0/slaves"} {
+		if err := os.MkdirAll(filepath.Join(devices, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"sda/sda1/partition", "nvme0n1/nvme0n1p2/partition"} {
+		if err := os.WriteFile(filepath.Join(devices, f), []byte("1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(class, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"sda": "sda", "sda1": "sda/sda1", "nvme0n1": "nvme0n1",
+		"nvme0n1p2": "nvme0n1/nvme0n1p2", "sdb": "sdb", "dm-0": "dm-0",
+	}
+	for name, target := range links {
+		if err := os.Symlink(filepath.Join(devices, target), filepath.Join(class, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// dm-0 (LVM) spans sda1 and sdb.
+	for _, slave := range []string{"sda1", "sdb"} {
+		if err := os.Symlink(filepath.Join(class, slave), filepath.Join(devices, "dm-0/slaves", slave)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"sda1":      "sda",
+		"nvme0n1p2": "nvme0n1",
+		"sda":       "sda",
+		"dm-0":      "sda,sdb",
+		"unknown":   "unknown",
+	} {
+		if got := strings.Join(blockParents(class, name, 0), ","); got != want {
+			t.Errorf("blockParents(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
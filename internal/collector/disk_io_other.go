@@ -0,0 +1,37 @@
+//go:build !windows && !linux
+
+package collector
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// readDiskIO returns gopsutil's per-disk counters. Busy time and queue
+// depth are not available on these platforms and stay zero.
+func readDiskIO(ctx context.Context) (map[string]diskIOCounters, error) {
+	stats, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]diskIOCounters, len(stats))
+	for name, s := range stats {
+		out[name] = diskIOCounters{
+			readBytes:  s.ReadBytes,
+			writeBytes: s.WriteBytes,
+			readOps:    s.ReadCount,
+			writeOps:   s.WriteCount,
+			readTime:   float64(s.ReadTime),
+			writeTime:  float64(s.WriteTime),
+		}
+	}
+	return out, nil
+}
+
+// parentDisks returns the device's base name; partition-to-disk mapping is
+// not implemented on these platforms.
+func parentDisks(device string) []string {
+	return []string{filepath.Base(device)}
+}
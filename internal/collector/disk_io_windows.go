@@ -0,0 +1,143 @@
+//go:build windows
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// IOCTL_DISK_PERFORMANCE returns cumulative counters for a physical drive.
+// gopsutil queries it per drive letter and truncates times to seconds, so
+// the physical drives are queried directly here.
+const ioctlDiskPerformance = 0x70020
+
+// maxPhysicalDrives bounds the \\.\PhysicalDriveN probe. Numbering can have
+// gaps (a removed USB disk), so probing stops only after several misses.
+const (
+	maxPhysicalDrives   = 32
+	maxPhysicalDriveGap = 4
+)
+
+// diskPerformance mirrors DISK_PERFORMANCE (winioctl.h). Times are in
+// 100 ns units.
+type diskPerformance struct {
+	BytesRead           int64
+	BytesWritten        int64
+	ReadTime            int64
+	WriteTime           int64
+	IdleTime            int64
+	ReadCount           uint32
+	WriteCount          uint32
+	QueueDepth          uint32
+	SplitCount          uint32
+	QueryTime           int64
+	StorageDeviceNumber uint32
+	StorageManagerName  [8]uint16
+}
+
+// readDiskIO queries every \\.\PhysicalDriveN. Opening a drive with zero
+// access rights is enough for this IOCTL, so no elevation is needed.
+func readDiskIO(_ context.Context) (map[string]diskIOCounters, error) {
+	out := make(map[string]diskIOCounters)
+	misses := 0
+	for i := 0; i < maxPhysicalDrives && misses < maxPhysicalDriveGap; i++ {
+		name := fmt.Sprintf("PhysicalDrive%d", i)
+		perf, err := queryDiskPerformance(`\\.\` + name)
+		if err != nil {
+			misses++
+			continue
+		}
+		misses = 0
+		out[name] = diskIOCounters{
+			readBytes:  uint64(perf.BytesRead),
+			writeBytes: uint64(perf.BytesWritten),
+			readOps:    uint64(perf.ReadCount),
+			writeOps:   uint64(perf.WriteCount),
+			readTime:   float64(perf.ReadTime) / 1e4,
+			writeTime:  float64(perf.WriteTime) / 1e4,
+			// QueryTime is a timestamp and IdleTime a running total, so
+			// their difference is not a busy total by itself, but its
+			// delta between two samples is the busy time in between.
+			busyTime:   float64(perf.QueryTime-perf.IdleTime) / 1e4,
+			queueDepth: uint64(perf.QueueDepth),
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no physical drive answered IOCTL_DISK_PERFORMANCE")
+	}
+	return out, nil
+}
+
+func queryDiskPerformance(path string) (*diskPerformance, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(p, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(h)
+
+	var perf diskPerformance
+	var n uint32
+	if err := syscall.DeviceIoControl(h, ioctlDiskPerformance, nil, 0,
+		(*byte)(unsafe.Pointer(&perf)), uint32(unsafe.Sizeof(perf)), &n, nil); err != nil {
+		return nil, err
+	}
+	return &perf, nil
+}
+
+// IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS lists the physical drives a volume
+// spans.
+const ioctlVolumeGetVolumeDiskExtents = 0x560000
+
+// volumeDiskExtents mirrors VOLUME_DISK_EXTENTS (winioctl.h) with room for
+// a volume spanning up to 8 drives.
+type volumeDiskExtents struct {
+	NumberOfDiskExtents uint32
+	_                   uint32
+	Extents             [8]struct {
+		DiskNumber     uint32
+		_              uint32
+		StartingOffset int64
+		ExtentLength   int64
+	}
+}
+
+// parentDisks returns the PhysicalDriveN names behind a volume such as
+// "C:". On error the device itself is returned, so only a Disks entry
+// naming it directly can match.
+func parentDisks(device string) []string {
+	fallback := []string{device}
+	p, err := syscall.UTF16PtrFromString(`\\.\` + strings.TrimSuffix(device, `\`))
+	if err != nil {
+		return fallback
+	}
+	h, err := syscall.CreateFile(p, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return fallback
+	}
+	defer syscall.CloseHandle(h)
+
+	var ext volumeDiskExtents
+	var n uint32
+	if err := syscall.DeviceIoControl(h, ioctlVolumeGetVolumeDiskExtents, nil, 0,
+		(*byte)(unsafe.Pointer(&ext)), uint32(unsafe.Sizeof(ext)), &n, nil); err != nil {
+		return fallback
+	}
+	count := int(ext.NumberOfDiskExtents)
+	if count > len(ext.Extents) {
+		count = len(ext.Extents)
+	}
+	out := make([]string, 0, count)
+	for _, e := range ext.Extents[:count] {
+		out = append(out, fmt.Sprintf("PhysicalDrive%d", e.DiskNumber))
+	}
+	return out
+}
@@ -52,3 +52,65 @@ func TestShouldSkipPartition_NormalDisk(t *testing.T) {
 		t.Error("expected partition with total > 0 to NOT be skipped")
 	}
 }
+
+func TestDiskIORates(t *testing.T) {
+	prev := diskIOCounters{readBytes: 1000, writeBytes: 2000, readOps: 10, writeOps: 20, readTime: 50, writeTime: 100, busyTime: 1000}
+	cur := diskIOCounters{readBytes: 21000, writeBytes: 42000, readOps: 30, writeOps: 60, readTime: 110, writeTime: 340, busyTime: 1500, queueDepth: 3}
+
+	dev, ok := diskIORates("sda", prev, cur, 2)
+	if !ok {
+		t.Fatal("diskIORates ok = false")
+	}
+	if dev.ReadBytesPerSec != 10000 || dev.WriteBytesPerSec != 20000 {
+		t.Errorf("throughput = %v/%v, want 10000/20000", dev.ReadBytesPerSec, dev.WriteBytesPerSec)
+	}
+	if dev.ReadOpsPerSec != 10 || dev.WriteOpsPerSec != 20 {
+		t.Errorf("iops = %v/%v, want 10/20", dev.ReadOpsPerSec, dev.WriteOpsPerSec)
+	}
+	// (60 + 240) ms over 60 ops
+	if dev.AwaitMs != 5 {
+		t.Errorf("AwaitMs = %v, want 5", dev.AwaitMs)
+	}
+	// 500 ms busy over 2 s
+	if dev.UtilPercent != 25 {
+		t.Errorf("UtilPercent = %v, want 25", dev.UtilPercent)
+	}
+	if dev.QueueDepth != 3 {
+		t.Errorf("QueueDepth = %d, want 3", dev.QueueDepth)
+	}
+}
+
+func TestDiskIORates_IdleAndInvalid(t *testing.T) {
+	c := diskIOCounters{readOps: 5, busyTime: 100}
+	dev, ok := diskIORates("sda", c, c, 10)
+	if !ok || dev.AwaitMs != 0 || dev.UtilPercent != 0 {
+		t.Errorf("idle device = %+v, %v; want zero await/util", dev, ok)
+	}
+
+	if _, ok := diskIORates("sda", c, diskIOCounters{readOps: 4, busyTime: 100}, 10); ok {
+		t.Error("decreasing counter must be rejected")
+	}
+	if _, ok := diskIORates("sda", c, c, 0); ok {
+		t.Error("zero elapsed must be rejected")
+	}
+
+	over := c
+	over.busyTime += 1200 // 1.2 s busy in a 1 s window
+	if dev, _ := diskIORates("sda", c, over, 1); dev.UtilPercent != 100 {
+		t.Errorf("UtilPercent = %v, want capped at 100", dev.UtilPercent)
+	}
+}
+
+func TestDiskCollector_ShouldIncludeDevice(t *testing.T) {
+	c := &DiskCollector{disks: []string{"/dev/sda", "PhysicalDrive1", "/"}}
+	// "/" is mounted from a partition of nvme0n1.
+	parents := map[string]bool{"nvme0n1": true}
+	for name, want := range map[string]bool{
+		"sda": true, "PhysicalDrive1": true, "nvme0n1": true,
+		"sdb": false, "PhysicalDrive0": false,
+	} {
+		if got := c.shouldIncludeDevice(name, parents); got != want {
+			t.Errorf("shouldIncludeDevice(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -35,6 +35,7 @@ func (c *GpuCollector) Configure(cfg config.CollectorConfig) error {
 		c.SetInterval(cfg.Interval)
 	}
 	c.includeGpus = cfg.IncludeZones // Reuse IncludeZones for GPU filtering
+	c.platformConfigure()
 	return nil
 }
 
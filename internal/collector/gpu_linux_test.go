@@ -0,0 +1,213 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+// fakeDrm builds /sys/class/drm with an amdgpu card (hwmon), an i915 card,
+// an nvidia card (skipped) and a connector entry.
+func fakeDrm(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	driver := func(card, name string) {
+		target := filepath.Join(root, "drivers", name)
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, filepath.Join(root, card, "device", "driver")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("card0/device/gpu_busy_percent", "87")
+	write("card0/device/mem_info_vram_used", "2147483648")
+	write("card0/device/mem_info_vram_total", "8589934592")
+	write("card0/device/product_name", "Radeon Pro W6600")
+	write("card0/device/hwmon/hwmon3/temp1_input", "61000")
+	write("card0/device/hwmon/hwmon3/power1_average", "95000000")
+	write("card0/device/hwmon/hwmon3/fan1_input", "1650")
+	write("card0/device/hwmon/hwmon3/freq1_input", "2480000000")
+	write("card0/device/hwmon/hwmon3/freq2_input", "875000000")
+	driver("card0", "amdgpu")
+
+	write("card1/gt_act_freq_mhz", "1150")
+	write("card1/device/vendor", "0x8086")
+	driver("card1", "i915")
+
+	write("card2/device/vendor", "0x10de")
+	driver("card2", "nvidia")
+
+	write("card0-DP-1/status", "connected")
+
+	orig := sysClassDrmPath
+	sysClassDrmPath = root
+	t.Cleanup(func() { sysClassDrmPath = orig })
+}
+
+func TestReadDrmGpus(t *testing.T) {
+	fakeDrm(t)
+
+	gpus := readDrmGpus()
+	if len(gpus) != 2 {
+		t.Fatalf("gpus = %+v, want amdgpu and i915 cards", gpus)
+	}
+
+	amd := gpus[0]
+	if amd.Name != "Radeon Pro W6600" {
+		t.Errorf("Name = %q, want product_name", amd.Name)
+	}
+	for name, tc := range map[string]struct {
+		got  *float64
+		want float64
+	}{
+		"CoreLoad":    {amd.CoreLoad, 87},
+		"MemoryLoad":  {amd.MemoryLoad, 25},
+		"Temperature": {amd.Temperature, 61},
+		"Power":       {amd.Power, 95},
+		"FanSpeed":    {amd.FanSpeed, 1650},
+		"CoreClock":   {amd.CoreClock, 2480},
+		"MemoryClock": {amd.MemoryClock, 875},
+	} {
+		if tc.got == nil || *tc.got != tc.want {
+			t.Errorf("amdgpu %s = %v, want %v", name, tc.got, tc.want)
+		}
+	}
+
+	intel := gpus[1]
+	if intel.Name != "i915 card1" || intel.CoreClock == nil || *intel.CoreClock != 1150 {
+		t.Errorf("i915 = %+v, want \"i915 card1\" at 1150 MHz", intel)
+	}
+	if intel.CoreLoad != nil || intel.Temperature != nil {
+		t.Errorf("i915 = %+v, want no busy %% or temperature", intel)
+	}
+}
+
+func TestParseNvidiaSmiCSV(t *testing.T) {
+	out := "0, NVIDIA RTX A4000, 54, 37, 4096, 16376, 71.52, 1560, 7000\n" +
+		"1, NVIDIA RTX A4000, 49, [N/A], 0, 16376, [Not Supported], 210, 405\n" +
+		"2, Tesla T4, 40, 0, 0, 0, 27.1, 585, 5000\n" +
+		"garbage line\n"
+
+	gpus := parseNvidiaSmiCSV(out)
+	if len(gpus) != 3 {
+		t.Fatalf("gpus = %+v, want 3", gpus)
+	}
+	if gpus[0].Name != "NVIDIA RTX A4000 #0" || gpus[1].Name != "NVIDIA RTX A4000 #1" || gpus[2].Name != "Tesla T4" {
+		t.Errorf("names = %q, %q, %q, want duplicates suffixed by index", gpus[0].Name, gpus[1].Name, gpus[2].Name)
+	}
+	g := gpus[0]
+	if *g.Temperature != 54 || *g.CoreLoad != 37 || *g.Power != 71.52 || *g.CoreClock != 1560 || *g.MemoryClock != 7000 {
+		t.Errorf("gpu0 = %+v", g)
+	}
+	if g.MemoryLoad == nil || *g.MemoryLoad < 25 || *g.MemoryLoad > 25.02 {
+		t.Errorf("gpu0 MemoryLoad = %v, want ~25%%", g.MemoryLoad)
+	}
+	if g.FanSpeed != nil {
+		t.Error("FanSpeed must stay nil (nvidia-smi reports percent, not RPM)")
+	}
+	if gpus[1].CoreLoad != nil || gpus[1].Power != nil {
+		t.Errorf("gpu1 = %+v, want N/A fields nil", gpus[1])
+	}
+	if gpus[2].MemoryLoad != nil {
+		t.Errorf("gpu2 MemoryLoad = %v, want nil for zero total", gpus[2].MemoryLoad)
+	}
+}
+
+// withMockNvidiaSmi installs a fake nvidia-smi for the duration of the test.
+func withMockNvidiaSmi(t *testing.T, run func(ctx context.Context, path string) ([]byte, error)) {
+	t.Helper()
+	origRun, origPath, origChecked := nvidiaSmiRunFunc, nvidiaSmiPathCached, nvidiaSmiChecked
+	nvidiaSmiRunFunc = run
+	nvidiaSmiPathCached, nvidiaSmiChecked = "/usr/bin/nvidia-smi", true
+	resetNvidiaSmiStateForTest()
+	t.Cleanup(func() {
+		nvidiaSmiRunFunc, nvidiaSmiPathCached, nvidiaSmiChecked = origRun, origPath, origChecked
+		resetNvidiaSmiStateForTest()
+	})
+}
+
+func TestQueryNvidiaSmi_HangServesCache(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	withMockNvidiaSmi(t, func(ctx context.Context, path string) ([]byte, error) {
+		if calls.Add(1) == 1 {
+			return []byte("0, Tesla T4, 40, 5, 100, 1000, 27.1, 585, 5000\n"), nil
+		}
+		<-release
+		return nil, errors.New("killed")
+	})
+
+	gpus, err := queryNvidiaSmi(context.Background())
+	if err != nil || len(gpus) != 1 {
+		t.Fatalf("first query = (%+v, %v), want one GPU", gpus, err)
+	}
+
+	// Second run hangs: the caller times out, the worker stays in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := queryNvidiaSmi(ctx); err == nil {
+		t.Fatal("expected timeout error while nvidia-smi hangs")
+	}
+
+	// Third call must not spawn another run; it gets the cached result.
+	gpus, err = queryNvidiaSmi(context.Background())
+	if err != nil || len(gpus) != 1 || gpus[0].Name != "Tesla T4" {
+		t.Errorf("in-flight query = (%+v, %v), want cached Tesla T4", gpus, err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("nvidia-smi runs = %d, want 2 (no run while one is in flight)", n)
+	}
+
+	// A cache older than nvidiaSmiMaxStale is not served.
+	stale := *loadNvidiaSmiCache()
+	stale.timestamp = time.Now().Add(-2 * nvidiaSmiMaxStale)
+	storeNvidiaSmiCache(&stale)
+	if _, err := queryNvidiaSmi(context.Background()); err == nil {
+		t.Error("expected error instead of a stale cached result")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for nvidiaSmiState.inFlight.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if nvidiaSmiState.inFlight.Load() {
+		t.Error("inFlight not cleared after the hung run returned")
+	}
+}
+
+func TestGpuCollector_Linux(t *testing.T) {
+	fakeDrm(t)
+	withMockNvidiaSmi(t, func(ctx context.Context, path string) ([]byte, error) {
+		return []byte("0, Tesla T4, 40, 5, 100, 1000, 27.1, 585, 5000\n"), nil
+	})
+
+	c := NewGpuCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true, IncludeZones: []string{"Tesla T4", "i915 card1"}}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	gpus, err := c.collectGpuMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("collectGpuMetrics: %v", err)
+	}
+	if len(gpus) != 2 || gpus[0].Name != "i915 card1" || gpus[1].Name != "Tesla T4" {
+		t.Errorf("gpus = %+v, want IncludeZones filter to keep i915 card1 and Tesla T4", gpus)
+	}
+}
@@ -0,0 +1,222 @@
+//go:build linux || darwin
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"resourceagent/internal/logger"
+)
+
+// nvidiaSmiPathCached is resolved during Configure to avoid repeated LookPath calls.
+var nvidiaSmiPathCached string
+var nvidiaSmiChecked bool
+
+// nvidiaSmiQuery is the --query-gpu field list; parseNvidiaSmiCSV relies on
+// this order.
+const nvidiaSmiQuery = "index,name,temperature.gpu,utilization.gpu,memory.used,memory.total,power.draw,clocks.gr,clocks.mem"
+
+const (
+	// nvidiaSmiTimeout bounds a single nvidia-smi run; the process is
+	// killed when it expires.
+	nvidiaSmiTimeout = 10 * time.Second
+	// nvidiaSmiMaxStale is the oldest cached result served while a prior
+	// run is still in flight.
+	nvidiaSmiMaxStale = 5 * time.Minute
+)
+
+// platformConfigure runs platform-specific setup during Configure.
+func (c *GpuCollector) platformConfigure() {
+	if nvidiaSmiChecked {
+		return
+	}
+	nvidiaSmiChecked = true
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		// No NVIDIA driver installed: DRM sysfs is the only source.
+		nvidiaSmiPathCached = ""
+		return
+	}
+	nvidiaSmiPathCached = path
+}
+
+// nvidiaSmiRunFunc is the seam through which queryNvidiaSmi runs
+// nvidia-smi. Tests swap it to simulate hangs without the binary.
+var nvidiaSmiRunFunc = func(ctx context.Context, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, path, "--query-gpu="+nvidiaSmiQuery, "--format=csv,noheader,nounits")
+	return cmd.Output()
+}
+
+// nvidiaSmiStateData tracks one outstanding nvidia-smi run, following the
+// same bounded-leak protocol as the WMI disk query: nvidia-smi can block in
+// the kernel when the driver is wedged, where even SIGKILL does not return
+// until the driver recovers. The first caller spawns the worker; callers
+// arriving while it is in flight get the cached result (if not older than
+// nvidiaSmiMaxStale) instead of spawning another process.
+type nvidiaSmiStateData struct {
+	inFlight      atomic.Bool
+	inFlightSince atomic.Int64 // unix-nanos of the in-flight start
+	cacheMu       sync.RWMutex
+	cache         *nvidiaSmiCacheEntry
+}
+
+type nvidiaSmiCacheEntry struct {
+	gpus      []GpuSensor
+	err       error
+	timestamp time.Time
+}
+
+var nvidiaSmiState nvidiaSmiStateData
+
+// queryNvidiaSmi returns the NVIDIA GPUs reported by nvidia-smi, or nil
+// when nvidia-smi is not installed.
+func queryNvidiaSmi(ctx context.Context) ([]GpuSensor, error) {
+	if nvidiaSmiPathCached == "" {
+		return nil, nil
+	}
+	log := logger.WithComponent("gpu")
+
+	if !nvidiaSmiState.inFlight.CompareAndSwap(false, true) {
+		stuckFor := time.Since(time.Unix(0, nvidiaSmiState.inFlightSince.Load()))
+		log.Warn().
+			Dur("inflight_for", stuckFor).
+			Msg("NVIDIA_SMI_INFLIGHT prior nvidia-smi run still running; serving cached response")
+
+		if cached := loadNvidiaSmiCache(); cached != nil && time.Since(cached.timestamp) < nvidiaSmiMaxStale {
+			return cached.gpus, cached.err
+		}
+		return nil, fmt.Errorf("nvidia-smi already in flight for %v and no recent cached response available", stuckFor)
+	}
+
+	nvidiaSmiState.inFlightSince.Store(time.Now().UnixNano())
+
+	type result struct {
+		gpus []GpuSensor
+		err  error
+	}
+	ch := make(chan result, 1)
+	path := nvidiaSmiPathCached
+
+	go func() {
+		startedAt := time.Now()
+		runCtx, cancel := context.WithTimeout(context.Background(), nvidiaSmiTimeout)
+		out, err := nvidiaSmiRunFunc(runCtx, path)
+		cancel()
+
+		var gpus []GpuSensor
+		if err == nil {
+			gpus = parseNvidiaSmiCSV(string(out))
+		}
+		storeNvidiaSmiCache(&nvidiaSmiCacheEntry{gpus: gpus, err: err, timestamp: time.Now()})
+		// Clear the inflight flag BEFORE sending on ch so a newly arriving
+		// caller observes the cleared state on its next attempt.
+		nvidiaSmiState.inFlight.Store(false)
+
+		if elapsed := time.Since(startedAt); elapsed > nvidiaSmiTimeout {
+			log.Info().
+				Dur("elapsed", elapsed).
+				Bool("query_error", err != nil).
+				Msg("NVIDIA_SMI_RECOVERED long-running nvidia-smi finally returned")
+		}
+
+		ch <- result{gpus, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			log.Warn().Err(r.err).Msg("NVIDIA_SMI_ERROR nvidia-smi failed")
+		}
+		return r.gpus, r.err
+
+	case <-ctx.Done():
+		stuckFor := time.Since(time.Unix(0, nvidiaSmiState.inFlightSince.Load()))
+		log.Warn().
+			Err(ctx.Err()).
+			Dur("inflight_for", stuckFor).
+			Msg("NVIDIA_SMI_TIMEOUT context expired; worker still running in background until nvidia-smi exits")
+		return nil, fmt.Errorf("nvidia-smi timed out: %w", ctx.Err())
+	}
+}
+
+func loadNvidiaSmiCache() *nvidiaSmiCacheEntry {
+	nvidiaSmiState.cacheMu.RLock()
+	defer nvidiaSmiState.cacheMu.RUnlock()
+	return nvidiaSmiState.cache
+}
+
+func storeNvidiaSmiCache(entry *nvidiaSmiCacheEntry) {
+	nvidiaSmiState.cacheMu.Lock()
+	nvidiaSmiState.cache = entry
+	nvidiaSmiState.cacheMu.Unlock()
+}
+
+// resetNvidiaSmiStateForTest restores nvidiaSmiState to a clean state. Tests only.
+func resetNvidiaSmiStateForTest() {
+	nvidiaSmiState.inFlight.Store(false)
+	nvidiaSmiState.inFlightSince.Store(0)
+	storeNvidiaSmiCache(nil)
+}
+
+// parseNvidiaSmiCSV parses "--format=csv,noheader,nounits" output for
+// nvidiaSmiQuery. Fields reported as "[N/A]" or "[Not Supported]" are left
+// nil. Memory load is used/total VRAM, matching LHM's "GPU Memory" load.
+// nvidia-smi reports fan speed only as a percentage, so FanSpeed (RPM) is
+// not set. GPUs sharing a model name get a " #<index>" suffix.
+func parseNvidiaSmiCSV(output string) []GpuSensor {
+	type row struct {
+		index  string
+		sensor GpuSensor
+	}
+	var rows []row
+	names := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 9 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		g := GpuSensor{
+			Name:        fields[1],
+			Temperature: parseNvidiaSmiValue(fields[2]),
+			CoreLoad:    parseNvidiaSmiValue(fields[3]),
+			Power:       parseNvidiaSmiValue(fields[6]),
+			CoreClock:   parseNvidiaSmiValue(fields[7]),
+			MemoryClock: parseNvidiaSmiValue(fields[8]),
+		}
+		used, total := parseNvidiaSmiValue(fields[4]), parseNvidiaSmiValue(fields[5])
+		if used != nil && total != nil && *total > 0 {
+			load := *used / *total * 100
+			g.MemoryLoad = &load
+		}
+		rows = append(rows, row{index: fields[0], sensor: g})
+		names[g.Name]++
+	}
+
+	gpus := make([]GpuSensor, 0, len(rows))
+	for _, r := range rows {
+		if names[r.sensor.Name] > 1 {
+			r.sensor.Name += " #" + r.index
+		}
+		gpus = append(gpus, r.sensor)
+	}
+	return gpus
+}
+
+func parseNvidiaSmiValue(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
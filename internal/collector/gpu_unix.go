@@ -4,12 +4,135 @@ package collector
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
 )
 
-// collectGpuMetrics returns empty data on Linux/Darwin.
-// Future: Could implement nvidia-smi parsing for NVIDIA GPUs on Linux.
+// sysClassDrmPath is the sysfs DRM class directory (overridden in tests).
+var sysClassDrmPath = "/sys/class/drm"
+
+// drmCardRe matches DRM card directories; connectors (card0-DP-1) and
+// render nodes are skipped.
+var drmCardRe = regexp.MustCompile(`^card\d+$`)
+
+// collectGpuMetrics collects GPU metrics on Linux from DRM sysfs (amdgpu,
+// i915 and other kernel drivers) and from nvidia-smi for NVIDIA GPUs.
+// On Darwin (macOS), returns empty as neither source is available.
 func (c *GpuCollector) collectGpuMetrics(ctx context.Context) ([]GpuSensor, error) {
-	// GPU metrics via LHM are only available on Windows
-	// Return nil to indicate no data (not an error)
-	return nil, nil
+	if runtime.GOOS == "darwin" {
+		return nil, nil
+	}
+
+	all := readDrmGpus()
+	nvidia, err := queryNvidiaSmi(ctx)
+	all = append(all, nvidia...)
+	if err != nil && len(all) == 0 {
+		return nil, err
+	}
+
+	var gpus []GpuSensor
+	for _, g := range all {
+		// Skip if specific GPUs are configured and this one isn't in the list
+		if len(c.includeGpus) > 0 && !c.shouldInclude(g.Name) {
+			continue
+		}
+		gpus = append(gpus, g)
+	}
+	return gpus, nil
+}
+
+// readDrmGpus reads /sys/class/drm/card*/device. Cards bound to the
+// proprietary nvidia driver are skipped (nvidia-smi reports them), as are
+// cards without any readable metric (simpledrm, virtual GPUs).
+//
+//   - CoreLoad: device/gpu_busy_percent (amdgpu; i915 does not expose one)
+//   - MemoryLoad: device/mem_info_vram_used / mem_info_vram_total (amdgpu)
+//   - CoreClock: hwmon freq1_input (amdgpu sclk), or gt_act_freq_mhz (i915)
+//   - MemoryClock: hwmon freq2_input (amdgpu mclk)
+//   - Temperature, Power, FanSpeed: hwmon temp1_input, power1_average
+//     (or power1_input), fan1_input
+func readDrmGpus() []GpuSensor {
+	entries, err := os.ReadDir(sysClassDrmPath)
+	if err != nil {
+		return nil
+	}
+
+	var gpus []GpuSensor
+	for _, entry := range entries {
+		card := entry.Name()
+		if !drmCardRe.MatchString(card) {
+			continue
+		}
+		cardPath := filepath.Join(sysClassDrmPath, card)
+		devPath := filepath.Join(cardPath, "device")
+
+		driver := ""
+		if target, err := os.Readlink(filepath.Join(devPath, "driver")); err == nil {
+			driver = filepath.Base(target)
+		}
+		if driver == "" || driver == "nvidia" {
+			continue
+		}
+
+		g := GpuSensor{Name: drmGpuName(devPath, driver, card)}
+		g.CoreLoad = readSysfsValue(filepath.Join(devPath, "gpu_busy_percent"), 1)
+		if used := readSysfsValue(filepath.Join(devPath, "mem_info_vram_used"), 1); used != nil {
+			if total := readSysfsValue(filepath.Join(devPath, "mem_info_vram_total"), 1); total != nil && *total > 0 {
+				load := *used / *total * 100
+				g.MemoryLoad = &load
+			}
+		}
+
+		if hwmons, _ := filepath.Glob(filepath.Join(devPath, "hwmon", "hwmon*")); len(hwmons) > 0 {
+			hw := hwmons[0]
+			g.Temperature = readSysfsValue(filepath.Join(hw, "temp1_input"), 1000)
+			g.Power = readSysfsValue(filepath.Join(hw, "power1_average"), 1e6)
+			if g.Power == nil {
+				g.Power = readSysfsValue(filepath.Join(hw, "power1_input"), 1e6)
+			}
+			g.FanSpeed = readSysfsValue(filepath.Join(hw, "fan1_input"), 1)
+			g.CoreClock = readSysfsValue(filepath.Join(hw, "freq1_input"), 1e6)
+			g.MemoryClock = readSysfsValue(filepath.Join(hw, "freq2_input"), 1e6)
+		}
+		if g.CoreClock == nil {
+			g.CoreClock = readSysfsValue(filepath.Join(cardPath, "gt_act_freq_mhz"), 1)
+		}
+
+		if g.Temperature == nil && g.CoreLoad == nil && g.MemoryLoad == nil && g.FanSpeed == nil &&
+			g.Power == nil && g.CoreClock == nil && g.MemoryClock == nil {
+			continue
+		}
+		gpus = append(gpus, g)
+	}
+	return gpus
+}
+
+// drmGpuName returns device/product_name when the driver provides it
+// (amdgpu on some boards), otherwise "<driver> <card>" (e.g. "i915 card0").
+func drmGpuName(devPath, driver, card string) string {
+	if data, err := os.ReadFile(filepath.Join(devPath, "product_name")); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	return driver + " " + card
+}
+
+// readSysfsValue reads a numeric sysfs attribute and divides it by scale.
+// Returns nil when the file is missing or unreadable.
+func readSysfsValue(path string, scale float64) *float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return nil
+	}
+	v /= scale
+	return &v
 }
@@ -6,6 +6,9 @@ import (
 	"context"
 )
 
+// platformConfigure is a no-op on Windows (GPU data comes from LhmProvider).
+func (c *GpuCollector) platformConfigure() {}
+
 // collectGpuMetrics collects GPU metrics using LibreHardwareMonitor helper.
 // Windows-specific implementation that uses shared LhmProvider for efficiency.
 func (c *GpuCollector) collectGpuMetrics(ctx context.Context) ([]GpuSensor, error) {
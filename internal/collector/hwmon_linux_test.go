@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"resourceagent/internal/config"
+)
+
+// fakeHwmon builds a sysfs-like tree: hwmonN entries are symlinks to device
+// directories, as in /sys/class/hwmon.
+func fakeHwmon(t *testing.T, devices map[string]map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	class := filepath.Join(root, "class", "hwmon")
+	if err := os.MkdirAll(class, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for hwmon, files := range devices {
+		dir := filepath.Join(root, "devices", hwmon)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Symlink(dir, filepath.Join(class, hwmon)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orig := hwmonPath
+	hwmonPath = class
+	t.Cleanup(func() { hwmonPath = orig })
+}
+
+func hwmonTestTree(t *testing.T) {
+	fakeHwmon(t, map[string]map[string]string{
+		"hwmon0": {
+			"name":        "coretemp",
+			"temp1_input": "65000",
+			"temp1_label": "Package id 0",
+		},
+		"hwmon1": {
+			"name":        "nct6798",
+			"in0_input":   "1104",
+			"in0_label":   "Vcore",
+			"in1_input":   "3344",
+			"temp1_input": "38000",
+			"temp1_label": "SYSTIN",
+			"temp2_input": "0", // disconnected
+			"temp3_input": "45500",
+			"fan1_input":  "1200",
+			"fan1_label":  "CPU Fan",
+		},
+		"hwmon2": {
+			"name":        "nvme",
+			"temp1_input": "41850",
+			"temp1_label": "Composite",
+		},
+	})
+}
+
+func TestReadHwmonInputs_Labels(t *testing.T) {
+	hwmonTestTree(t)
+
+	readings, err := readHwmonInputs(context.Background(), "in", "Voltage")
+	if err != nil {
+		t.Fatalf("readHwmonInputs: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, r := range readings {
+		got[r.label] = r.value
+	}
+	if len(got) != 2 || got["Vcore"] != 1104 || got["nct6798 Voltage #1"] != 3344 {
+		t.Errorf("readings = %v, want Vcore and fallback label for in1", got)
+	}
+}
+
+func TestHwmonMissing(t *testing.T) {
+	orig := hwmonPath
+	hwmonPath = filepath.Join(t.TempDir(), "missing")
+	defer func() { hwmonPath = orig }()
+
+	readings, err := readHwmonInputs(context.Background(), "temp", "Temp")
+	if readings != nil || err != nil {
+		t.Errorf("readHwmonInputs = (%v, %v), want (nil, nil) without hwmon", readings, err)
+	}
+}
+
+func TestVoltageCollector_Hwmon(t *testing.T) {
+	hwmonTestTree(t)
+
+	c := NewVoltageCollector()
+	cfg := c.DefaultConfig()
+	if err := c.Configure(cfg); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	sensors, err := c.collectVoltageMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("collectVoltageMetrics: %v", err)
+	}
+	want := map[string]float64{"Vcore": 1.104, "nct6798 Voltage #1": 3.344}
+	if len(sensors) != len(want) {
+		t.Fatalf("sensors = %+v, want %v", sensors, want)
+	}
+	for _, s := range sensors {
+		if want[s.Name] != s.Voltage {
+			t.Errorf("%s = %v V, want %v", s.Name, s.Voltage, want[s.Name])
+		}
+	}
+
+	cfg.IncludeZones = []string{"Vcore"}
+	if err := c.Configure(cfg); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	sensors, _ = c.collectVoltageMetrics(context.Background())
+	if len(sensors) != 1 || sensors[0].Name != "Vcore" {
+		t.Errorf("IncludeZones [Vcore]: sensors = %+v", sensors)
+	}
+}
+
+func TestMotherboardTempCollector_Hwmon(t *testing.T) {
+	hwmonTestTree(t)
+
+	c := NewMotherboardTempCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	sensors, err := c.collectMotherboardTemps(context.Background())
+	if err != nil {
+		t.Fatalf("collectMotherboardTemps: %v", err)
+	}
+	// coretemp (CPU) and nvme (storage) are excluded; temp2 is disconnected.
+	want := map[string]float64{"SYSTIN": 38, "nct6798 Temp #3": 45.5}
+	if len(sensors) != len(want) {
+		t.Fatalf("sensors = %+v, want %v", sensors, want)
+	}
+	for _, s := range sensors {
+		if want[s.Name] != s.Temperature {
+			t.Errorf("%s = %v °C, want %v", s.Name, s.Temperature, want[s.Name])
+		}
+	}
+
+	if err := c.Configure(config.CollectorConfig{Enabled: true, IncludeZones: []string{"SYSTIN"}}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	sensors, _ = c.collectMotherboardTemps(context.Background())
+	if len(sensors) != 1 || sensors[0].Name != "SYSTIN" {
+		t.Errorf("IncludeZones [SYSTIN]: sensors = %+v", sensors)
+	}
+}
+
+func TestFanCollector_Hwmon(t *testing.T) {
+	hwmonTestTree(t)
+
+	c := NewFanCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	sensors, err := c.collectFanSpeeds(context.Background())
+	if err != nil {
+		t.Fatalf("collectFanSpeeds: %v", err)
+	}
+	if len(sensors) != 1 || sensors[0].Name != "CPU Fan" || sensors[0].RPM != 1200 {
+		t.Errorf("sensors = %+v, want CPU Fan 1200 RPM", sensors)
+	}
+}
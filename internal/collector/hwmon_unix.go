@@ -0,0 +1,106 @@
+//go:build linux || darwin
+
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// hwmonPath is the sysfs hwmon class directory (overridden in tests).
+var hwmonPath = "/sys/class/hwmon"
+
+// hwmonReading is one <prefix>N_input value of an hwmon device, in the
+// raw sysfs unit (RPM, millivolts, millidegrees Celsius).
+type hwmonReading struct {
+	device string // hwmon "name" file, e.g. "nct6798"
+	label  string // <prefix>N_label, or "<device> <kind> #N"
+	value  float64
+}
+
+// readHwmonInputs reads every <prefix>*_input file under hwmonPath. kind
+// names the sensor in the fallback label when there is no _label file.
+// On Darwin (macOS), returns empty as sysfs is not available.
+func readHwmonInputs(ctx context.Context, prefix, kind string) ([]hwmonReading, error) {
+	if runtime.GOOS == "darwin" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(hwmonPath)
+	if err != nil {
+		return nil, nil // hwmon may not be available
+	}
+
+	var readings []hwmonReading
+	for _, entry := range entries {
+		// hwmonN entries are symlinks into /sys/devices; os.Stat follows them.
+		devicePath := filepath.Join(hwmonPath, entry.Name())
+		if info, err := os.Stat(devicePath); err != nil || !info.IsDir() {
+			continue
+		}
+		deviceName := getHwmonDeviceName(devicePath)
+
+		inputs, err := filepath.Glob(filepath.Join(devicePath, prefix+"*_input"))
+		if err != nil {
+			continue
+		}
+		for _, input := range inputs {
+			select {
+			case <-ctx.Done():
+				return readings, ctx.Err()
+			default:
+			}
+
+			data, err := os.ReadFile(input)
+			if err != nil {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+			if err != nil {
+				continue
+			}
+			readings = append(readings, hwmonReading{
+				device: deviceName,
+				label:  getHwmonLabel(input, deviceName, prefix, kind),
+				value:  value,
+			})
+		}
+	}
+	return readings, nil
+}
+
+// getHwmonDeviceName reads the device name from hwmon directory.
+func getHwmonDeviceName(devicePath string) string {
+	// Try to read the name file
+	nameFile := filepath.Join(devicePath, "name")
+	data, err := os.ReadFile(nameFile)
+	if err != nil {
+		return filepath.Base(devicePath)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// getHwmonLabel returns the <prefix>N_label content for an input file, or
+// "<device> <kind> #N" when the driver provides no label.
+func getHwmonLabel(inputPath, deviceName, prefix, kind string) string {
+	// Extract sensor number from path (e.g., in3_input -> 3)
+	base := filepath.Base(inputPath)
+	num := strings.TrimSuffix(strings.TrimPrefix(base, prefix), "_input")
+
+	// Try to read the label file (e.g., in3_label)
+	labelFile := strings.TrimSuffix(inputPath, "_input") + "_label"
+	data, err := os.ReadFile(labelFile)
+	if err == nil {
+		label := strings.TrimSpace(string(data))
+		if label != "" {
+			return label
+		}
+	}
+
+	// Fall back to device name + sensor number
+	return deviceName + " " + kind + " #" + num
+}
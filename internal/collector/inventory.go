@@ -0,0 +1,331 @@
+package collector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+// defaultInventoryStateFile keeps the last snapshot the sender accepted, the
+// baseline for the change list after a restart.
+const defaultInventoryStateFile = "log/ResourceAgent/inventory_state.json"
+
+// AgentVersion is the agent build version reported in the inventory.
+// main sets it from the version injected by ldflags.
+var AgentVersion = "dev"
+
+// InventoryCollector reports the hardware and OS inventory: CPU, installed
+// memory, fixed disks, physical NICs, OS, BIOS/board and the agent version.
+// The scheduler collects once at startup and then every Interval (default
+// hourly). Checking hourly rather than daily reports a swapped disk or NIC
+// within the hour; it costs only a local gather, because a snapshot is sent
+// only when its content hash differs from the last one sent, together with
+// the list of changes (disk swapped, NIC replaced, OS updated). An unchanged
+// inventory, the normal case, returns nil and sends nothing. A snapshot becomes the last one sent, and is saved
+// to StateFile, only when the scheduler reports it was accepted by the
+// sender (Sent), so a failed send is retried with the same changes on the
+// next cycle and a restart does not resend an unchanged inventory.
+type InventoryCollector struct {
+	BaseCollector
+
+	mu        sync.Mutex
+	statePath string
+	last      *InventoryData // last sent snapshot, nil before the first
+	pending   *InventoryData // returned by Collect, not yet Sent
+	loaded    bool           // last restored from statePath
+	gather    func(ctx context.Context) (InventoryData, error)
+}
+
+// NewInventoryCollector creates a new inventory collector.
+func NewInventoryCollector() *InventoryCollector {
+	return &InventoryCollector{
+		BaseCollector: NewBaseCollector("Inventory"),
+		statePath:     defaultInventoryStateFile,
+		gather:        gatherInventory,
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the inventory collector.
+func (c *InventoryCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = time.Hour
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *InventoryCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	statePath := cfg.StateFile
+	if statePath == "" {
+		statePath = defaultInventoryStateFile
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if statePath != c.statePath {
+		c.statePath = statePath
+		c.loaded = false
+	}
+	return nil
+}
+
+// Collect gathers the inventory and returns it when it changed since the
+// last snapshot sent. Returns nil when nothing changed.
+func (c *InventoryCollector) Collect(ctx context.Context) (*MetricData, error) {
+	inv, err := c.gather(ctx)
+	if err != nil {
+		// Never diff a partial inventory: a failed disk query would
+		// otherwise report every disk as removed.
+		return nil, err
+	}
+	inv.Hash = inventoryHash(inv)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log := logger.WithComponent("collector")
+	if !c.loaded {
+		var last InventoryData
+		if err := loadJSONState(c.statePath, &last); err != nil {
+			log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).
+				Msg("Failed to load inventory state, sending full snapshot")
+		}
+		c.last = nil
+		if last.Hash != "" {
+			c.last = &last
+		}
+		c.loaded = true
+	}
+
+	if c.last != nil && c.last.Hash == inv.Hash {
+		return nil, nil
+	}
+	if c.last != nil {
+		inv.Changes = diffInventory(*c.last, inv)
+		logInventoryChanges(inv.Changes)
+	}
+
+	snapshot := inv
+	snapshot.Changes = nil
+	c.pending = &snapshot
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      inv,
+	}, nil
+}
+
+// Sent records data, returned by the latest Collect, as the last snapshot
+// sent and saves it to StateFile.
+func (c *InventoryCollector) Sent(data *MetricData) {
+	inv, ok := data.Data.(InventoryData)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil || c.pending.Hash != inv.Hash {
+		return
+	}
+	c.last, c.pending = c.pending, nil
+	if err := saveJSONState(c.statePath, c.last); err != nil {
+		log := logger.WithComponent("collector")
+		log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).Msg("Failed to save inventory state")
+	}
+}
+
+// gatherInventory builds a snapshot from the portable sources (memory, OS)
+// and the platform sources (CPU, disks, NICs, board). Lists are sorted so
+// the hash only changes with the content.
+func gatherInventory(ctx context.Context) (InventoryData, error) {
+	inv := InventoryData{AgentVersion: AgentVersion}
+
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return inv, fmt.Errorf("inventory: memory: %w", err)
+	}
+	inv.MemoryTotalBytes = vm.Total
+
+	hi, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return inv, fmt.Errorf("inventory: host info: %w", err)
+	}
+	inv.OS = InventoryOS{
+		Name:    hi.Platform,
+		Version: hi.PlatformVersion,
+		Kernel:  hi.KernelVersion,
+		Arch:    hi.KernelArch,
+	}
+
+	if err := collectPlatformInventory(ctx, &inv); err != nil {
+		return inv, fmt.Errorf("inventory: %w", err)
+	}
+
+	if inv.Disks == nil {
+		inv.Disks = []InventoryDisk{}
+	}
+	if inv.NICs == nil {
+		inv.NICs = []InventoryNIC{}
+	}
+	sort.Slice(inv.Disks, func(i, j int) bool { return inv.Disks[i].Name < inv.Disks[j].Name })
+	sort.Slice(inv.NICs, func(i, j int) bool { return inv.NICs[i].Name < inv.NICs[j].Name })
+	return inv, nil
+}
+
+// listInventoryNICs returns the interfaces with a MAC address that are not
+// loopback and pass isPhysical.
+func listInventoryNICs(isPhysical func(name string) bool) ([]InventoryNIC, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var nics []InventoryNIC
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if !isPhysical(iface.Name) {
+			continue
+		}
+		nics = append(nics, InventoryNIC{Name: iface.Name, MAC: iface.HardwareAddr.String()})
+	}
+	return nics, nil
+}
+
+// inventoryHash returns the SHA-256 of the snapshot content, excluding the
+// hash itself and the change list.
+func inventoryHash(inv InventoryData) string {
+	inv.Hash = ""
+	inv.Changes = nil
+	b, _ := json.Marshal(inv) // plain structs: cannot fail
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// diffInventory lists the differences between two snapshots. Disks are
+// matched by serial number (model and name when the serial is unknown) and
+// NICs by MAC address, so a replaced part shows up as removed + added.
+func diffInventory(old, cur InventoryData) []InventoryChange {
+	var changes []InventoryChange
+	scalar := func(component, o, n string) {
+		if o != n {
+			changes = append(changes, InventoryChange{Component: component, Type: "changed", Old: o, New: n})
+		}
+	}
+	scalar("cpu", old.CPU.label(), cur.CPU.label())
+	scalar("memory", strconv.FormatUint(old.MemoryTotalBytes, 10), strconv.FormatUint(cur.MemoryTotalBytes, 10))
+
+	oldDisks := make(map[string]string, len(old.Disks))
+	for _, d := range old.Disks {
+		oldDisks[d.key()] = d.label()
+	}
+	curDisks := make(map[string]string, len(cur.Disks))
+	for _, d := range cur.Disks {
+		curDisks[d.key()] = d.label()
+	}
+	changes = append(changes, diffSet("disk", oldDisks, curDisks)...)
+
+	oldNICs := make(map[string]string, len(old.NICs))
+	for _, n := range old.NICs {
+		oldNICs[n.MAC] = n.label()
+	}
+	curNICs := make(map[string]string, len(cur.NICs))
+	for _, n := range cur.NICs {
+		curNICs[n.MAC] = n.label()
+	}
+	changes = append(changes, diffSet("nic", oldNICs, curNICs)...)
+
+	scalar("os", old.OS.label(), cur.OS.label())
+	scalar("board", old.Board.label(), cur.Board.label())
+	scalar("agent", old.AgentVersion, cur.AgentVersion)
+	return changes
+}
+
+// diffSet reports keys only in old as removed and keys only in cur as added,
+// sorted by label.
+func diffSet(component string, old, cur map[string]string) []InventoryChange {
+	var removed, added []InventoryChange
+	for k, label := range old {
+		if _, ok := cur[k]; !ok {
+			removed = append(removed, InventoryChange{Component: component, Type: "removed", Old: label})
+		}
+	}
+	for k, label := range cur {
+		if _, ok := old[k]; !ok {
+			added = append(added, InventoryChange{Component: component, Type: "added", New: label})
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Old < removed[j].Old })
+	sort.Slice(added, func(i, j int) bool { return added[i].New < added[j].New })
+	return append(removed, added...)
+}
+
+func (c InventoryCPU) label() string {
+	return fmt.Sprintf("%s (%d cores, %d threads)", c.Model, c.Cores, c.LogicalCores)
+}
+
+func (d InventoryDisk) key() string {
+	if d.Serial != "" {
+		return "serial:" + d.Serial
+	}
+	return "name:" + d.Name + "/" + d.Model
+}
+
+// label identifies the disk as "model@serial", or the model alone when the
+// serial is unknown.
+func (d InventoryDisk) label() string {
+	if d.Serial == "" {
+		return d.Model
+	}
+	return d.Model + "@" + d.Serial
+}
+
+// label identifies the NIC as "name@mac".
+func (n InventoryNIC) label() string {
+	return n.Name + "@" + n.MAC
+}
+
+func (o InventoryOS) label() string {
+	return joinNonEmpty(o.Name, o.Version, o.Kernel, o.Arch)
+}
+
+func (b InventoryBoard) label() string {
+	return joinNonEmpty(b.BIOSVendor, b.BIOSVersion, b.BoardVendor, b.BoardProduct, b.SystemVendor, b.SystemProduct)
+}
+
+func joinNonEmpty(parts ...string) string {
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func logInventoryChanges(changes []InventoryChange) {
+	log := logger.WithComponent("inventory")
+	for _, ch := range changes {
+		log.Warn().Str("component", ch.Component).Str("type", ch.Type).
+			Str("old", ch.Old).Str("new", ch.New).
+			Msg("INVENTORY_CHANGED: hardware/OS inventory changed")
+	}
+}
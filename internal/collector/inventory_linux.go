@@ -0,0 +1,103 @@
+//go:build linux
+
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// dmiIDPath is the sysfs DMI identification directory (overridden in tests).
+var dmiIDPath = "/sys/class/dmi/id"
+
+// collectPlatformInventory fills CPU, disks, NICs and board on Linux:
+//   - CPU from /proc/cpuinfo (gopsutil)
+//   - disks from /sys/block: devices backed by hardware (device link) and not
+//     removable, with model, serial and size from sysfs; the serial falls
+//     back to the udev database when sysfs has none (SATA)
+//   - NICs backed by hardware (/sys/class/net/<if>/device)
+//   - BIOS/board from /sys/class/dmi/id (absent on most ARM boards)
+func collectPlatformInventory(ctx context.Context, inv *InventoryData) error {
+	infos, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(infos) > 0 {
+		inv.CPU.Model = strings.TrimSpace(infos[0].ModelName)
+	}
+	if inv.CPU.Cores, err = cpu.CountsWithContext(ctx, false); err != nil {
+		return err
+	}
+	if inv.CPU.LogicalCores, err = cpu.CountsWithContext(ctx, true); err != nil {
+		return err
+	}
+
+	if inv.Disks, err = readInventoryDisks(ctx); err != nil {
+		return err
+	}
+	inv.NICs, err = listInventoryNICs(func(name string) bool {
+		_, err := os.Stat(filepath.Join(sysClassNetPath, name, "device"))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	inv.Board = InventoryBoard{
+		BIOSVendor:    readDMI("bios_vendor"),
+		BIOSVersion:   readDMI("bios_version"),
+		BoardVendor:   readDMI("board_vendor"),
+		BoardProduct:  readDMI("board_name"),
+		SystemVendor:  readDMI("sys_vendor"),
+		SystemProduct: readDMI("product_name"),
+	}
+	return nil
+}
+
+func readInventoryDisks(ctx context.Context) ([]InventoryDisk, error) {
+	entries, err := os.ReadDir(sysBlockPath)
+	if err != nil {
+		return nil, err
+	}
+	var disks []InventoryDisk
+	for _, e := range entries {
+		name := e.Name()
+		if !isPhysicalBlockDevice(sysBlockPath, name) || readSysfsString(filepath.Join(sysBlockPath, name, "removable")) == "1" {
+			continue
+		}
+		d := InventoryDisk{
+			Name:   name,
+			Model:  readSysfsString(filepath.Join(sysBlockPath, name, "device", "model")),
+			Serial: readSysfsString(filepath.Join(sysBlockPath, name, "device", "serial")),
+		}
+		if d.Serial == "" {
+			d.Serial, _ = disk.SerialNumberWithContext(ctx, "/dev/"+name)
+			d.Serial = strings.TrimSpace(d.Serial)
+		}
+		if sectors, err := strconv.ParseUint(readSysfsString(filepath.Join(sysBlockPath, name, "size")), 10, 64); err == nil {
+			d.SizeBytes = sectors * 512 // always 512-byte units, whatever the logical block size
+		}
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
+
+func readDMI(name string) string {
+	return readSysfsString(filepath.Join(dmiIDPath, name))
+}
+
+// readSysfsString returns the trimmed content of a sysfs attribute, or ""
+// when it cannot be read.
+func readSysfsString(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
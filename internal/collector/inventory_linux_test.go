@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadInventoryDisks(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("nvme0n1/device/model", "Samsung SSD 970 EVO Plus 1TB            \n")
+	write("nvme0n1/device/serial", "  S4EWNX0R123456\n")
+	write("nvme0n1/size", "1953525168\n")
+	write("nvme0n1/removable", "0\n")
+	write("sdb/device/model", "Cruzer Blade\n")
+	write("sdb/removable", "1\n")
+	write("loop0/size", "8\n") // no device link: virtual
+
+	orig := sysBlockPath
+	sysBlockPath = root
+	defer func() { sysBlockPath = orig }()
+
+	disks, err := readInventoryDisks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disks) != 1 {
+		t.Fatalf("disks = %+v, want only nvme0n1", disks)
+	}
+	want := InventoryDisk{Name: "nvme0n1", Model: "Samsung SSD 970 EVO Plus 1TB", Serial: "S4EWNX0R123456", SizeBytes: 1953525168 * 512}
+	if disks[0] != want {
+		t.Errorf("disk = %+v, want %+v", disks[0], want)
+	}
+}
+
+func TestReadDMI(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "board_vendor"), []byte("ASUSTeK COMPUTER INC.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	orig := dmiIDPath
+	dmiIDPath = root
+	defer func() { dmiIDPath = orig }()
+
+	if got := readDMI("board_vendor"); got != "ASUSTeK COMPUTER INC." {
+		t.Errorf("readDMI(board_vendor) = %q", got)
+	}
+	if got := readDMI("board_name"); got != "" {
+		t.Errorf("readDMI(missing) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !windows && !linux
+
+package collector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// collectPlatformInventory fills CPU and NICs on platforms without a disk or
+// board source (macOS); disks and board stay empty.
+func collectPlatformInventory(ctx context.Context, inv *InventoryData) error {
+	infos, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(infos) > 0 {
+		inv.CPU.Model = strings.TrimSpace(infos[0].ModelName)
+	}
+	if inv.CPU.Cores, err = cpu.CountsWithContext(ctx, false); err != nil {
+		return err
+	}
+	if inv.CPU.LogicalCores, err = cpu.CountsWithContext(ctx, true); err != nil {
+		return err
+	}
+
+	inv.NICs, err = listInventoryNICs(func(name string) bool {
+		for _, p := range []string{"awdl", "llw", "bridge", "anpi", "ap", "utun"} {
+			if strings.HasPrefix(name, p) {
+				return false // macOS virtual interfaces
+			}
+		}
+		return true
+	})
+	return err
+}
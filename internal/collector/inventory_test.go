@@ -0,0 +1,177 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"resourceagent/internal/config"
+)
+
+func testInventory() InventoryData {
+	return InventoryData{
+		AgentVersion:     "1.4.0",
+		CPU:              InventoryCPU{Model: "Intel(R) Core(TM) i5-8500", Cores: 6, LogicalCores: 6},
+		MemoryTotalBytes: 16 << 30,
+		Disks: []InventoryDisk{
+			{Name: "sda", Model: "Samsung SSD 860", Serial: "S3Z1", SizeBytes: 500 << 30},
+			{Name: "sdb", Model: "WDC WD10EZEX", Serial: "WX11", SizeBytes: 1 << 40},
+		},
+		NICs: []InventoryNIC{{Name: "eth0", MAC: "00:1a:2b:3c:4d:5e"}},
+		OS:   InventoryOS{Name: "ubuntu", Version: "22.04", Kernel: "5.15.0-91-generic", Arch: "x86_64"},
+	}
+}
+
+func newTestInventory(t *testing.T, statePath string, gather func() (InventoryData, error)) *InventoryCollector {
+	t.Helper()
+	c := NewInventoryCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true, StateFile: statePath}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	c.gather = func(context.Context) (InventoryData, error) { return gather() }
+	return c
+}
+
+// collectInventory runs one cycle and, like the scheduler after a
+// successful send, reports the result as Sent.
+func collectInventory(t *testing.T, c *InventoryCollector) *InventoryData {
+	t.Helper()
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if m == nil {
+		return nil
+	}
+	c.Sent(m)
+	d, ok := m.Data.(InventoryData)
+	if !ok {
+		t.Fatalf("Data = %T, want InventoryData", m.Data)
+	}
+	return &d
+}
+
+func TestInventoryHash(t *testing.T) {
+	a := testInventory()
+	b := testInventory()
+	b.Changes = []InventoryChange{{Component: "agent", Type: "changed"}}
+	b.Hash = "stale"
+	if inventoryHash(a) != inventoryHash(b) {
+		t.Error("hash depends on Hash/Changes, want content only")
+	}
+	b.Disks[1].Serial = "WX22"
+	if inventoryHash(a) == inventoryHash(b) {
+		t.Error("hash unchanged after a disk serial change")
+	}
+}
+
+func TestDiffInventory(t *testing.T) {
+	old := testInventory()
+	cur := testInventory()
+	cur.Disks[1] = InventoryDisk{Name: "sdb", Model: "WDC WD10EZEX", Serial: "WX99", SizeBytes: 1 << 40}
+	cur.NICs[0].Name = "enp3s0" // renamed, same MAC: not a change
+	cur.NICs = append(cur.NICs, InventoryNIC{Name: "enp4s0", MAC: "00:1a:2b:3c:4d:60"})
+	cur.OS.Kernel = "5.15.0-94-generic"
+	cur.AgentVersion = "1.5.0"
+
+	want := []InventoryChange{
+		{Component: "disk", Type: "removed", Old: "WDC WD10EZEX@WX11"},
+		{Component: "disk", Type: "added", New: "WDC WD10EZEX@WX99"},
+		{Component: "nic", Type: "added", New: "enp4s0@00:1a:2b:3c:4d:60"},
+		{Component: "os", Type: "changed", Old: "ubuntu 22.04 5.15.0-91-generic x86_64", New: "ubuntu 22.04 5.15.0-94-generic x86_64"},
+		{Component: "agent", Type: "changed", Old: "1.4.0", New: "1.5.0"},
+	}
+	if got := diffInventory(old, cur); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffInventory =\n  %+v\nwant\n  %+v", got, want)
+	}
+}
+
+func TestInventory_SendsOnlyOnChange(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "inventory.json")
+	inv := testInventory()
+	c := newTestInventory(t, statePath, func() (InventoryData, error) { return inv, nil })
+
+	first := collectInventory(t, c)
+	if first == nil || first.Hash == "" || len(first.Changes) != 0 {
+		t.Fatalf("first snapshot = %+v, want full snapshot without changes", first)
+	}
+	if d := collectInventory(t, c); d != nil {
+		t.Errorf("unchanged inventory sent again: %+v", d)
+	}
+
+	// A restart with unchanged hardware sends nothing either.
+	restarted := newTestInventory(t, statePath, func() (InventoryData, error) { return inv, nil })
+	if d := collectInventory(t, restarted); d != nil {
+		t.Errorf("unchanged inventory sent after restart: %+v", d)
+	}
+
+	inv.NICs[0].MAC = "00:1a:2b:3c:4d:99"
+	d := collectInventory(t, restarted)
+	if d == nil || len(d.Changes) != 2 || d.Changes[0].Type != "removed" || d.Changes[1].Type != "added" {
+		t.Fatalf("after NIC swap = %+v, want removed + added", d)
+	}
+	if d.Hash == first.Hash {
+		t.Error("hash unchanged after NIC swap")
+	}
+}
+
+func TestInventory_GatherErrorKeepsState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "inventory.json")
+	inv := testInventory()
+	fail := false
+	c := newTestInventory(t, statePath, func() (InventoryData, error) {
+		if fail {
+			return InventoryData{}, errors.New("WMI timed out")
+		}
+		return inv, nil
+	})
+	collectInventory(t, c)
+
+	fail = true
+	if m, err := c.Collect(context.Background()); m != nil || err == nil {
+		t.Fatalf("Collect() = (%v, %v), want error", m, err)
+	}
+	fail = false
+	if d := collectInventory(t, c); d != nil {
+		t.Errorf("inventory sent after a failed cycle with unchanged hardware: %+v", d)
+	}
+}
+
+func TestInventory_UnsentSnapshotIsResent(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "inventory.json")
+	inv := testInventory()
+	gather := func() (InventoryData, error) {
+		cp := inv
+		cp.Disks = append([]InventoryDisk(nil), inv.Disks...)
+		return cp, nil
+	}
+	c := newTestInventory(t, statePath, gather)
+	collectInventory(t, c)
+
+	// The disk swap is collected but the send fails: no Sent.
+	inv.Disks[1].Serial = "WX99"
+	if m, err := c.Collect(context.Background()); m == nil || err != nil {
+		t.Fatalf("Collect() = (%v, %v), want the changed snapshot", m, err)
+	}
+
+	// Neither the next cycle nor a restart may treat it as sent.
+	d := collectInventory(t, c)
+	if d == nil || len(d.Changes) != 2 {
+		t.Fatalf("next cycle = %+v, want the disk swap again", d)
+	}
+	restarted := newTestInventory(t, statePath, gather)
+	if d := collectInventory(t, restarted); d != nil {
+		t.Errorf("snapshot sent again after restart although it was Sent: %+v", d)
+	}
+
+	inv.Disks[0].Serial = "S3Z9"
+	if m, err := restarted.Collect(context.Background()); m == nil || err != nil {
+		t.Fatalf("Collect() = (%v, %v), want the changed snapshot", m, err)
+	}
+	again := newTestInventory(t, statePath, gather)
+	if d := collectInventory(t, again); d == nil || len(d.Changes) != 2 {
+		t.Errorf("after restart with an unsent change = %+v, want it resent", d)
+	}
+}
@@ -0,0 +1,174 @@
+//go:build windows
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"resourceagent/internal/logger"
+)
+
+type win32InventoryProcessor struct {
+	Name                      string
+	NumberOfCores             uint32
+	NumberOfLogicalProcessors uint32
+}
+
+type win32InventoryDisk struct {
+	Index        uint32
+	Model        string
+	SerialNumber string
+	Size         uint64
+	MediaType    string
+}
+
+type win32InventoryNIC struct {
+	NetConnectionID string
+	MACAddress      string
+}
+
+type win32InventoryBIOS struct {
+	Manufacturer      string
+	SMBIOSBIOSVersion string
+}
+
+type win32InventoryBaseBoard struct {
+	Manufacturer string
+	Product      string
+}
+
+type win32InventoryComputerSystem struct {
+	Manufacturer string
+	Model        string
+}
+
+// inventoryWMIState guards the inventory WMI worker like wmiQueryStateData
+// does for the disk-drive query: at most one worker is ever outstanding, so
+// a hung WMI service leaks one goroutine rather than one per cycle. No
+// response is cached; while the worker is stuck the cycle fails and the
+// inventory is retried next interval.
+var inventoryWMIState struct {
+	inFlight      atomic.Bool
+	inFlightSince atomic.Int64 // unix-nanos of the in-flight start
+}
+
+// collectPlatformInventory fills CPU, disks, NICs and board on Windows from
+// WMI: Win32_Processor, Win32_DiskDrive (removable media excluded),
+// Win32_NetworkAdapter (PhysicalAdapter only, which drops Hyper-V, VPN and
+// loopback adapters), Win32_BIOS, Win32_BaseBoard and Win32_ComputerSystem.
+// All queries run in one worker; any failure fails the whole cycle.
+func collectPlatformInventory(ctx context.Context, inv *InventoryData) error {
+	log := logger.WithComponent("inventory")
+
+	if !inventoryWMIState.inFlight.CompareAndSwap(false, true) {
+		stuckFor := time.Since(time.Unix(0, inventoryWMIState.inFlightSince.Load()))
+		log.Warn().Dur("inflight_for", stuckFor).
+			Msg("INVENTORY_WMI_INFLIGHT prior inventory WMI query still running; skipping this cycle")
+		return fmt.Errorf("inventory WMI query already in flight for %v", stuckFor)
+	}
+	inventoryWMIState.inFlightSince.Store(time.Now().UnixNano())
+
+	type result struct {
+		inv InventoryData
+		err error
+	}
+	ch := make(chan result, 1)
+
+	go func() {
+		startedAt := time.Now()
+		var r result
+		r.err = queryInventoryWMI(&r.inv)
+		inventoryWMIState.inFlight.Store(false)
+		if elapsed := time.Since(startedAt); elapsed > 5*time.Second {
+			log.Info().Dur("elapsed", elapsed).Bool("query_error", r.err != nil).
+				Msg("INVENTORY_WMI_RECOVERED long-running inventory WMI query finally returned")
+		}
+		ch <- r
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			log.Warn().Err(r.err).Msg("INVENTORY_WMI_ERROR wmi.Query returned error")
+			return r.err
+		}
+		inv.CPU, inv.Disks, inv.NICs, inv.Board = r.inv.CPU, r.inv.Disks, r.inv.NICs, r.inv.Board
+		return nil
+	case <-ctx.Done():
+		log.Warn().Err(ctx.Err()).
+			Msg("INVENTORY_WMI_TIMEOUT context expired; worker still running in background until WMI responds")
+		return fmt.Errorf("inventory WMI query timed out: %w", ctx.Err())
+	}
+}
+
+// queryInventoryWMI runs the inventory WMI queries through wmiQueryFunc.
+func queryInventoryWMI(inv *InventoryData) error {
+	var procs []win32InventoryProcessor
+	if err := wmiQueryFunc("SELECT Name, NumberOfCores, NumberOfLogicalProcessors FROM Win32_Processor", &procs); err != nil {
+		return fmt.Errorf("Win32_Processor: %w", err)
+	}
+	for i, p := range procs {
+		if i == 0 {
+			inv.CPU.Model = strings.TrimSpace(p.Name)
+		}
+		inv.CPU.Cores += int(p.NumberOfCores)
+		inv.CPU.LogicalCores += int(p.NumberOfLogicalProcessors)
+	}
+
+	var disks []win32InventoryDisk
+	if err := wmiQueryFunc("SELECT Index, Model, SerialNumber, Size, MediaType FROM Win32_DiskDrive", &disks); err != nil {
+		return fmt.Errorf("Win32_DiskDrive: %w", err)
+	}
+	for _, d := range disks {
+		if isRemovableMedia(d.MediaType) {
+			continue
+		}
+		inv.Disks = append(inv.Disks, InventoryDisk{
+			Name:      fmt.Sprintf("PhysicalDrive%d", d.Index),
+			Model:     strings.TrimSpace(d.Model),
+			Serial:    strings.TrimSpace(d.SerialNumber),
+			SizeBytes: d.Size,
+		})
+	}
+
+	var nics []win32InventoryNIC
+	if err := wmiQueryFunc("SELECT NetConnectionID, MACAddress FROM Win32_NetworkAdapter WHERE PhysicalAdapter = TRUE AND MACAddress IS NOT NULL", &nics); err != nil {
+		return fmt.Errorf("Win32_NetworkAdapter: %w", err)
+	}
+	for _, n := range nics {
+		if n.NetConnectionID == "" {
+			continue // not bound to a connection (kernel debug, hidden adapters)
+		}
+		inv.NICs = append(inv.NICs, InventoryNIC{Name: n.NetConnectionID, MAC: strings.ToLower(n.MACAddress)})
+	}
+
+	var bios []win32InventoryBIOS
+	if err := wmiQueryFunc("SELECT Manufacturer, SMBIOSBIOSVersion FROM Win32_BIOS", &bios); err != nil {
+		return fmt.Errorf("Win32_BIOS: %w", err)
+	}
+	var boards []win32InventoryBaseBoard
+	if err := wmiQueryFunc("SELECT Manufacturer, Product FROM Win32_BaseBoard", &boards); err != nil {
+		return fmt.Errorf("Win32_BaseBoard: %w", err)
+	}
+	var systems []win32InventoryComputerSystem
+	if err := wmiQueryFunc("SELECT Manufacturer, Model FROM Win32_ComputerSystem", &systems); err != nil {
+		return fmt.Errorf("Win32_ComputerSystem: %w", err)
+	}
+	if len(bios) > 0 {
+		inv.Board.BIOSVendor = strings.TrimSpace(bios[0].Manufacturer)
+		inv.Board.BIOSVersion = strings.TrimSpace(bios[0].SMBIOSBIOSVersion)
+	}
+	if len(boards) > 0 {
+		inv.Board.BoardVendor = strings.TrimSpace(boards[0].Manufacturer)
+		inv.Board.BoardProduct = strings.TrimSpace(boards[0].Product)
+	}
+	if len(systems) > 0 {
+		inv.Board.SystemVendor = strings.TrimSpace(systems[0].Manufacturer)
+		inv.Board.SystemProduct = strings.TrimSpace(systems[0].Model)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	// defaultLogWatchStateFile holds each file's offset and fingerprint so
+	// a restart resumes tailing instead of rereading or skipping lines.
+	defaultLogWatchStateFile = "log/ResourceAgent/logwatch_state.json"
+	// logWatchFingerprintBytes is how much of a file's head identifies it.
+	logWatchFingerprintBytes = 1024
+	// logWatchMaxReadBytes bounds the bytes read from one file per cycle so a
+	// burst of logging cannot stall the collector; the rest is read next cycle.
+	logWatchMaxReadBytes = 8 << 20
+	// logWatchMaxLineBytes truncates forwarded event lines.
+	logWatchMaxLineBytes = 512
+)
+
+// LogWatchCollector tails application log files and counts the lines
+// matching configured patterns per interval. Files are opened, read and
+// closed every cycle (never held open, so vendor applications can still
+// rotate them on Windows). Each file's read position is tracked by offset
+// and a CRC-32 fingerprint of its first bytes:
+//   - fingerprint changed: the file was rotated (replaced), read from the start
+//   - size below the offset: the file was truncated, read from the start
+//
+// Offsets advance only once the cycle's data has been sent (Sent): a cycle
+// whose send failed is read again from the last sent offsets next time, so
+// its lines are counted and forwarded late rather than lost. Sent offsets
+// are saved to StateFile so a restart resumes where it left off. Files that
+// exist when the agent starts without saved state are read from the end;
+// files appearing later are read from the start.
+type LogWatchCollector struct {
+	BaseCollector
+
+	mu          sync.Mutex
+	files       []string // paths or filepath.Glob patterns
+	patterns    []logPattern
+	maxEvents   int
+	statePath   string
+	tails       map[string]logTail // sent read positions, by path
+	pending     map[string]logTail // positions after pendingData, once it is Sent
+	pendingData *MetricData
+	loaded      bool // tails restored from statePath
+	started     bool // first cycle done
+}
+
+type logPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// logTail is the persisted read position of one file.
+type logTail struct {
+	Offset int64  `json:"offset"`
+	FpLen  int64  `json:"fp_len"`
+	Fp     uint32 `json:"fp"` // CRC-32 of the first FpLen bytes
+}
+
+// NewLogWatchCollector creates a new log watch collector.
+func NewLogWatchCollector() *LogWatchCollector {
+	return &LogWatchCollector{
+		BaseCollector: NewBaseCollector("LogWatch"),
+		statePath:     defaultLogWatchStateFile,
+		tails:         make(map[string]logTail),
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the log watch collector.
+func (c *LogWatchCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 60 * time.Second
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *LogWatchCollector) Configure(cfg config.CollectorConfig) error {
+	patterns := make([]logPattern, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return fmt.Errorf("log watch: pattern %s: %w", p.Name, err)
+		}
+		patterns = append(patterns, logPattern{name: p.Name, re: re})
+	}
+	statePath := cfg.StateFile
+	if statePath == "" {
+		statePath = defaultLogWatchStateFile
+	}
+
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files = cfg.Files
+	c.patterns = patterns
+	c.maxEvents = cfg.MaxEventLines
+	if statePath != c.statePath {
+		c.statePath = statePath
+		c.loaded = false
+	}
+	return nil
+}
+
+// Collect reads the lines appended to each watched file since the last cycle.
+// Returns nil when no files or patterns are configured.
+func (c *LogWatchCollector) Collect(ctx context.Context) (*MetricData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.files) == 0 || len(c.patterns) == 0 {
+		return nil, nil
+	}
+
+	log := logger.WithComponent("collector")
+	if !c.loaded {
+		tails, err := loadLogWatchState(c.statePath)
+		if err != nil {
+			log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).
+				Msg("Failed to load log watch state, starting at end of files")
+		}
+		c.tails = tails
+		c.loaded = true
+	}
+
+	// Read from the sent positions; they advance in Sent.
+	work := make(map[string]logTail, len(c.tails))
+	for path, tail := range c.tails {
+		work[path] = tail
+	}
+
+	data := LogWatchData{Files: []LogWatchFile{}}
+	seen := make(map[string]bool)
+	for _, path := range expandLogFiles(c.files) {
+		if ctx.Err() != nil {
+			break
+		}
+		seen[path] = true
+		f, err := c.tailFile(path, work, &data.Events)
+		if err != nil {
+			log.Warn().Str("collector", c.Name()).Str("path", path).Err(err).Msg("Failed to read watched log file")
+			continue
+		}
+		data.Files = append(data.Files, f)
+	}
+	if ctx.Err() == nil {
+		// Forget files that no longer exist or match.
+		for path := range work {
+			if !seen[path] {
+				delete(work, path)
+			}
+		}
+	}
+	c.started = true
+
+	m := &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+	c.pending, c.pendingData = work, m
+	return m, nil
+}
+
+// Sent advances the read positions past the lines in data, saves them and
+// logs data's forwarded lines. Logging here rather than in Collect means a
+// cycle reread after a failed send does not log its lines twice. Data from
+// an older cycle than the last Collect is ignored.
+func (c *LogWatchCollector) Sent(data *MetricData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil || c.pendingData != data {
+		return
+	}
+	c.tails = c.pending
+	c.pending, c.pendingData = nil, nil
+	if d, ok := data.Data.(LogWatchData); ok {
+		logLogWatchEvents(d.Events)
+	}
+	if err := saveJSONState(c.statePath, c.tails); err != nil {
+		log := logger.WithComponent("collector")
+		log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).Msg("Failed to save log watch state")
+	}
+}
+
+// tailFile reads the complete lines appended to path since its offset in
+// tails, counts pattern matches and appends up to maxEvents matching lines to
+// events. A trailing partial line is left for the next cycle.
+func (c *LogWatchCollector) tailFile(path string, tails map[string]logTail, events *[]LogWatchEvent) (LogWatchFile, error) {
+	result := LogWatchFile{Path: path, Matches: make([]LogWatchMatch, len(c.patterns))}
+	for i, p := range c.patterns {
+		result.Matches[i].Pattern = p.name
+	}
+
+	f, err := openLogFile(path)
+	if err != nil {
+		return result, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return result, err
+	}
+	size := st.Size()
+
+	tail, known := tails[path]
+	baseline := false
+	switch {
+	case !known && !c.started:
+		tail.Offset = size
+		baseline = true
+	case !known:
+		tail.Offset = 0
+	case size < tail.FpLen || fingerprint(f, tail.FpLen) != tail.Fp:
+		tail.Offset = 0
+		result.Rotated = true
+	case size < tail.Offset:
+		tail.Offset = 0
+		result.Rotated = true
+	}
+
+	if n := size - tail.Offset; n > 0 {
+		if n > logWatchMaxReadBytes {
+			n = logWatchMaxReadBytes
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(io.NewSectionReader(f, tail.Offset, n), buf); err != nil {
+			return result, err
+		}
+		end := bytes.LastIndexByte(buf, '\n') + 1
+		if end == 0 && n == logWatchMaxReadBytes {
+			end = len(buf) // a single oversized line: consume it rather than stall
+		}
+		c.matchLines(path, buf[:end], result.Matches, events)
+		tail.Offset += int64(end)
+		result.BytesRead = int64(end)
+	}
+
+	tail.FpLen = size
+	if tail.FpLen > logWatchFingerprintBytes {
+		tail.FpLen = logWatchFingerprintBytes
+	}
+	tail.Fp = fingerprint(f, tail.FpLen)
+	tails[path] = tail
+	if baseline {
+		// Nothing was read: the start position holds even if this cycle's
+		// send fails, so a retry does not reread the whole file.
+		c.tails[path] = tail
+	}
+	return result, nil
+}
+
+// matchLines counts every pattern each line matches. A line is forwarded as
+// an event once, for the first pattern it matches.
+func (c *LogWatchCollector) matchLines(path string, buf []byte, matches []LogWatchMatch, events *[]LogWatchEvent) {
+	for len(buf) > 0 {
+		line := buf
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			line, buf = buf[:i], buf[i+1:]
+		} else {
+			buf = nil
+		}
+		line = bytes.TrimRight(line, "\r")
+
+		forwarded := false
+		for i, p := range c.patterns {
+			if !p.re.Match(line) {
+				continue
+			}
+			matches[i].Count++
+			if !forwarded && len(*events) < c.maxEvents {
+				text := line
+				if len(text) > logWatchMaxLineBytes {
+					text = text[:logWatchMaxLineBytes]
+				}
+				*events = append(*events, LogWatchEvent{Path: path, Pattern: p.name, Line: string(text)})
+				forwarded = true
+			}
+		}
+	}
+}
+
+// fingerprint returns the CRC-32 of the first n bytes of f (0 on read error).
+func fingerprint(f *os.File, n int64) uint32 {
+	buf := make([]byte, n)
+	if _, err := f.ReadAt(buf, 0); err != nil && n > 0 {
+		return 0
+	}
+	return crc32.ChecksumIEEE(buf)
+}
+
+// expandLogFiles resolves configured paths and globs to a sorted, de-duplicated
+// list of existing regular files.
+func expandLogFiles(patterns []string) []string {
+	set := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue // malformed pattern; only filepath.ErrBadPattern is possible
+		}
+		for _, m := range matches {
+			if st, err := os.Stat(m); err == nil && st.Mode().IsRegular() {
+				set[m] = true
+			}
+		}
+	}
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// loadLogWatchState reads the offsets file. A missing file is not an error.
+func loadLogWatchState(path string) (map[string]logTail, error) {
+	tails := make(map[string]logTail)
+	if err := loadJSONState(path, &tails); err != nil {
+		return make(map[string]logTail), err
+	}
+	return tails, nil
+}
+
+func logLogWatchEvents(events []LogWatchEvent) {
+	if len(events) == 0 {
+		return
+	}
+	log := logger.WithComponent("logwatch")
+	for _, e := range events {
+		log.Warn().Str("path", e.Path).Str("pattern", e.Pattern).Str("line", e.Line).
+			Msg("LOGWATCH_MATCH: log line matched pattern")
+	}
+}
@@ -0,0 +1,225 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"resourceagent/internal/config"
+)
+
+func newTestLogWatch(t *testing.T, dir string, files []string, maxEvents int) *LogWatchCollector {
+	t.Helper()
+	c := NewLogWatchCollector()
+	err := c.Configure(config.CollectorConfig{
+		Enabled: true,
+		Files:   files,
+		Patterns: []config.LogPattern{
+			{Name: "alarm", Regex: `ALARM`},
+			{Name: "timeout", Regex: `(?i)timeout`},
+		},
+		MaxEventLines: maxEvents,
+		StateFile:     filepath.Join(dir, "state", "logwatch.json"),
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	return c
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collectLogWatch(t *testing.T, c *LogWatchCollector) *LogWatchData {
+	t.Helper()
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	c.Sent(m)
+	d, ok := m.Data.(LogWatchData)
+	if !ok {
+		t.Fatalf("Data = %T, want LogWatchData", m.Data)
+	}
+	return &d
+}
+
+// counts returns pattern -> count for path.
+func counts(d *LogWatchData, path string) map[string]int {
+	for _, f := range d.Files {
+		if f.Path == path {
+			out := make(map[string]int)
+			for _, m := range f.Matches {
+				out[m.Pattern] = m.Count
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+func TestLogWatch_NoConfig(t *testing.T) {
+	c := NewLogWatchCollector()
+	if m, err := c.Collect(context.Background()); m != nil || err != nil {
+		t.Errorf("Collect() = (%v, %v), want (nil, nil) without files", m, err)
+	}
+}
+
+func TestLogWatch_TailAndPartialLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "eqp.log")
+	appendFile(t, path, "ALARM before start\n")
+
+	c := newTestLogWatch(t, dir, []string{path}, 0)
+	d := collectLogWatch(t, c)
+	if got := counts(d, path); got["alarm"] != 0 {
+		t.Errorf("first cycle counts = %v, want existing content skipped", got)
+	}
+
+	appendFile(t, path, "ok\nALARM 101 door open\nRead Timeout on port 3\nALARM partial")
+	d = collectLogWatch(t, c)
+	if got := counts(d, path); got["alarm"] != 1 || got["timeout"] != 1 {
+		t.Errorf("counts = %v, want alarm=1 timeout=1 (partial line held back)", got)
+	}
+
+	appendFile(t, path, " completed\r\n")
+	d = collectLogWatch(t, c)
+	if got := counts(d, path); got["alarm"] != 1 || got["timeout"] != 0 {
+		t.Errorf("counts = %v, want the completed partial line counted once", got)
+	}
+}
+
+func TestLogWatch_RotationAndTruncation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "eqp.log")
+	appendFile(t, path, "header line\n")
+	c := newTestLogWatch(t, dir, []string{path}, 0)
+	collectLogWatch(t, c)
+	appendFile(t, path, "ALARM 1\nALARM 2\n")
+	collectLogWatch(t, c)
+
+	// Rotation: rename away and create a new file.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, "new file\nALARM 3\n")
+	d := collectLogWatch(t, c)
+	if got := counts(d, path); got["alarm"] != 1 || !d.Files[0].Rotated {
+		t.Errorf("after rotation: counts = %v, rotated = %v, want alarm=1 from the new file", got, d.Files[0].Rotated)
+	}
+
+	// copytruncate: same head, shorter than the saved offset.
+	if err := os.WriteFile(path, []byte("new file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d = collectLogWatch(t, c)
+	if !d.Files[0].Rotated {
+		t.Error("truncation not detected")
+	}
+	appendFile(t, path, "Timeout\n")
+	d = collectLogWatch(t, c)
+	if got := counts(d, path); got["timeout"] != 1 || d.Files[0].Rotated {
+		t.Errorf("after truncation: counts = %v, want timeout=1", got)
+	}
+}
+
+func TestLogWatch_PersistsOffsets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "eqp.log")
+	appendFile(t, path, "start\n")
+	c := newTestLogWatch(t, dir, []string{path}, 0)
+	collectLogWatch(t, c)
+	appendFile(t, path, "ALARM seen\n")
+	collectLogWatch(t, c)
+
+	// Written while the agent is down: a restarted collector must read it.
+	appendFile(t, path, "ALARM while stopped\n")
+	restarted := newTestLogWatch(t, dir, []string{path}, 0)
+	d := collectLogWatch(t, restarted)
+	if got := counts(d, path); got["alarm"] != 1 {
+		t.Errorf("after restart counts = %v, want only the line written while stopped", got)
+	}
+}
+
+func TestLogWatch_UnsentLinesAreReread(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "eqp.log")
+	appendFile(t, path, "ALARM before start\n")
+	c := newTestLogWatch(t, dir, []string{path}, 0)
+
+	// The first cycle's send fails: the start position still holds.
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	appendFile(t, path, "ALARM 1\n")
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	// ALARM 1 was not sent either: the next cycle reads it again.
+	appendFile(t, path, "ALARM 2\n")
+	d := collectLogWatch(t, c)
+	if got := counts(d, path); got["alarm"] != 2 {
+		t.Errorf("after unsent cycle counts = %v, want alarm=2 (ALARM 1 and 2)", got)
+	}
+
+	// Sent: a restart resumes after ALARM 2.
+	restarted := newTestLogWatch(t, dir, []string{path}, 0)
+	appendFile(t, path, "ALARM 3\n")
+	d = collectLogWatch(t, restarted)
+	if got := counts(d, path); got["alarm"] != 1 {
+		t.Errorf("after restart counts = %v, want only ALARM 3", got)
+	}
+}
+
+func TestLogWatch_GlobAndEvents(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.log")
+	appendFile(t, a, "")
+	c := newTestLogWatch(t, dir, []string{filepath.Join(dir, "*.log")}, 2)
+	collectLogWatch(t, c)
+
+	// b.log appears after start and is read from the beginning.
+	b := filepath.Join(dir, "b.log")
+	long := "ALARM " + strings.Repeat("x", 600)
+	appendFile(t, b, long+"\nALARM timeout\nALARM 3\n")
+	appendFile(t, a, "timeout\n")
+	d := collectLogWatch(t, c)
+
+	if len(d.Files) != 2 || d.Files[0].Path != a || d.Files[1].Path != b {
+		t.Fatalf("files = %+v, want a.log and b.log sorted", d.Files)
+	}
+	if got := counts(d, b); got["alarm"] != 3 || got["timeout"] != 1 {
+		t.Errorf("b.log counts = %v, want alarm=3 timeout=1", got)
+	}
+	if len(d.Events) != 2 {
+		t.Fatalf("events = %+v, want the first 2 matches", d.Events)
+	}
+	if d.Events[0].Path != a || d.Events[0].Pattern != "timeout" {
+		t.Errorf("event[0] = %+v, want a.log timeout", d.Events[0])
+	}
+	if d.Events[1].Pattern != "alarm" || len(d.Events[1].Line) != logWatchMaxLineBytes {
+		t.Errorf("event[1] = %q (%d bytes), want alarm truncated to %d bytes",
+			d.Events[1].Pattern, len(d.Events[1].Line), logWatchMaxLineBytes)
+	}
+
+	// Deleted files are forgotten.
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	collectLogWatch(t, c)
+	if _, ok := c.tails[b]; ok {
+		t.Error("tail of deleted b.log still tracked")
+	}
+}
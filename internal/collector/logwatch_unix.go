@@ -0,0 +1,11 @@
+//go:build linux || darwin
+
+package collector
+
+import "os"
+
+// openLogFile opens a watched log file for reading. Unix rename and unlink
+// never conflict with open readers.
+func openLogFile(path string) (*os.File, error) {
+	return os.Open(path)
+}
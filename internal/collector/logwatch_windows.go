@@ -0,0 +1,26 @@
+//go:build windows
+
+package collector
+
+import (
+	"os"
+	"syscall"
+)
+
+// openLogFile opens a watched log file with FILE_SHARE_DELETE in addition
+// to read/write sharing, so an application renaming or deleting its log
+// during rotation does not fail while the collector is reading it (os.Open
+// omits FILE_SHARE_DELETE).
+func openLogFile(path string) (*os.File, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(p, syscall.GENERIC_READ,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
@@ -4,12 +4,57 @@ package collector
 
 import (
 	"context"
+	"strings"
 )
 
-// collectMotherboardTemps returns empty data on Linux/Darwin.
-// Future: Could implement hwmon/sysfs parsing for motherboard temperature sensors on Linux.
+// nonBoardHwmonDevices are hwmon drivers whose temperatures are not board or
+// chipset sensors: CPU package/core (covered by the Temperature collector),
+// GPU, storage and wireless devices. Matched as a name prefix.
+var nonBoardHwmonDevices = []string{
+	"coretemp", "k10temp", "k8temp", "zenpower", "cpu_thermal",
+	"amdgpu", "radeon", "nouveau", "i915",
+	"nvme", "drivetemp",
+	"iwlwifi", "ath",
+}
+
+// collectMotherboardTemps collects board and chipset temperatures from sysfs
+// hwmon (temp*_input, millidegrees Celsius) on Linux, e.g. Super I/O chips
+// (nct6775, it87), PCH and ACPI zones. Sensors are named by temp*_label
+// when present, otherwise "<device> Temp #N". On Darwin (macOS), returns empty.
 func (c *MotherboardTempCollector) collectMotherboardTemps(ctx context.Context) ([]MotherboardTempSensor, error) {
-	// Motherboard temperature metrics via LHM are only available on Windows
-	// Return nil to indicate no data (not an error)
-	return nil, nil
+	readings, err := readHwmonInputs(ctx, "temp", "Temp")
+
+	var sensors []MotherboardTempSensor
+	for _, r := range readings {
+		if !isBoardHwmonDevice(r.device) {
+			continue
+		}
+
+		celsius := r.value / 1000
+		// Skip disconnected inputs (0 or negative) and bogus readings
+		if celsius <= 0 || celsius > 200 {
+			continue
+		}
+
+		// Skip if specific sensors are configured and this one isn't in the list
+		if len(c.includeSensors) > 0 && !c.shouldInclude(r.label) {
+			continue
+		}
+
+		sensors = append(sensors, MotherboardTempSensor{
+			Name:        r.label,
+			Temperature: celsius,
+		})
+	}
+
+	return sensors, err
+}
+
+func isBoardHwmonDevice(device string) bool {
+	for _, prefix := range nonBoardHwmonDevices {
+		if strings.HasPrefix(device, prefix) {
+			return false
+		}
+	}
+	return true
 }
@@ -2,6 +2,8 @@ package collector
 
 import (
 	"context"
+	stdnet "net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/shirou/gopsutil/v3/net"
 
 	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
 )
 
 // NetworkCollector collects network interface metrics.
@@ -16,12 +19,20 @@ type NetworkCollector struct {
 	BaseCollector
 	interfaces []string // Specific interfaces to monitor; empty means all
 
-	// For rate calculation
+	// For rate calculation and state-change events
 	mu          sync.Mutex
 	lastStats   map[string]net.IOCountersStat
+	lastLinks   map[string]linkState
 	lastCollect time.Time
 }
 
+// linkState is the part of an interface's state that raises an event when
+// it changes between cycles.
+type linkState struct {
+	up    bool
+	addrs string // sorted, comma-joined CIDRs
+}
+
 // NewNetworkCollector creates a new network collector.
 func NewNetworkCollector() *NetworkCollector {
 	return &NetworkCollector{
@@ -57,9 +68,12 @@ func (c *NetworkCollector) Collect(ctx context.Context) (*MetricData, error) {
 	// Snapshot previous state under lock
 	c.mu.Lock()
 	prevStats := c.lastStats
+	prevLinks := c.lastLinks
 	prevCollect := c.lastCollect
 	c.mu.Unlock()
 
+	ifaces := interfacesByName()
+
 	now := time.Now()
 	elapsed := now.Sub(prevCollect).Seconds()
 	if elapsed <= 0 {
@@ -69,6 +83,7 @@ func (c *NetworkCollector) Collect(ctx context.Context) (*MetricData, error) {
 	// Rate calculation outside lock
 	var interfaces []NetworkInterface
 	newStats := make(map[string]net.IOCountersStat, len(counters))
+	newLinks := make(map[string]linkState, len(counters))
 
 	for _, counter := range counters {
 		if len(c.interfaces) > 0 && !c.shouldInclude(counter.Name) {
@@ -92,24 +107,35 @@ func (c *NetworkCollector) Collect(ctx context.Context) (*MetricData, error) {
 		}
 
 		if prev, ok := prevStats[counter.Name]; ok && prevCollect.Unix() > 0 {
-			bytesSentDiff := float64(counter.BytesSent - prev.BytesSent)
-			bytesRecvDiff := float64(counter.BytesRecv - prev.BytesRecv)
+			iface.BytesSentRate = counterRate(prev.BytesSent, counter.BytesSent, elapsed)
+			iface.BytesRecvRate = counterRate(prev.BytesRecv, counter.BytesRecv, elapsed)
+			iface.ErrorsInRate = counterRate(prev.Errin, counter.Errin, elapsed)
+			iface.ErrorsOutRate = counterRate(prev.Errout, counter.Errout, elapsed)
+			iface.DropsInRate = counterRate(prev.Dropin, counter.Dropin, elapsed)
+			iface.DropsOutRate = counterRate(prev.Dropout, counter.Dropout, elapsed)
+		}
 
-			if bytesSentDiff >= 0 {
-				iface.BytesSentRate = bytesSentDiff / elapsed
-			}
-			if bytesRecvDiff >= 0 {
-				iface.BytesRecvRate = bytesRecvDiff / elapsed
-			}
+		if ni, ok := ifaces[counter.Name]; ok {
+			fillLinkState(&iface, ni)
 		}
+		iface.SpeedMbps, iface.Duplex = readLinkInfo(counter.Name)
+		iface.UtilPercent = linkUtilization(iface)
 
 		newStats[counter.Name] = counter
+		newLinks[counter.Name] = linkState{up: iface.OperUp, addrs: strings.Join(iface.Addresses, ",")}
 		interfaces = append(interfaces, iface)
 	}
 
+	var events []NetworkEvent
+	if prevCollect.Unix() > 0 {
+		events = diffLinkStates(prevLinks, newLinks)
+		logNetworkEvents(events)
+	}
+
 	// Update state under lock
 	c.mu.Lock()
 	c.lastStats = newStats
+	c.lastLinks = newLinks
 	c.lastCollect = now
 	c.mu.Unlock()
 
@@ -122,10 +148,117 @@ func (c *NetworkCollector) Collect(ctx context.Context) (*MetricData, error) {
 			Interfaces:       interfaces,
 			TCPInboundCount:  inbound,
 			TCPOutboundCount: outbound,
+			Events:           events,
 		},
 	}, nil
 }
 
+// counterRate returns the per-second increase of a cumulative counter, or 0
+// when the counter went backwards (interface reset, driver reload).
+func counterRate(prev, cur uint64, elapsed float64) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / elapsed
+}
+
+// interfacesByName returns the OS interface list keyed by name. gopsutil
+// names interfaces the same way (the friendly name on Windows).
+func interfacesByName() map[string]stdnet.Interface {
+	list, err := stdnet.Interfaces()
+	if err != nil {
+		return nil
+	}
+	m := make(map[string]stdnet.Interface, len(list))
+	for _, ni := range list {
+		m[ni.Name] = ni
+	}
+	return m
+}
+
+// fillLinkState sets oper state, MAC and addresses. An interface is
+// operationally up when it is administratively up and has carrier
+// (IFF_RUNNING on Linux, IfOperStatusUp on Windows).
+func fillLinkState(iface *NetworkInterface, ni stdnet.Interface) {
+	iface.OperUp = ni.Flags&stdnet.FlagUp != 0 && ni.Flags&stdnet.FlagRunning != 0
+	iface.MAC = ni.HardwareAddr.String()
+	if addrs, err := ni.Addrs(); err == nil {
+		for _, a := range addrs {
+			iface.Addresses = append(iface.Addresses, a.String())
+		}
+		sort.Strings(iface.Addresses)
+	}
+}
+
+// linkUtilization returns traffic as a percentage of link speed: the busier
+// direction for full duplex, both directions combined for half duplex.
+func linkUtilization(iface NetworkInterface) *float64 {
+	if iface.SpeedMbps <= 0 {
+		return nil
+	}
+	capacity := iface.SpeedMbps * 1e6 / 8 // bytes/s
+	used := iface.BytesRecvRate
+	if iface.Duplex == "half" {
+		used += iface.BytesSentRate
+	} else if iface.BytesSentRate > used {
+		used = iface.BytesSentRate
+	}
+	util := used / capacity * 100
+	if util > 100 {
+		util = 100
+	}
+	return &util
+}
+
+// diffLinkStates compares interface states between two cycles. An
+// interface that disappears while up (USB NIC unplugged, adapter disabled
+// on Windows) counts as link_down; one that appears up counts as link_up.
+func diffLinkStates(prev, cur map[string]linkState) []NetworkEvent {
+	var events []NetworkEvent
+	for name, now := range cur {
+		was, ok := prev[name]
+		switch {
+		case !ok:
+			if now.up {
+				events = append(events, NetworkEvent{Interface: name, Type: "link_up", New: now.addrs})
+			}
+		case was.up && !now.up:
+			events = append(events, NetworkEvent{Interface: name, Type: "link_down", Old: was.addrs, New: now.addrs})
+		case !was.up && now.up:
+			events = append(events, NetworkEvent{Interface: name, Type: "link_up", Old: was.addrs, New: now.addrs})
+		case was.addrs != now.addrs:
+			events = append(events, NetworkEvent{Interface: name, Type: "address_changed", Old: was.addrs, New: now.addrs})
+		}
+	}
+	for name, was := range prev {
+		if _, ok := cur[name]; !ok && was.up {
+			events = append(events, NetworkEvent{Interface: name, Type: "link_down", Old: was.addrs})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Interface < events[j].Interface })
+	return events
+}
+
+func logNetworkEvents(events []NetworkEvent) {
+	if len(events) == 0 {
+		return
+	}
+	log := logger.WithComponent("network")
+	for _, e := range events {
+		switch e.Type {
+		case "link_up":
+			log.Info().Str("interface", e.Interface).Str("addresses", e.New).
+				Msg("NIC_LINK_UP: interface came up")
+		case "address_changed":
+			log.Warn().Str("interface", e.Interface).Str("old", e.Old).Str("new", e.New).
+				Msg("NIC_ADDRESS_CHANGED: interface addresses changed")
+		default:
+			log.Warn().Str("interface", e.Interface).Str("addresses", e.Old).
+				Msg("NIC_LINK_DOWN: interface went down")
+		}
+	}
+}
+
 // classifyTCPConnections classifies TCP connections into inbound (server) and outbound (client).
 // Inbound connections have a local port that matches a LISTEN port.
 // Outbound connections have a local port that does not match any LISTEN port.
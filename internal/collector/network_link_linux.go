@@ -0,0 +1,33 @@
+//go:build linux
+
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysClassNetPath is the sysfs network interface directory (overridden in tests).
+var sysClassNetPath = "/sys/class/net"
+
+// readLinkInfo reads the negotiated speed (Mbps) and duplex from sysfs.
+// Drivers report speed -1 and duplex "unknown" when there is no link or
+// the value is not applicable (Wi-Fi, bridges); reading speed on a down
+// link fails with EINVAL. All of these yield 0 / "".
+func readLinkInfo(name string) (speedMbps float64, duplex string) {
+	dir := filepath.Join(sysClassNetPath, name)
+	if b, err := os.ReadFile(filepath.Join(dir, "speed")); err == nil {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(string(b)), 64); err == nil && v > 0 {
+			speedMbps = v
+		}
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "duplex")); err == nil {
+		switch d := strings.TrimSpace(string(b)); d {
+		case "full", "half":
+			duplex = d
+		}
+	}
+	return speedMbps, duplex
+}
@@ -0,0 +1,38 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadLinkInfo_Sysfs(t *testing.T) {
+	root := t.TempDir()
+	write := func(iface, file, content string) {
+		dir := filepath.Join(root, iface)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("eth0", "speed", "1000\n")
+	write("eth0", "duplex", "full\n")
+	write("eth1", "speed", "-1\n") // no link
+	write("eth1", "duplex", "unknown\n")
+
+	orig := sysClassNetPath
+	sysClassNetPath = root
+	defer func() { sysClassNetPath = orig }()
+
+	if speed, duplex := readLinkInfo("eth0"); speed != 1000 || duplex != "full" {
+		t.Errorf("eth0 = %v %q, want 1000 full", speed, duplex)
+	}
+	if speed, duplex := readLinkInfo("eth1"); speed != 0 || duplex != "" {
+		t.Errorf("eth1 = %v %q, want unknown", speed, duplex)
+	}
+	if speed, duplex := readLinkInfo("wlan0"); speed != 0 || duplex != "" {
+		t.Errorf("missing interface = %v %q, want unknown", speed, duplex)
+	}
+}
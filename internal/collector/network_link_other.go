@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package collector
+
+// readLinkInfo is not implemented on this platform; link speed and duplex
+// are reported as unknown.
+func readLinkInfo(string) (float64, string) {
+	return 0, ""
+}
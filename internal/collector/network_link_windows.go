@@ -0,0 +1,64 @@
+//go:build windows
+
+package collector
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// GetAdaptersAddresses flags not defined in x/sys/windows (iptypes.h).
+const (
+	gaaFlagSkipAnycast   = 0x0002
+	gaaFlagSkipMulticast = 0x0004
+	gaaFlagSkipDNSServer = 0x0008
+)
+
+// linkSpeedCache holds one GetAdaptersAddresses snapshot per collection
+// cycle; readLinkInfo is called once per interface.
+var linkSpeedCache struct {
+	mu     sync.Mutex
+	at     time.Time
+	speeds map[string]float64 // friendly name → Mbps
+}
+
+// readLinkInfo returns the adapter's transmit link speed from
+// GetAdaptersAddresses (IP_ADAPTER_ADDRESSES.TransmitLinkSpeed, Vista+).
+// Windows does not expose the negotiated duplex through IP Helper, so
+// duplex is always "" and utilization assumes full duplex.
+func readLinkInfo(name string) (float64, string) {
+	linkSpeedCache.mu.Lock()
+	defer linkSpeedCache.mu.Unlock()
+	if time.Since(linkSpeedCache.at) > time.Second {
+		linkSpeedCache.speeds = adapterLinkSpeeds()
+		linkSpeedCache.at = time.Now()
+	}
+	return linkSpeedCache.speeds[name], ""
+}
+
+func adapterLinkSpeeds() map[string]float64 {
+	size := uint32(15 * 1024) // recommended initial buffer size
+	for i := 0; i < 3; i++ {
+		buf := make([]byte, size)
+		first := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, gaaFlagSkipAnycast|gaaFlagSkipMulticast|gaaFlagSkipDNSServer, 0, first, &size)
+		if err == windows.ERROR_BUFFER_OVERFLOW {
+			continue
+		}
+		if err != nil {
+			return nil
+		}
+		speeds := make(map[string]float64)
+		for a := first; a != nil; a = a.Next {
+			// ULONG64_MAX means the speed is unknown.
+			if a.TransmitLinkSpeed > 0 && a.TransmitLinkSpeed != ^uint64(0) {
+				speeds[windows.UTF16PtrToString(a.FriendlyName)] = float64(a.TransmitLinkSpeed) / 1e6
+			}
+		}
+		return speeds
+	}
+	return nil
+}
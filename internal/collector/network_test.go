@@ -105,3 +105,67 @@ func TestNetworkCollect_HasTCPCounts(t *testing.T) {
 		t.Log("warning: no TCP connections detected (may be expected in CI)")
 	}
 }
+
+func TestCounterRate(t *testing.T) {
+	if got := counterRate(100, 400, 3); got != 100 {
+		t.Errorf("counterRate = %v, want 100", got)
+	}
+	if got := counterRate(400, 100, 3); got != 0 {
+		t.Errorf("counterRate after counter reset = %v, want 0", got)
+	}
+}
+
+func TestLinkUtilization(t *testing.T) {
+	if u := linkUtilization(NetworkInterface{BytesRecvRate: 1000}); u != nil {
+		t.Errorf("unknown speed: util = %v, want nil", *u)
+	}
+
+	// 100 Mbps = 12.5 MB/s; full duplex uses the busier direction.
+	full := NetworkInterface{SpeedMbps: 100, BytesRecvRate: 1250000, BytesSentRate: 2500000}
+	if u := linkUtilization(full); u == nil || *u != 20 {
+		t.Errorf("full duplex util = %v, want 20", u)
+	}
+	half := full
+	half.Duplex = "half"
+	if u := linkUtilization(half); u == nil || *u != 30 {
+		t.Errorf("half duplex util = %v, want 30", u)
+	}
+	half.BytesRecvRate = 20000000
+	if u := linkUtilization(half); *u != 100 {
+		t.Errorf("util = %v, want capped at 100", *u)
+	}
+}
+
+func TestDiffLinkStates(t *testing.T) {
+	prev := map[string]linkState{
+		"eth0": {up: true, addrs: "10.0.0.5/24"},
+		"eth1": {up: true, addrs: "192.168.1.2/24"},
+		"eth2": {up: false},
+		"usb0": {up: true, addrs: "172.16.0.1/16"},
+		"eth3": {up: true, addrs: "10.1.0.1/24"},
+	}
+	cur := map[string]linkState{
+		"eth0":  {up: true, addrs: "10.0.0.6/24"}, // DHCP moved it
+		"eth1":  {up: false},                      // cable pulled
+		"eth2":  {up: true, addrs: "10.2.0.1/24"}, // came up
+		"eth3":  {up: true, addrs: "10.1.0.1/24"}, // unchanged
+		"wlan0": {up: false},                      // new, down: no event
+		// usb0 unplugged
+	}
+
+	events := diffLinkStates(prev, cur)
+	want := []NetworkEvent{
+		{Interface: "eth0", Type: "address_changed", Old: "10.0.0.5/24", New: "10.0.0.6/24"},
+		{Interface: "eth1", Type: "link_down", Old: "192.168.1.2/24"},
+		{Interface: "eth2", Type: "link_up", New: "10.2.0.1/24"},
+		{Interface: "usb0", Type: "link_down", Old: "172.16.0.1/16"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %+v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events[%d] = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
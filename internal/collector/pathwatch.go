@@ -0,0 +1,246 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	// defaultPathWatchMaxFiles bounds the directory entries visited per rule
+	// and cycle so a folder with millions of files cannot stall the collector.
+	defaultPathWatchMaxFiles = 10000
+	// pathWatchReadDirBatch is how many entries are read from a directory at
+	// once, keeping memory flat for huge flat folders.
+	pathWatchReadDirBatch = 256
+)
+
+// PathWatchCollector reports the file count, total size, newest file age and
+// largest file of configured directories or globs, and flags rules whose
+// newest file is older than MaxAge (the producing software stopped) or whose
+// size exceeds QuotaMB (the folder is filling up).
+//
+// A rule's Path is expanded with filepath.Glob; matched files are counted
+// directly and matched directories are listed (recursively when Recursive is
+// set). Each rule visits at most MaxFiles directory entries per cycle.
+//
+// A walk cut short (MaxFiles reached, or the collection deadline) is
+// Truncated. Its newest file may be among the unvisited ones, so it is fresh
+// only on positive evidence: a file or a visited directory modified within
+// MaxAge. A directory's mtime changes whenever a file is created in it.
+type PathWatchCollector struct {
+	BaseCollector
+
+	mu       sync.RWMutex
+	rules    []config.PathRule
+	maxFiles int
+
+	warnMu    sync.Mutex
+	truncated map[string]bool // rule paths whose truncation was logged
+}
+
+// NewPathWatchCollector creates a new path watch collector.
+func NewPathWatchCollector() *PathWatchCollector {
+	return &PathWatchCollector{
+		BaseCollector: NewBaseCollector("PathWatch"),
+		maxFiles:      defaultPathWatchMaxFiles,
+		truncated:     make(map[string]bool),
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the path watch collector.
+func (c *PathWatchCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 60 * time.Second
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *PathWatchCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = cfg.Paths
+	c.maxFiles = cfg.MaxFiles
+	if c.maxFiles <= 0 {
+		c.maxFiles = defaultPathWatchMaxFiles
+	}
+	return nil
+}
+
+// Collect walks every configured rule. Returns nil when no paths are configured.
+func (c *PathWatchCollector) Collect(ctx context.Context) (*MetricData, error) {
+	c.mu.RLock()
+	rules := c.rules
+	maxFiles := c.maxFiles
+	c.mu.RUnlock()
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	data := PathWatchData{Paths: make([]PathWatchStatus, 0, len(rules))}
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		status := scanPathRule(ctx, rule, maxFiles, now)
+		c.logTruncation(rule.Path, status.Truncated, maxFiles)
+		data.Paths = append(data.Paths, status)
+	}
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: now,
+		Data:      data,
+	}, nil
+}
+
+// logTruncation warns the first time path's walk is truncated, and again
+// only after a complete walk in between.
+func (c *PathWatchCollector) logTruncation(path string, truncated bool, maxFiles int) {
+	c.warnMu.Lock()
+	defer c.warnMu.Unlock()
+	if !truncated {
+		delete(c.truncated, path)
+		return
+	}
+	if c.truncated[path] {
+		return
+	}
+	c.truncated[path] = true
+	log := logger.WithComponent("collector")
+	log.Warn().Str("collector", c.Name()).Str("path", path).Int("max_files", maxFiles).
+		Msg("PATHWATCH_TRUNCATED path walk stopped before the end (MaxFiles or collection timeout), totals are partial")
+}
+
+// pathScan accumulates the files of one rule.
+type pathScan struct {
+	status    PathWatchStatus
+	newest    time.Time
+	newestDir time.Time // latest mtime of the directories listed
+	budget    int       // directory entries left to visit
+}
+
+// scanPathRule expands rule.Path and aggregates the matched files.
+func scanPathRule(ctx context.Context, rule config.PathRule, maxFiles int, now time.Time) PathWatchStatus {
+	s := &pathScan{status: PathWatchStatus{Path: rule.Path}, budget: maxFiles}
+
+	matches, _ := filepath.Glob(rule.Path) // pattern validated at config load
+	sort.Strings(matches)
+	for _, m := range matches {
+		if s.budget <= 0 || ctx.Err() != nil {
+			s.status.Truncated = s.budget <= 0
+			break
+		}
+		st, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if st.IsDir() {
+			s.walkDir(ctx, m, rule.Recursive)
+		} else if st.Mode().IsRegular() {
+			s.budget--
+			s.addFile(m, st)
+		}
+	}
+	if ctx.Err() != nil {
+		s.status.Truncated = true // the deadline cut the walk short
+	}
+
+	status := s.status
+	if status.FileCount > 0 {
+		age := now.Sub(s.newest).Seconds()
+		if age < 0 {
+			age = 0 // clock skew with a network share
+		}
+		status.NewestAgeSeconds = &age
+	}
+	if rule.MaxAge > 0 {
+		status.Stale = status.NewestAgeSeconds == nil || *status.NewestAgeSeconds > rule.MaxAge.Seconds()
+		// A truncated walk may have missed the newest file: a recently
+		// modified directory is evidence of a write there too.
+		if status.Stale && status.Truncated && !s.newestDir.IsZero() &&
+			now.Sub(s.newestDir) <= rule.MaxAge {
+			status.Stale = false
+		}
+	}
+	// A truncated total is a lower bound: above quota is still over.
+	if rule.QuotaMB > 0 {
+		status.OverQuota = status.TotalBytes > rule.QuotaMB<<20
+	}
+	return status
+}
+
+// walkDir lists dir in batches, descending into subdirectories when
+// recursive, until the entry budget is spent. Unreadable directories are
+// skipped.
+func (s *pathScan) walkDir(ctx context.Context, root string, recursive bool) {
+	stack := []string{root}
+	for len(stack) > 0 {
+		dir := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		f, err := os.Open(dir)
+		if err != nil {
+			continue
+		}
+		if st, err := f.Stat(); err == nil && st.ModTime().After(s.newestDir) {
+			s.newestDir = st.ModTime()
+		}
+		for {
+			if ctx.Err() != nil {
+				f.Close()
+				return
+			}
+			entries, err := f.ReadDir(pathWatchReadDirBatch)
+			for _, e := range entries {
+				if s.budget <= 0 {
+					s.status.Truncated = true
+					f.Close()
+					return
+				}
+				s.budget--
+				path := filepath.Join(dir, e.Name())
+				switch {
+				case e.IsDir():
+					if recursive {
+						stack = append(stack, path)
+					}
+				case e.Type().IsRegular():
+					if info, err := e.Info(); err == nil {
+						s.addFile(path, info)
+					}
+				}
+			}
+			if err != nil {
+				break // io.EOF, or a read error: keep what was listed
+			}
+		}
+		f.Close()
+	}
+}
+
+func (s *pathScan) addFile(path string, info os.FileInfo) {
+	s.status.FileCount++
+	s.status.TotalBytes += info.Size()
+	if info.Size() > s.status.LargestBytes || s.status.LargestFile == "" {
+		s.status.LargestFile = path
+		s.status.LargestBytes = info.Size()
+	}
+	if mt := info.ModTime(); mt.After(s.newest) {
+		s.newest = mt
+		s.status.NewestFile = path
+	}
+}
@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+func writeAgedFile(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Now().Add(-age)
+	if err := os.Chtimes(path, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collectPathWatch(t *testing.T, rules []config.PathRule, maxFiles int) []PathWatchStatus {
+	t.Helper()
+	c := NewPathWatchCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true, Paths: rules, MaxFiles: maxFiles}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	d, ok := m.Data.(PathWatchData)
+	if !ok {
+		t.Fatalf("Data = %T, want PathWatchData", m.Data)
+	}
+	return d.Paths
+}
+
+func TestPathWatch_NoConfig(t *testing.T) {
+	c := NewPathWatchCollector()
+	if m, err := c.Collect(context.Background()); m != nil || err != nil {
+		t.Errorf("Collect() = (%v, %v), want (nil, nil) without paths", m, err)
+	}
+}
+
+func TestPathWatch_DirectoryStats(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFile(t, filepath.Join(dir, "a.csv"), 100, 2*time.Hour)
+	writeAgedFile(t, filepath.Join(dir, "b.csv"), 300, 10*time.Minute)
+	writeAgedFile(t, filepath.Join(dir, "sub", "c.csv"), 500, time.Minute)
+
+	got := collectPathWatch(t, []config.PathRule{
+		{Path: dir, MaxAge: 30 * time.Minute},
+		{Path: dir, Recursive: true, MaxAge: 30 * time.Minute, QuotaMB: 1},
+		{Path: filepath.Join(dir, "*.csv"), MaxAge: 5 * time.Minute},
+	}, 0)
+
+	flat := got[0]
+	if flat.FileCount != 2 || flat.TotalBytes != 400 || flat.LargestBytes != 300 || flat.LargestFile != filepath.Join(dir, "b.csv") {
+		t.Errorf("flat = %+v, want 2 files, 400 bytes, largest b.csv", flat)
+	}
+	if flat.NewestAgeSeconds == nil || *flat.NewestAgeSeconds < 590 || *flat.NewestAgeSeconds > 700 || flat.Stale {
+		t.Errorf("flat newest = %v, stale = %v, want ~600s and fresh", flat.NewestAgeSeconds, flat.Stale)
+	}
+
+	rec := got[1]
+	if rec.FileCount != 3 || rec.TotalBytes != 900 || rec.NewestFile != filepath.Join(dir, "sub", "c.csv") || rec.OverQuota {
+		t.Errorf("recursive = %+v, want 3 files, 900 bytes, newest sub/c.csv, within quota", rec)
+	}
+
+	glob := got[2]
+	if glob.FileCount != 2 || !glob.Stale {
+		t.Errorf("glob = %+v, want 2 files, stale beyond 5m", glob)
+	}
+}
+
+func TestPathWatch_MissingAndQuota(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFile(t, filepath.Join(dir, "big.bin"), 2<<20, 0)
+
+	got := collectPathWatch(t, []config.PathRule{
+		{Path: filepath.Join(dir, "missing"), MaxAge: time.Hour},
+		{Path: filepath.Join(dir, "missing2")},
+		{Path: dir, QuotaMB: 1},
+	}, 0)
+
+	if got[0].FileCount != 0 || got[0].NewestAgeSeconds != nil || !got[0].Stale {
+		t.Errorf("missing with MaxAge = %+v, want no files and stale", got[0])
+	}
+	if got[1].Stale || got[1].OverQuota {
+		t.Errorf("missing without thresholds = %+v, want no alert", got[1])
+	}
+	if !got[2].OverQuota {
+		t.Errorf("2MB in a 1MB quota = %+v, want over quota", got[2])
+	}
+}
+
+func TestPathWatch_MaxFilesBound(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		writeAgedFile(t, filepath.Join(dir, "f"+strings.Repeat("0", i)), 300<<10, time.Hour)
+	}
+
+	// The files visited are old, but the directory was just written to: an
+	// unvisited file is fresh. Their 1500 KiB already exceed the 1 MiB
+	// quota, which the rest can only add to.
+	partial := config.PathRule{Path: dir, MaxAge: time.Minute, QuotaMB: 1}
+	got := collectPathWatch(t, []config.PathRule{partial, {Path: filepath.Join(dir, "f*")}}, 5)
+	for i, s := range got {
+		if s.FileCount != 5 || !s.Truncated {
+			t.Errorf("rule %d = %+v, want 5 files and truncated", i, s)
+		}
+		if s.Stale {
+			t.Errorf("rule %d = %+v, want fresh from the directory mtime", i, s)
+		}
+	}
+
+	// No file or directory seen is recent: the truncated walk is stale.
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(dir, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if s := collectPathWatch(t, []config.PathRule{partial}, 5)[0]; !s.Truncated || !s.Stale {
+		t.Errorf("rule = %+v, want truncated and stale", s)
+	}
+	if !got[0].OverQuota {
+		t.Errorf("partial total %d bytes above 1 MiB quota, want OverQuota", got[0].TotalBytes)
+	}
+	if got[1].OverQuota {
+		t.Error("rule without QuotaMB reported OverQuota")
+	}
+
+	// Below quota after a truncated walk says nothing about the full total.
+	under := config.PathRule{Path: dir, QuotaMB: 2}
+	if s := collectPathWatch(t, []config.PathRule{under}, 5)[0]; s.OverQuota || !s.Truncated {
+		t.Errorf("rule = %+v, want truncated and not over quota", s)
+	}
+
+	got = collectPathWatch(t, []config.PathRule{{Path: dir}}, 20)
+	if got[0].FileCount != 20 || got[0].Truncated {
+		t.Errorf("exact budget = %+v, want 20 files, not truncated", got[0])
+	}
+}
+
+func TestPathWatch_DeadlineTruncates(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFile(t, filepath.Join(dir, "a"), 10, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := scanPathRule(ctx, config.PathRule{Path: dir, MaxAge: time.Minute}, 100, time.Now())
+	if !s.Truncated {
+		t.Errorf("status = %+v, want truncated by the cancelled context", s)
+	}
+}
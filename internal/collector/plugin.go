@@ -0,0 +1,207 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	// defaultPluginTimeout applies to plugins without a Timeout.
+	defaultPluginTimeout = 10 * time.Second
+	// pluginMaxMetrics caps the metrics accepted from one response.
+	pluginMaxMetrics = 1000
+)
+
+// PluginCollector runs an external executable declared in Monitor.json and
+// turns its JSON response into metrics, so equipment-specific values can be
+// collected without changing the agent. The collector is named after its
+// Collectors key.
+//
+// In "oneshot" mode the executable is run once per interval and must print
+// one JSON response to stdout and exit. In "daemon" mode it is started once
+// and kept running; each interval the agent writes "collect\n" to its stdin
+// and reads one JSON line from its stdout. The response is
+//
+//	{"metrics": [{"name": "wafer_count", "value": 1520, "proc": "LineA"}], "error": ""}
+//
+// A non-empty "error" fails the cycle. A daemon that times out is killed and
+// restarted on a later cycle with exponential backoff.
+type PluginCollector struct {
+	BaseCollector
+
+	mu     sync.Mutex
+	spec   *config.PluginSpec
+	daemon *pluginDaemon // daemon mode, started on first Collect
+}
+
+// pluginResponse is the JSON document a plugin prints per request.
+type pluginResponse struct {
+	Metrics []PluginMetric `json:"metrics"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// NewPluginCollector creates a plugin collector named name. It does nothing
+// until Configure provides a Plugin spec.
+func NewPluginCollector(name string) *PluginCollector {
+	return &PluginCollector{
+		BaseCollector: NewBaseCollector(name),
+	}
+}
+
+// RegisterPlugins registers a PluginCollector for every entry of configs
+// with a Plugin block, in name order, and returns them so the caller can
+// stop their daemons on shutdown. An entry whose name is taken by a
+// built-in collector is skipped and reported in errs.
+func RegisterPlugins(r *Registry, configs map[string]config.CollectorConfig) (plugins []*PluginCollector, errs []error) {
+	names := make([]string, 0, len(configs))
+	for name, cc := range configs {
+		if cc.Plugin != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := NewPluginCollector(name)
+		if err := r.Register(p); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", name, err))
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errs
+}
+
+// DefaultConfig returns the default CollectorConfig for a plugin collector.
+// It is disabled: a plugin removed from Monitor.json falls back to this on
+// hot reload, which stops its daemon.
+func (c *PluginCollector) DefaultConfig() config.CollectorConfig {
+	return config.CollectorConfig{
+		Enabled:  false,
+		Interval: 60 * time.Second,
+	}
+}
+
+// Configure applies the configuration to the collector. A changed spec, or
+// disabling the collector, stops a running daemon.
+func (c *PluginCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !cfg.Enabled || !reflect.DeepEqual(cfg.Plugin, c.spec) {
+		c.stopLocked()
+	}
+	c.spec = cfg.Plugin
+	return nil
+}
+
+// Stop shuts down the daemon process, if any.
+func (c *PluginCollector) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+}
+
+func (c *PluginCollector) stopLocked() {
+	if c.daemon != nil {
+		c.daemon.stop()
+		c.daemon = nil
+	}
+}
+
+// Collect runs one plugin request. Returns nil when no Plugin is configured.
+func (c *PluginCollector) Collect(ctx context.Context) (*MetricData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.spec == nil {
+		return nil, nil
+	}
+	timeout := c.spec.Timeout
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+
+	var (
+		payload []byte
+		err     error
+	)
+	if strings.EqualFold(c.spec.Mode, "daemon") {
+		payload, err = c.requestDaemon(ctx, timeout)
+	} else {
+		payload, err = runPluginOnce(ctx, c.Name(), resolvePluginCommand(c.spec.Command), c.spec.Args, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := c.parseResponse(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      PluginData{Plugin: c.Name(), Metrics: metrics},
+	}, nil
+}
+
+// requestDaemon starts the daemon if needed and performs one request. Any
+// failure stops the process so the next cycle restarts it after backoff.
+func (c *PluginCollector) requestDaemon(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	if c.daemon == nil {
+		c.daemon = newPluginDaemon(c.Name(), resolvePluginCommand(c.spec.Command), c.spec.Args)
+	}
+	if err := c.daemon.ensureRunning(); err != nil {
+		return nil, err
+	}
+	line, err := c.daemon.request(ctx, timeout)
+	if err != nil {
+		c.daemon.consecutiveFailures++
+		c.daemon.stop()
+		return nil, err
+	}
+	c.daemon.consecutiveFailures = 0
+	return line, nil
+}
+
+// parseResponse decodes a plugin response. Metrics without a name are
+// dropped, and at most pluginMaxMetrics are kept.
+func (c *PluginCollector) parseResponse(payload []byte) ([]PluginMetric, error) {
+	var resp pluginResponse
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: parse response (%d bytes): %w", c.Name(), len(payload), err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s error: %s", c.Name(), resp.Error)
+	}
+
+	metrics := make([]PluginMetric, 0, len(resp.Metrics))
+	dropped := 0
+	for _, m := range resp.Metrics {
+		if strings.TrimSpace(m.Name) == "" || len(metrics) == pluginMaxMetrics {
+			dropped++
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	if dropped > 0 {
+		log := logger.WithComponent("plugin")
+		log.Warn().Str("plugin", c.Name()).Int("dropped", dropped).Int("max_metrics", pluginMaxMetrics).
+			Msg("Plugin metrics without a name or beyond the limit were dropped")
+	}
+	return metrics, nil
+}
@@ -0,0 +1,406 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"resourceagent/internal/logger"
+)
+
+const (
+	// pluginMaxResponseBytes caps one plugin response so a runaway plugin
+	// cannot grow the agent's memory.
+	pluginMaxResponseBytes = 1024 * 1024
+	// pluginMaxStderrBytes is how much oneshot stderr is kept for the log.
+	pluginMaxStderrBytes = 4 * 1024
+	// pluginWaitDelay bounds how long a killed oneshot plugin's pipes may
+	// stay open (e.g. inherited by a grandchild) before Wait gives up.
+	pluginWaitDelay = 2 * time.Second
+	// pluginStopTimeout is how long a daemon may take to exit after its
+	// stdin is closed before it is killed.
+	pluginStopTimeout = 5 * time.Second
+	// pluginDrainTimeout bounds the wait for the request worker after a kill.
+	pluginDrainTimeout = 2 * time.Second
+)
+
+// resolvePluginCommand returns command as is when it is absolute, a bare
+// name (looked up in PATH), or exists relative to the working directory;
+// otherwise it is taken relative to the agent executable's directory.
+func resolvePluginCommand(command string) string {
+	if filepath.IsAbs(command) || !strings.ContainsAny(command, `/\`) {
+		return command
+	}
+	if _, err := os.Stat(command); err == nil {
+		return command
+	}
+	if exePath, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(exePath), command)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return command
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the
+// rest, so a chatty child never blocks on a full pipe.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// runPluginOnce runs a oneshot plugin and returns its stdout. The process is
+// killed when timeout or ctx expires; WaitDelay then bounds how long its
+// pipes are drained so a lingering grandchild cannot hang the collector.
+func runPluginOnce(ctx context.Context, name, path string, args []string, timeout time.Duration) ([]byte, error) {
+	log := logger.WithComponent("plugin")
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{max: pluginMaxResponseBytes}
+	stderr := &cappedBuffer{max: pluginMaxStderrBytes}
+	cmd := exec.CommandContext(runCtx, path, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = pluginWaitDelay
+
+	err := cmd.Run()
+	for _, line := range strings.Split(strings.TrimSpace(stderr.buf.String()), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			log.Info().Str("plugin", name).Str("stderr", line).Msg("Plugin")
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("plugin %s cancelled: %w", name, ctx.Err())
+	case runCtx.Err() != nil:
+		log.Error().Str("plugin", name).Dur("timeout", timeout).
+			Msg("PLUGIN_TIMEOUT_KILL oneshot plugin timed out and was killed")
+		return nil, fmt.Errorf("plugin %s timed out after %v", name, timeout)
+	case errors.Is(err, exec.ErrWaitDelay):
+		log.Warn().Str("plugin", name).
+			Msg("PLUGIN_DRAIN_TIMEOUT plugin exited but its output pipes stayed open; a child process may still be running")
+	case err != nil:
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("plugin %s response exceeds %d bytes", name, pluginMaxResponseBytes)
+	}
+	return stdout.buf.Bytes(), nil
+}
+
+// pluginDaemon is a long-running plugin process spoken to over stdin/stdout
+// pipes: the platform-neutral counterpart of LhmProvider's daemon handling.
+// A request that times out kills the process so the blocked pipe I/O
+// unwinds, and the worker goroutine is drained before returning. Failed
+// processes are restarted with exponential backoff.
+//
+// Not safe for concurrent use; PluginCollector serialises calls.
+type pluginDaemon struct {
+	name string
+	path string
+	args []string
+
+	cmd          *exec.Cmd
+	stdinFile    *os.File
+	stdoutFile   *os.File
+	stdoutReader *bufio.Reader
+	stderr       io.ReadCloser
+
+	// processExit is CLOSED (not drained) on exit, safe for multiple reads.
+	processExit chan struct{}
+
+	// Kept across restarts: every failed request stops the process, so
+	// the counters only make sense for the daemon as a whole.
+	consecutiveFailures int
+	consecutiveTimeouts int // timed out requests since the last response
+	lastStartAttempt    time.Time
+}
+
+func newPluginDaemon(name, path string, args []string) *pluginDaemon {
+	return &pluginDaemon{name: name, path: path, args: args}
+}
+
+// ensureRunning starts the daemon if it is not running, unless the restart
+// backoff (1s, 2s, 4s ... 60s after consecutive failures) has not elapsed.
+// Unlike LhmProvider it does not sleep out the backoff: the cycle is
+// skipped so the scheduler is never blocked.
+func (d *pluginDaemon) ensureRunning() error {
+	if d.alive() {
+		return nil
+	}
+	log := logger.WithComponent("plugin")
+
+	if d.consecutiveFailures > 0 {
+		backoffSeconds := 1 << d.consecutiveFailures
+		if backoffSeconds > 60 {
+			backoffSeconds = 60
+		}
+		backoff := time.Duration(backoffSeconds) * time.Second
+		if wait := backoff - time.Since(d.lastStartAttempt); wait > 0 {
+			log.Warn().Str("plugin", d.name).Int("consecutive_failures", d.consecutiveFailures).
+				Dur("backoff_wait", wait).Msg("PLUGIN_RESTART_BACKOFF plugin daemon restart delayed")
+			return fmt.Errorf("plugin %s restart backoff, next attempt in %v", d.name, wait.Round(time.Second))
+		}
+	}
+
+	d.stop() // release the pipes of a process that exited on its own
+	d.lastStartAttempt = time.Now()
+	if err := d.start(); err != nil {
+		d.consecutiveFailures++
+		return err
+	}
+	return nil
+}
+
+// start launches the daemon with os.Pipe stdin/stdout, like LhmProvider.
+func (d *pluginDaemon) start() error {
+	log := logger.WithComponent("plugin")
+
+	cmd := exec.Command(d.path, d.args...)
+
+	// childStdinR / childStdoutW are the child's ends; our copies are closed
+	// after Start so EOF propagates when the child exits.
+	childStdinR, parentStdinW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	parentStdoutR, childStdoutW, err := os.Pipe()
+	if err != nil {
+		childStdinR.Close()
+		parentStdinW.Close()
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stdin = childStdinR
+	cmd.Stdout = childStdoutW
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		childStdinR.Close()
+		parentStdinW.Close()
+		parentStdoutR.Close()
+		childStdoutW.Close()
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		childStdinR.Close()
+		parentStdinW.Close()
+		parentStdoutR.Close()
+		childStdoutW.Close()
+		return fmt.Errorf("failed to start plugin %s: %w", d.name, err)
+	}
+	childStdinR.Close()
+	childStdoutW.Close()
+
+	d.cmd = cmd
+	d.stdinFile = parentStdinW
+	d.stdoutFile = parentStdoutR
+	d.stdoutReader = bufio.NewReaderSize(parentStdoutR, 64*1024)
+	d.stderr = stderr
+	d.processExit = make(chan struct{})
+
+	// Capture exitCh by value so a restart creating a new channel won't
+	// cause this goroutine to close the wrong one.
+	exitCh := d.processExit
+	go func() {
+		cmd.Wait()
+		close(exitCh)
+	}()
+	go drainPluginStderr(d.name, stderr)
+
+	log.Info().Str("plugin", d.name).Int("pid", cmd.Process.Pid).Str("path", d.path).
+		Msg("Plugin daemon started")
+	return nil
+}
+
+// stop closes stdin to ask the daemon to exit, then waits or kills.
+func (d *pluginDaemon) stop() {
+	if d.cmd == nil || d.cmd.Process == nil {
+		return
+	}
+	log := logger.WithComponent("plugin")
+	pid := d.cmd.Process.Pid
+
+	if d.stdinFile != nil {
+		d.stdinFile.Close()
+		d.stdinFile = nil
+	}
+	if d.stderr != nil {
+		d.stderr.Close()
+		d.stderr = nil
+	}
+
+	select {
+	case <-d.processExit:
+		log.Info().Str("plugin", d.name).Int("pid", pid).Msg("Plugin daemon stopped")
+	case <-time.After(pluginStopTimeout):
+		log.Warn().Str("plugin", d.name).Int("pid", pid).Msg("Plugin daemon did not exit in time, killing")
+		d.cmd.Process.Kill()
+		<-d.processExit
+	}
+
+	if d.stdoutFile != nil {
+		d.stdoutFile.Close()
+		d.stdoutFile = nil
+	}
+	d.cmd = nil
+	d.stdoutReader = nil
+}
+
+// alive reports whether the daemon process is running.
+func (d *pluginDaemon) alive() bool {
+	if d.cmd == nil || d.cmd.Process == nil {
+		return false
+	}
+	select {
+	case <-d.processExit:
+		return false
+	default:
+		return true
+	}
+}
+
+// pluginRequestResult is what the request worker posts back.
+type pluginRequestResult struct {
+	line []byte
+	err  error
+}
+
+// request sends "collect\n" and reads one response line. On timeout or
+// cancellation the daemon is killed so the blocked worker unwinds, and the
+// worker is drained before returning (see LhmProvider.doRequestWithTimeout
+// for why pipe deadlines cannot be used on Windows). The caller stops the
+// daemon after any error so the next cycle restarts it.
+func (d *pluginDaemon) request(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	log := logger.WithComponent("plugin")
+
+	stdinFile := d.stdinFile
+	stdoutReader := d.stdoutReader
+	if stdinFile == nil || stdoutReader == nil {
+		return nil, fmt.Errorf("plugin %s pipes not available", d.name)
+	}
+
+	ch := make(chan pluginRequestResult, 1)
+	go func() {
+		if _, err := stdinFile.Write([]byte("collect\n")); err != nil {
+			ch <- pluginRequestResult{nil, fmt.Errorf("stdin write: %w", err)}
+			return
+		}
+		line, err := readPluginLine(stdoutReader)
+		if err != nil {
+			ch <- pluginRequestResult{nil, fmt.Errorf("stdout read: %w", err)}
+			return
+		}
+		ch <- pluginRequestResult{line, nil}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			log.Warn().Str("plugin", d.name).Err(r.err).Msg("PLUGIN_IO_ERROR pipe I/O failed")
+			return nil, fmt.Errorf("plugin %s request failed: %w", d.name, r.err)
+		}
+		if d.consecutiveTimeouts > 0 {
+			log.Info().Str("plugin", d.name).Int("prior_timeouts", d.consecutiveTimeouts).
+				Msg("PLUGIN_TIMEOUT_RECOVERED daemon responded after prior timeout streak")
+			d.consecutiveTimeouts = 0
+		}
+		return r.line, nil
+
+	case <-ctx.Done():
+		log.Warn().Str("plugin", d.name).Err(ctx.Err()).
+			Msg("PLUGIN_CTX_CANCELLED killing plugin daemon to release blocked I/O")
+		d.killAndDrain(ch, "ctx_cancelled")
+		return nil, fmt.Errorf("plugin %s request cancelled: %w", d.name, ctx.Err())
+
+	case <-timer.C:
+		d.consecutiveTimeouts++
+		log.Error().Str("plugin", d.name).Dur("timeout", timeout).Int("consecutive_timeouts", d.consecutiveTimeouts).
+			Msg("PLUGIN_TIMEOUT_KILL request timed out; killing plugin daemon to release blocked goroutine")
+		d.killAndDrain(ch, "timeout")
+		return nil, fmt.Errorf("plugin %s request timed out after %v", d.name, timeout)
+	}
+}
+
+// killAndDrain kills the daemon and waits (bounded) for the request worker
+// to observe the dead pipes and exit.
+func (d *pluginDaemon) killAndDrain(ch <-chan pluginRequestResult, reason string) {
+	log := logger.WithComponent("plugin")
+
+	if d.cmd != nil && d.cmd.Process != nil {
+		if err := d.cmd.Process.Kill(); err != nil {
+			log.Error().Str("plugin", d.name).Err(err).Str("reason", reason).
+				Msg("PLUGIN_KILL_FAILED Process.Kill failed")
+		}
+	}
+
+	select {
+	case <-ch:
+	case <-time.After(pluginDrainTimeout):
+		log.Error().Str("plugin", d.name).Str("reason", reason).
+			Msg("PLUGIN_DRAIN_TIMEOUT worker goroutine did not unwind within 2s; one goroutine leaked until next process restart")
+	}
+}
+
+// readPluginLine reads one '\n'-terminated line of at most
+// pluginMaxResponseBytes.
+func readPluginLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > pluginMaxResponseBytes {
+			return nil, fmt.Errorf("response exceeds %d bytes", pluginMaxResponseBytes)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return line, err
+	}
+}
+
+// drainPluginStderr forwards daemon stderr lines to the agent log. A panic
+// in the reader or logger is recovered so it cannot take the agent down.
+func drainPluginStderr(name string, r io.Reader) {
+	log := logger.WithComponent("plugin")
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Error().Str("plugin", name).Interface("panic", rec).Msg("drainPluginStderr panic recovered")
+		}
+	}()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		log.Info().Str("plugin", name).Str("stderr", scanner.Text()).Msg("Plugin")
+	}
+	if err := scanner.Err(); err != nil {
+		log.Warn().Str("plugin", name).Err(err).Msg("drainPluginStderr scanner error")
+	}
+}
@@ -0,0 +1,279 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+var (
+	fakePluginOnce sync.Once
+	fakePluginPath string
+	fakePluginErr  error
+)
+
+// buildFakePlugin compiles testdata/fake_plugin.go once per test process,
+// like buildFakeDaemon for the LhmProvider tests.
+func buildFakePlugin(t *testing.T) string {
+	t.Helper()
+	fakePluginOnce.Do(func() {
+		ext := ""
+		if runtime.GOOS == "windows" {
+			ext = ".exe"
+		}
+		dir, err := os.MkdirTemp("", "ra-fake-plugin-*")
+		if err != nil {
+			fakePluginErr = fmt.Errorf("mkdtemp: %w", err)
+			return
+		}
+		out := filepath.Join(dir, "fake_plugin"+ext)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "go", "build", "-o", out, filepath.Join("testdata", "fake_plugin.go"))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			fakePluginErr = fmt.Errorf("go build: %w\n%s", err, output)
+			return
+		}
+		fakePluginPath = out
+	})
+	if fakePluginErr != nil {
+		t.Fatalf("failed to build fake plugin: %v", fakePluginErr)
+	}
+	return fakePluginPath
+}
+
+func newTestPlugin(t *testing.T, mode string, args ...string) *PluginCollector {
+	t.Helper()
+	c := NewPluginCollector("FakePlugin")
+	spec := &config.PluginSpec{Command: buildFakePlugin(t), Args: args, Mode: mode, Timeout: 3 * time.Second}
+	if err := c.Configure(config.CollectorConfig{Enabled: true, Interval: time.Minute, Plugin: spec}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	t.Cleanup(c.Stop)
+	return c
+}
+
+func pluginMetrics(t *testing.T, m *MetricData) map[string]PluginMetric {
+	t.Helper()
+	d, ok := m.Data.(PluginData)
+	if !ok {
+		t.Fatalf("Data = %T, want PluginData", m.Data)
+	}
+	if m.Type != "FakePlugin" || d.Plugin != "FakePlugin" {
+		t.Errorf("Type = %q, Plugin = %q, want FakePlugin", m.Type, d.Plugin)
+	}
+	byName := make(map[string]PluginMetric, len(d.Metrics))
+	for _, pm := range d.Metrics {
+		byName[pm.Name] = pm
+	}
+	return byName
+}
+
+func TestPlugin_NoSpec(t *testing.T) {
+	c := NewPluginCollector("FakePlugin")
+	if c.DefaultConfig().Enabled {
+		t.Error("plugin collectors must default to disabled")
+	}
+	if m, err := c.Collect(context.Background()); m != nil || err != nil {
+		t.Errorf("Collect() = (%v, %v), want (nil, nil) without a spec", m, err)
+	}
+}
+
+func TestPlugin_Oneshot(t *testing.T) {
+	c := newTestPlugin(t, "", "oneshot", "normal")
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	got := pluginMetrics(t, m)
+	if len(got) != 2 {
+		t.Fatalf("got %d metrics, want 2 (unnamed one dropped): %+v", len(got), got)
+	}
+	if got["wafer_count"].Value != 1520 {
+		t.Errorf("wafer_count = %+v", got["wafer_count"])
+	}
+	if q := got["queue_depth"]; q.Value != 3 || q.Proc != "LoaderA" || q.PID != 42 {
+		t.Errorf("queue_depth = %+v, want 3 on LoaderA pid 42", q)
+	}
+}
+
+func TestPlugin_OneshotFailures(t *testing.T) {
+	tests := []struct {
+		mode    string
+		timeout time.Duration
+		wantErr string
+	}{
+		{"error", 0, "camera offline"},
+		{"fail", 0, "exit status 2"},
+		{"garbage", 0, "parse response"},
+		{"slow", 300 * time.Millisecond, "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			c := newTestPlugin(t, "oneshot", "oneshot", tt.mode)
+			if tt.timeout > 0 {
+				spec := *c.spec
+				spec.Timeout = tt.timeout
+				c.Configure(config.CollectorConfig{Enabled: true, Interval: time.Minute, Plugin: &spec})
+			}
+			start := time.Now()
+			m, err := c.Collect(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Collect() = (%v, %v), want error containing %q", m, err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Collect took %v, want the plugin killed at its timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestPlugin_Daemon(t *testing.T) {
+	c := newTestPlugin(t, "daemon", "daemon", "normal")
+
+	var pid float64
+	for i := 1; i <= 3; i++ {
+		m, err := c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect %d failed: %v", i, err)
+		}
+		got := pluginMetrics(t, m)
+		if got["requests"].Value != float64(i) {
+			t.Errorf("request %d: requests = %v, want %d (same process)", i, got["requests"].Value, i)
+		}
+		if i == 1 {
+			pid = got["pid"].Value
+		} else if got["pid"].Value != pid {
+			t.Errorf("request %d served by pid %v, want %v", i, got["pid"].Value, pid)
+		}
+	}
+
+	// A changed spec stops the running daemon; the next cycle starts a new one.
+	d := c.daemon
+	spec := *c.spec
+	spec.Timeout = 2 * time.Second
+	if err := c.Configure(config.CollectorConfig{Enabled: true, Interval: time.Minute, Plugin: &spec}); err != nil {
+		t.Fatal(err)
+	}
+	if d.alive() || c.daemon != nil {
+		t.Error("daemon still running after the spec changed")
+	}
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect after reconfigure failed: %v", err)
+	}
+	if got := pluginMetrics(t, m); got["requests"].Value != 1 {
+		t.Errorf("requests = %v after restart, want 1", got["requests"].Value)
+	}
+
+	// Disabling stops it too.
+	d = c.daemon
+	c.Configure(config.CollectorConfig{Enabled: false, Plugin: &spec})
+	if d.alive() {
+		t.Error("daemon still running after the plugin was disabled")
+	}
+}
+
+func TestPlugin_DaemonTimeoutKillsAndBacksOff(t *testing.T) {
+	c := newTestPlugin(t, "daemon", "daemon", "slow")
+	spec := *c.spec
+	spec.Timeout = 300 * time.Millisecond
+	c.Configure(config.CollectorConfig{Enabled: true, Interval: time.Minute, Plugin: &spec})
+
+	before := runtime.NumGoroutine()
+	if _, err := c.Collect(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Collect error = %v, want timeout", err)
+	}
+	if c.daemon.alive() {
+		t.Error("daemon still alive after a timed out request")
+	}
+	// The request worker, exit watcher and stderr drain have all unwound.
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("goroutines = %d after timeout, want <= %d", n, before)
+	}
+
+	if _, err := c.Collect(context.Background()); err == nil || !strings.Contains(err.Error(), "backoff") {
+		t.Errorf("Collect right after a failure = %v, want restart backoff", err)
+	}
+}
+
+func TestPluginDaemon_TimeoutStreakSurvivesRestart(t *testing.T) {
+	d := newPluginDaemon("FakePlugin", buildFakePlugin(t), []string{"daemon", "normal"})
+	defer d.stop()
+	// As left by two timed out requests, each of which stopped the process.
+	d.consecutiveTimeouts = 2
+
+	if err := d.ensureRunning(); err != nil {
+		t.Fatalf("ensureRunning failed: %v", err)
+	}
+	if d.consecutiveTimeouts != 2 {
+		t.Errorf("consecutiveTimeouts = %d after restart, want 2 kept", d.consecutiveTimeouts)
+	}
+	if _, err := d.request(context.Background(), 3*time.Second); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if d.consecutiveTimeouts != 0 {
+		t.Errorf("consecutiveTimeouts = %d after a response, want 0", d.consecutiveTimeouts)
+	}
+}
+
+func TestPlugin_DaemonCrashRestarts(t *testing.T) {
+	c := newTestPlugin(t, "daemon", "daemon", "crash")
+
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("first Collect failed: %v", err)
+	}
+	// The fake exits after answering; the next request finds it dead and
+	// restarts it without backoff because no request has failed yet.
+	c.daemon.mustExit(t)
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect after crash failed: %v", err)
+	}
+	if got := pluginMetrics(t, m); got["requests"].Value != 1 {
+		t.Errorf("requests = %v, want 1 from a fresh process", got["requests"].Value)
+	}
+}
+
+// mustExit waits for the daemon process to exit on its own.
+func (d *pluginDaemon) mustExit(t *testing.T) {
+	t.Helper()
+	select {
+	case <-d.processExit:
+	case <-time.After(3 * time.Second):
+		t.Fatal("daemon did not exit")
+	}
+}
+
+func TestRegisterPlugins(t *testing.T) {
+	r := NewRegistry()
+	r.Register(NewCPUCollector())
+	plugins, errs := RegisterPlugins(r, map[string]config.CollectorConfig{
+		"VisionStats": {Enabled: true, Plugin: &config.PluginSpec{Command: "vision_stats"}},
+		"CPU":         {Enabled: true, Plugin: &config.PluginSpec{Command: "cpu_plugin"}},
+		"Memory":      {Enabled: true},
+	})
+	if len(plugins) != 1 || plugins[0].Name() != "VisionStats" {
+		t.Errorf("plugins = %v, want only VisionStats", plugins)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "CPU") {
+		t.Errorf("errs = %v, want the CPU name clash", errs)
+	}
+	if _, ok := r.Get("VisionStats"); !ok {
+		t.Error("VisionStats not registered")
+	}
+}
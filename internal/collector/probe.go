@@ -0,0 +1,285 @@
+package collector
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	// defaultProbeTimeout applies to targets without a Timeout.
+	defaultProbeTimeout = 5 * time.Second
+	// probeMaxBodyBytes is how much of an HTTP response body is drained
+	// before closing; the probe only needs the status line.
+	probeMaxBodyBytes = 64 * 1024
+)
+
+// DialFunc dials a connection, e.g. through the SOCKS5 proxy.
+type DialFunc func(network, addr string) (net.Conn, error)
+
+// ProbeEndpoints are the agent's own upstream addresses ("host:port") for
+// the redis, servicediscovery and kafkarest probe types. An empty field is
+// not used by this agent (file sender) and its probes are skipped.
+type ProbeEndpoints struct {
+	Redis            string
+	ServiceDiscovery string
+	KafkaRest        string
+}
+
+// ProbeCollector runs the configured TCP-connect and HTTP GET checks
+// concurrently each cycle and reports success, latency and HTTP status code
+// per target, so an unreachable PLC, MES or file server shows up even while
+// the NIC has traffic.
+//
+// Targets with Proxy set, and the agent's own endpoints, go through the
+// SOCKS5 dialer installed with SetNetwork when ResourceAgent.json configures
+// SOCKSProxy; everything else is dialed directly.
+type ProbeCollector struct {
+	BaseCollector
+
+	mu        sync.RWMutex
+	targets   []config.ProbeTarget
+	dial      DialFunc
+	endpoints func() ProbeEndpoints
+}
+
+// NewProbeCollector creates a new reachability probe collector.
+func NewProbeCollector() *ProbeCollector {
+	return &ProbeCollector{
+		BaseCollector: NewBaseCollector("Probe"),
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the probe collector.
+func (c *ProbeCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 60 * time.Second
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *ProbeCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.targets = cfg.Probes
+	return nil
+}
+
+// SetNetwork installs the SOCKS5 dialer (nil when no proxy is configured)
+// and the resolver of the agent's own endpoints. endpoints is called every
+// cycle so a KafkaRest address refreshed from ServiceDiscovery is followed.
+func (c *ProbeCollector) SetNetwork(dial DialFunc, endpoints func() ProbeEndpoints) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dial = dial
+	c.endpoints = endpoints
+}
+
+// Collect probes every target. Returns nil when no targets are configured.
+func (c *ProbeCollector) Collect(ctx context.Context) (*MetricData, error) {
+	c.mu.RLock()
+	targets := c.targets
+	dial := c.dial
+	endpointsFunc := c.endpoints
+	c.mu.RUnlock()
+
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	var endpoints ProbeEndpoints
+	if endpointsFunc != nil {
+		endpoints = endpointsFunc()
+	}
+
+	results := make([]*ProbeResult, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		r, ok := resolveProbeTarget(t, endpoints, dial)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, t config.ProbeTarget, r ProbeResult) {
+			defer wg.Done()
+			runProbe(ctx, &r, t.Timeout, dial)
+			results[i] = &r
+		}(i, t, r)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	data := ProbeData{Probes: make([]ProbeResult, 0, len(results))}
+	log := logger.WithComponent("collector")
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if !r.Success {
+			log.Debug().Str("collector", c.Name()).Str("probe", r.Name).Str("address", r.Address).
+				Bool("proxy", r.Proxy).Str("error", r.Error).Msg("Probe failed")
+		}
+		data.Probes = append(data.Probes, *r)
+	}
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      data,
+	}, nil
+}
+
+// resolveProbeTarget fills in the address and route of t. Agent endpoint
+// types are skipped (ok=false) when the agent has no such endpoint.
+func resolveProbeTarget(t config.ProbeTarget, endpoints ProbeEndpoints, dial DialFunc) (r ProbeResult, ok bool) {
+	r = ProbeResult{Name: t.Name, Type: strings.ToLower(t.Type), Address: t.Address, Proxy: t.Proxy && dial != nil}
+	switch r.Type {
+	case "redis":
+		r.Address = endpoints.Redis
+	case "servicediscovery":
+		r.Address = endpoints.ServiceDiscovery
+	case "kafkarest":
+		r.Address = kafkaRestHostPort(endpoints.KafkaRest)
+	}
+	switch r.Type {
+	case "redis", "servicediscovery", "kafkarest":
+		// The agent reaches its own endpoints through the proxy when one is
+		// configured; probe them the same way.
+		r.Proxy = dial != nil
+		if r.Name == "" {
+			r.Name = r.Type
+		}
+		if r.Address == "" {
+			return r, false
+		}
+	}
+	if r.Name == "" {
+		r.Name = r.Address
+	}
+	return r, true
+}
+
+// kafkaRestHostPort strips the scheme ServiceDiscovery may include.
+func kafkaRestHostPort(addr string) string {
+	addr = strings.TrimPrefix(addr, "http://")
+	addr = strings.TrimPrefix(addr, "https://")
+	return strings.TrimSuffix(addr, "/")
+}
+
+// runProbe performs one check and records the outcome in r.
+func runProbe(ctx context.Context, r *ProbeResult, timeout time.Duration, dial DialFunc) {
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if !r.Proxy {
+		dial = nil
+	}
+	start := time.Now()
+	var err error
+	if r.Type == "http" {
+		r.StatusCode, err = probeHTTP(ctx, r.Address, dial)
+		if err == nil && r.StatusCode >= 400 {
+			err = fmt.Errorf("HTTP %d", r.StatusCode)
+		}
+	} else {
+		var conn net.Conn
+		if conn, err = probeDial(ctx, dial, "tcp", r.Address); err == nil {
+			conn.Close()
+		}
+	}
+	r.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	r.Success = err == nil
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timeout after %s", timeout)
+		}
+		r.Error = err.Error()
+	}
+}
+
+// probeHTTP sends a GET without following redirects, so the reported status
+// is the target's own, and returns the status code. A fresh connection is
+// used for every probe so the latency includes the connect.
+func probeHTTP(ctx context.Context, rawURL string, dial DialFunc) (int, error) {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return probeDial(ctx, dial, network, addr)
+		},
+		TLSClientConfig:   &tls.Config{MinVersion: tls.VersionTLS12},
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err // drop the repeated method and URL
+		}
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, probeMaxBodyBytes))
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// probeDial dials addr directly, or through dial when set. The proxy dialer
+// takes no context, so it runs in a goroutine that closes a connection
+// arriving after ctx is done.
+func probeDial(ctx context.Context, dial DialFunc, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan dialResult, 1)
+	go func() {
+		conn, err := dial(network, addr)
+		ch <- dialResult{conn, err}
+	}()
+	select {
+	case res := <-ch:
+		return res.conn, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-ch; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
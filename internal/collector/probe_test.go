@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+func collectProbes(t *testing.T, c *ProbeCollector, targets []config.ProbeTarget) []ProbeResult {
+	t.Helper()
+	if err := c.Configure(config.CollectorConfig{Enabled: true, Probes: targets}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	d, ok := m.Data.(ProbeData)
+	if !ok {
+		t.Fatalf("Data = %T, want ProbeData", m.Data)
+	}
+	return d.Probes
+}
+
+// closedPort returns a loopback address nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestProbe_NoConfig(t *testing.T) {
+	c := NewProbeCollector()
+	if m, err := c.Collect(context.Background()); m != nil || err != nil {
+		t.Errorf("Collect() = (%v, %v), want (nil, nil) without targets", m, err)
+	}
+}
+
+func TestProbe_TCPAndHTTP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/moved":
+			http.Redirect(w, r, "/down", http.StatusFound)
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	got := collectProbes(t, NewProbeCollector(), []config.ProbeTarget{
+		{Name: "PLC", Type: "tcp", Address: ln.Addr().String()},
+		{Type: "tcp", Address: closedPort(t)},
+		{Name: "MES", Type: "HTTP", Address: srv.URL + "/health"},
+		{Type: "http", Address: srv.URL + "/down"},
+		{Type: "http", Address: srv.URL + "/moved"},
+		{Type: "http", Address: srv.URL + "/slow", Timeout: 50 * time.Millisecond},
+	})
+	if len(got) != 6 {
+		t.Fatalf("got %d results, want 6", len(got))
+	}
+
+	if r := got[0]; r.Name != "PLC" || !r.Success || r.LatencyMs <= 0 || r.Error != "" {
+		t.Errorf("open port = %+v, want success with latency", r)
+	}
+	if r := got[1]; r.Name != r.Address || r.Success || r.Error == "" {
+		t.Errorf("closed port = %+v, want failure named after the address", r)
+	}
+	if r := got[2]; r.Name != "MES" || r.Type != "http" || !r.Success || r.StatusCode != 200 {
+		t.Errorf("http 200 = %+v, want success", r)
+	}
+	if r := got[3]; r.Success || r.StatusCode != 503 {
+		t.Errorf("http 503 = %+v, want failure with the status code", r)
+	}
+	if r := got[4]; !r.Success || r.StatusCode != 302 {
+		t.Errorf("redirect = %+v, want the 302 itself, not followed", r)
+	}
+	if r := got[5]; r.Success || r.StatusCode != 0 || !strings.Contains(r.Error, "timeout") {
+		t.Errorf("slow = %+v, want a timeout failure", r)
+	}
+}
+
+func TestProbe_ProxyAndAgentEndpoints(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	addr := ln.Addr().String()
+
+	var (
+		mu     sync.Mutex
+		dialed []string
+	)
+	c := NewProbeCollector()
+	c.SetNetwork(func(network, a string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, a)
+		mu.Unlock()
+		return net.Dial(network, a)
+	}, func() ProbeEndpoints {
+		return ProbeEndpoints{Redis: addr, KafkaRest: "http://" + addr + "/"}
+	})
+
+	got := collectProbes(t, c, []config.ProbeTarget{
+		{Name: "direct", Type: "tcp", Address: addr},
+		{Name: "proxied", Type: "tcp", Address: addr, Proxy: true},
+		{Type: "redis", Proxy: false},
+		{Type: "servicediscovery"},
+		{Name: "KR", Type: "kafkarest"},
+	})
+
+	// servicediscovery has no endpoint on this agent and is skipped.
+	if len(got) != 4 {
+		t.Fatalf("got %d results, want 4: %+v", len(got), got)
+	}
+	if got[0].Proxy || !got[1].Proxy {
+		t.Errorf("proxy flags = %v, %v, want false, true", got[0].Proxy, got[1].Proxy)
+	}
+	if r := got[2]; r.Name != "redis" || r.Address != addr || !r.Proxy || !r.Success {
+		t.Errorf("redis = %+v, want success through the agent's proxy", r)
+	}
+	if r := got[3]; r.Name != "KR" || r.Address != addr || !r.Success {
+		t.Errorf("kafkarest = %+v, want scheme stripped and success", r)
+	}
+	if len(dialed) != 3 {
+		t.Errorf("proxy dialer used %d times, want 3 (proxied, redis, kafkarest)", len(dialed))
+	}
+}
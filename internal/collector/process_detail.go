@@ -0,0 +1,266 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+// processDetailMaxCmdlineBytes truncates reported command lines.
+const processDetailMaxCmdlineBytes = 512
+
+// ProcessDetailCollector reports health details (threads, handles/fds,
+// children, uptime, executable path, command line) for the processes listed
+// in WatchProcesses and RequiredProcesses, and flags processes whose handle
+// or thread count has grown monotonically over GrowthWindow.
+type ProcessDetailCollector struct {
+	BaseCollector
+	matcher *ProcessMatcher // WatchProcesses + RequiredProcesses
+	growth  *growthTracker
+}
+
+// NewProcessDetailCollector creates a new process detail collector.
+func NewProcessDetailCollector() *ProcessDetailCollector {
+	return &ProcessDetailCollector{
+		BaseCollector: NewBaseCollector("ProcessDetail"),
+		matcher:       NewProcessMatcher(nil),
+		growth:        newGrowthTracker(time.Hour),
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the process detail collector.
+func (c *ProcessDetailCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 60 * time.Second
+	cfg.GrowthWindow = time.Hour
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+// GrowthWindow 0 disables growth detection.
+func (c *ProcessDetailCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+	names := make([]string, 0, len(cfg.WatchProcesses)+len(cfg.RequiredProcesses))
+	names = append(names, cfg.WatchProcesses...)
+	names = append(names, cfg.RequiredProcesses...)
+	c.matcher = NewProcessMatcher(names)
+	if cfg.GrowthWindow != c.growth.window {
+		c.growth = newGrowthTracker(cfg.GrowthWindow)
+	}
+	return nil
+}
+
+// Collect gathers details for every running instance of a watched process.
+// Returns nil when no process names are configured.
+func (c *ProcessDetailCollector) Collect(ctx context.Context) (*MetricData, error) {
+	if !c.matcher.HasWatchList() {
+		return nil, nil
+	}
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var watched []*process.Process
+	names := make(map[int32]string)
+	for _, p := range procs {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		name, err := p.NameWithContext(ctx)
+		if err != nil || name == "" {
+			continue
+		}
+		if c.matcher.IsWatched(name) {
+			watched = append(watched, p)
+			names[p.Pid] = name
+		}
+	}
+
+	now := time.Now()
+	var children map[int32]int
+	if len(watched) > 0 {
+		children = countChildren(parentPIDs(ctx, procs))
+	}
+
+	log := logger.WithComponent("process-detail")
+	seen := make(map[growthKey]struct{}, len(watched))
+	details := make([]ProcessDetail, 0, len(watched))
+	for _, p := range watched {
+		createTime, _ := p.CreateTimeWithContext(ctx)
+		threads, _ := p.NumThreadsWithContext(ctx)
+		exe, _ := p.ExeWithContext(ctx)
+		// The command line stays in the collector JSON only: it may carry
+		// credentials or connection strings, so it is neither logged nor
+		// sent as an EARS row.
+		cmdline, _ := p.CmdlineWithContext(ctx)
+		if len(cmdline) > processDetailMaxCmdlineBytes {
+			cmdline = cmdline[:processDetailMaxCmdlineBytes]
+		}
+
+		d := ProcessDetail{
+			PID:         p.Pid,
+			Name:        names[p.Pid],
+			Exe:         exe,
+			Cmdline:     cmdline,
+			NumThreads:  threads,
+			NumChildren: children[p.Pid],
+			CreateTime:  createTime,
+		}
+		if createTime > 0 {
+			d.UptimeSeconds = now.Sub(time.UnixMilli(createTime)).Seconds()
+		}
+		handles := int32(-1)
+		if n, err := processHandleCount(ctx, p); err == nil {
+			handles = n
+			d.NumHandles = &n
+		}
+
+		key := growthKey{pid: p.Pid, createTime: createTime}
+		seen[key] = struct{}{}
+		if c.growth.isNew(key) {
+			log.Info().
+				Int32("pid", d.PID).
+				Str("name", d.Name).
+				Str("exe", d.Exe).
+				Msg("PROCESS_DETAIL_NEW: watched process instance detected")
+		}
+		d.HandleGrowing, d.ThreadGrowing = c.growth.observe(key, now, handles, threads, func(metric string, from, to int32) {
+			log.Warn().
+				Int32("pid", d.PID).
+				Str("name", d.Name).
+				Str("metric", metric).
+				Int32("from", from).
+				Int32("to", to).
+				Dur("window", c.growth.window).
+				Msg("PROCESS_RESOURCE_GROWTH: count grew monotonically over the growth window")
+		})
+		details = append(details, d)
+	}
+	c.growth.prune(seen)
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: now,
+		Data:      ProcessDetailData{Processes: details},
+	}, nil
+}
+
+// countChildren converts a PID → parent PID map into parent PID → child count.
+func countChildren(parents map[int32]int32) map[int32]int {
+	counts := make(map[int32]int, len(parents))
+	for pid, ppid := range parents {
+		if ppid != 0 && ppid != pid {
+			counts[ppid]++
+		}
+	}
+	return counts
+}
+
+// growthKey identifies a process instance; createTime guards against PID reuse.
+type growthKey struct {
+	pid        int32
+	createTime int64
+}
+
+type growthSample struct {
+	at      time.Time
+	handles int32 // -1 when unreadable
+	threads int32
+}
+
+type growthHistory struct {
+	samples        []growthSample
+	handlesGrowing bool
+	threadsGrowing bool
+}
+
+// growthTracker keeps per-process handle/thread samples covering the growth
+// window. A count is "growing" when the samples span at least the window,
+// there are at least three of them, no sample is lower than the previous
+// one, and the last is higher than the first.
+type growthTracker struct {
+	window  time.Duration // 0 disables detection
+	history map[growthKey]*growthHistory
+}
+
+func newGrowthTracker(window time.Duration) *growthTracker {
+	return &growthTracker{window: window, history: make(map[growthKey]*growthHistory)}
+}
+
+// isNew reports whether key has not been observed yet.
+func (g *growthTracker) isNew(key growthKey) bool {
+	_, ok := g.history[key]
+	return !ok
+}
+
+// observe records a sample and returns the growth flags. onRise is called
+// when a flag turns on, with the count at the start and end of the window.
+func (g *growthTracker) observe(key growthKey, now time.Time, handles, threads int32, onRise func(metric string, from, to int32)) (handlesGrowing, threadsGrowing bool) {
+	h, ok := g.history[key]
+	if !ok {
+		h = &growthHistory{}
+		g.history[key] = h
+	}
+	if g.window <= 0 {
+		return false, false
+	}
+
+	h.samples = append(h.samples, growthSample{at: now, handles: handles, threads: threads})
+	// Keep one sample at or before the window start so the history spans it.
+	cutoff := now.Add(-g.window)
+	for len(h.samples) >= 2 && !h.samples[1].at.After(cutoff) {
+		h.samples = h.samples[1:]
+	}
+
+	handlesGrowing, threadsGrowing = false, false
+	if len(h.samples) >= 3 && !h.samples[0].at.After(cutoff) {
+		handlesGrowing = monotonicGrowth(h.samples, func(s growthSample) int32 { return s.handles })
+		threadsGrowing = monotonicGrowth(h.samples, func(s growthSample) int32 { return s.threads })
+	}
+
+	first, last := h.samples[0], h.samples[len(h.samples)-1]
+	if handlesGrowing && !h.handlesGrowing && onRise != nil {
+		onRise("handles", first.handles, last.handles)
+	}
+	if threadsGrowing && !h.threadsGrowing && onRise != nil {
+		onRise("threads", first.threads, last.threads)
+	}
+	h.handlesGrowing, h.threadsGrowing = handlesGrowing, threadsGrowing
+	return handlesGrowing, threadsGrowing
+}
+
+// prune drops the history of process instances that were not seen this cycle.
+func (g *growthTracker) prune(seen map[growthKey]struct{}) {
+	for key := range g.history {
+		if _, ok := seen[key]; !ok {
+			delete(g.history, key)
+		}
+	}
+}
+
+func monotonicGrowth(samples []growthSample, value func(growthSample) int32) bool {
+	prev := value(samples[0])
+	if prev < 0 {
+		return false
+	}
+	for _, s := range samples[1:] {
+		v := value(s)
+		if v < prev {
+			return false
+		}
+		prev = v
+	}
+	return prev > value(samples[0])
+}
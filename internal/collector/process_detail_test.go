@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"resourceagent/internal/config"
+)
+
+func TestGrowthTracker_FlagsMonotonicGrowth(t *testing.T) {
+	g := newGrowthTracker(10 * time.Minute)
+	key := growthKey{pid: 42, createTime: 1}
+	start := time.Date(2026, 2, 5, 10, 0, 0, 0, time.UTC)
+
+	var risen []string
+	onRise := func(metric string, from, to int32) {
+		risen = append(risen, metric)
+		if metric == "handles" && (from != 100 || to != 110) {
+			t.Errorf("onRise handles from %d to %d, want 100 → 110", from, to)
+		}
+	}
+
+	// Handles climb every 5 minutes, threads stay flat.
+	var handlesGrowing, threadsGrowing bool
+	for i := 0; i <= 2; i++ {
+		handlesGrowing, threadsGrowing = g.observe(key, start.Add(time.Duration(i)*5*time.Minute), int32(100+i*5), 8, onRise)
+	}
+	if !handlesGrowing || threadsGrowing {
+		t.Errorf("after one full window: handles=%v threads=%v, want true/false", handlesGrowing, threadsGrowing)
+	}
+	if len(risen) != 1 || risen[0] != "handles" {
+		t.Errorf("onRise calls = %v, want [handles]", risen)
+	}
+
+	// Still growing: no second rising edge.
+	g.observe(key, start.Add(15*time.Minute), 120, 8, onRise)
+	if len(risen) != 1 {
+		t.Errorf("onRise calls = %v, want a single rising edge", risen)
+	}
+
+	// A drop inside the window clears the flag.
+	handlesGrowing, _ = g.observe(key, start.Add(20*time.Minute), 90, 8, onRise)
+	if handlesGrowing {
+		t.Error("handles still flagged after a drop inside the window")
+	}
+}
+
+func TestGrowthTracker_RequiresFullWindow(t *testing.T) {
+	g := newGrowthTracker(time.Hour)
+	key := growthKey{pid: 1}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		h, th := g.observe(key, start.Add(time.Duration(i)*time.Minute), int32(i), int32(i), nil)
+		if h || th {
+			t.Fatalf("sample %d: flagged growth before the window was covered", i)
+		}
+	}
+}
+
+func TestGrowthTracker_UnreadableHandlesAndDisabled(t *testing.T) {
+	g := newGrowthTracker(time.Minute)
+	key := growthKey{pid: 1}
+	start := time.Now()
+	var h bool
+	for i := 0; i < 3; i++ {
+		h, _ = g.observe(key, start.Add(time.Duration(i)*time.Minute), -1, 4, nil)
+	}
+	if h {
+		t.Error("unreadable handle count (-1) must not be flagged")
+	}
+
+	off := newGrowthTracker(0)
+	for i := 0; i < 5; i++ {
+		h, th := off.observe(key, start.Add(time.Duration(i)*time.Hour), int32(i), int32(i), nil)
+		if h || th {
+			t.Fatal("GrowthWindow 0 must disable detection")
+		}
+	}
+}
+
+func TestGrowthTracker_Prune(t *testing.T) {
+	g := newGrowthTracker(time.Minute)
+	alive, gone := growthKey{pid: 1}, growthKey{pid: 2}
+	g.observe(alive, time.Now(), 1, 1, nil)
+	g.observe(gone, time.Now(), 1, 1, nil)
+
+	g.prune(map[growthKey]struct{}{alive: {}})
+	if g.isNew(alive) || !g.isNew(gone) {
+		t.Error("prune must keep seen instances and drop the rest")
+	}
+}
+
+func TestCountChildren(t *testing.T) {
+	got := countChildren(map[int32]int32{1: 0, 10: 1, 11: 1, 20: 10, 30: 30})
+	if got[1] != 2 || got[10] != 1 || got[30] != 0 {
+		t.Errorf("countChildren = %v, want 1→2, 10→1, self-parented ignored", got)
+	}
+}
+
+func TestProcessDetailCollector_NoWatchList(t *testing.T) {
+	c := NewProcessDetailCollector()
+	if err := c.Configure(c.DefaultConfig()); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	metric, err := c.Collect(context.Background())
+	if metric != nil || err != nil {
+		t.Errorf("Collect = (%v, %v), want (nil, nil) without watched names", metric, err)
+	}
+}
+
+func TestProcessDetailCollector_Collect_Self(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("NewProcess: %v", err)
+	}
+	name, err := self.Name()
+	if err != nil || name == "" {
+		t.Skipf("process name unavailable: %v", err)
+	}
+
+	c := NewProcessDetailCollector()
+	if err := c.Configure(config.CollectorConfig{
+		Enabled:           true,
+		Interval:          60 * time.Second,
+		RequiredProcesses: []string{name},
+		GrowthWindow:      time.Hour,
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metric, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if metric.Type != "ProcessDetail" {
+		t.Errorf("Type = %q, want %q", metric.Type, "ProcessDetail")
+	}
+	data, ok := metric.Data.(ProcessDetailData)
+	if !ok {
+		t.Fatalf("Data is not ProcessDetailData")
+	}
+
+	for _, p := range data.Processes {
+		if p.PID != self.Pid {
+			continue
+		}
+		if p.NumThreads <= 0 {
+			t.Errorf("NumThreads = %d, want > 0", p.NumThreads)
+		}
+		if p.NumHandles == nil || *p.NumHandles <= 0 {
+			t.Errorf("NumHandles = %v, want own handle/fd count", p.NumHandles)
+		}
+		if p.Exe == "" || p.UptimeSeconds <= 0 {
+			t.Errorf("Exe = %q, UptimeSeconds = %v, want both set", p.Exe, p.UptimeSeconds)
+		}
+		if p.HandleGrowing || p.ThreadGrowing {
+			t.Error("growth flagged on the first sample")
+		}
+		return
+	}
+	t.Errorf("test process %q (PID %d) not reported", name, self.Pid)
+}
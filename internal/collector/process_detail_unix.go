@@ -0,0 +1,27 @@
+//go:build linux || darwin
+
+package collector
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// processHandleCount returns the number of open file descriptors
+// (/proc/[pid]/fd). Reading another user's fd table requires root.
+func processHandleCount(ctx context.Context, p *process.Process) (int32, error) {
+	return p.NumFDsWithContext(ctx)
+}
+
+// parentPIDs returns PID → parent PID for procs. On Linux each lookup reads
+// /proc/[pid]/stat, so the whole table costs one small read per process.
+func parentPIDs(ctx context.Context, procs []*process.Process) map[int32]int32 {
+	parents := make(map[int32]int32, len(procs))
+	for _, p := range procs {
+		if ppid, err := p.PpidWithContext(ctx); err == nil {
+			parents[p.Pid] = ppid
+		}
+	}
+	return parents
+}
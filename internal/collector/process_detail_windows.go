@@ -0,0 +1,51 @@
+//go:build windows
+
+package collector
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// PROCESS_QUERY_LIMITED_INFORMATION (Vista+, Win7 OK) is enough for
+// GetProcessHandleCount and is granted for most non-protected processes.
+const processQueryLimitedInformation = 0x1000
+
+// processHandleCount returns the kernel handle count of p via
+// GetProcessHandleCount (procGetProcessHandleCount, selfmetrics_windows.go).
+func processHandleCount(_ context.Context, p *process.Process) (int32, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(p.Pid))
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.CloseHandle(h)
+
+	var count uint32
+	r1, _, e1 := procGetProcessHandleCount.Call(uintptr(h), uintptr(unsafe.Pointer(&count)))
+	if r1 == 0 {
+		return 0, e1
+	}
+	return int32(count), nil
+}
+
+// parentPIDs returns PID → parent PID from a single toolhelp snapshot.
+// gopsutil's Ppid takes a full snapshot per call, which is O(n²) over the
+// process table.
+func parentPIDs(_ context.Context, _ []*process.Process) map[int32]int32 {
+	parents := make(map[int32]int32)
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return parents
+	}
+	defer syscall.CloseHandle(snap)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = syscall.Process32First(snap, &entry); err == nil; err = syscall.Process32Next(snap, &entry) {
+		parents[int32(entry.ProcessID)] = int32(entry.ParentProcessID)
+	}
+	return parents
+}
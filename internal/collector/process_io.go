@@ -0,0 +1,235 @@
+package collector
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"resourceagent/internal/config"
+)
+
+// ioSample is the cumulative I/O counter snapshot of a process from the
+// previous cycle. The name guards against PID reuse between cycles.
+type ioSample struct {
+	name     string
+	counters process.IOCountersStat
+}
+
+// ioQuickInfo holds per-process rates from the 1st pass.
+type ioQuickInfo struct {
+	proc    *process.Process
+	name    string
+	watched bool
+	rates   ioRates
+}
+
+// ioRates are per-second deltas of process.IOCountersStat.
+type ioRates struct {
+	readBytes  float64
+	writeBytes float64
+	readOps    float64
+	writeOps   float64
+}
+
+// total is the ranking key for TopN selection.
+func (r ioRates) total() float64 { return r.readBytes + r.writeBytes }
+
+// ioMinHeap is a min-heap of ioQuickInfo ordered by total bytes/s (lowest at top).
+type ioMinHeap []ioQuickInfo
+
+func (h ioMinHeap) Len() int            { return len(h) }
+func (h ioMinHeap) Less(i, j int) bool  { return h[i].rates.total() < h[j].rates.total() }
+func (h ioMinHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *ioMinHeap) Push(x interface{}) { *h = append(*h, x.(ioQuickInfo)) }
+func (h *ioMinHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// ProcessIOCollector collects per-process disk I/O rates.
+//
+// Rates are derived from cumulative counters: /proc/[pid]/io
+// (read_bytes/write_bytes, syscr/syscw) on Linux and GetProcessIoCounters on
+// Windows. On Windows the byte counters include all I/O the process issues
+// (file, network and device), not only disk.
+type ProcessIOCollector struct {
+	BaseCollector
+	topN           int             // Number of top processes to report
+	watchProcesses []string        // List of process names to always include
+	matcher        *ProcessMatcher // For efficient process name matching
+
+	last        map[int32]ioSample // Counters from the previous cycle
+	lastCollect time.Time
+}
+
+// NewProcessIOCollector creates a new process I/O collector.
+func NewProcessIOCollector() *ProcessIOCollector {
+	return &ProcessIOCollector{
+		BaseCollector: NewBaseCollector("ProcessIO"),
+		topN:          10,
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the process I/O collector.
+func (c *ProcessIOCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = 30 * time.Second
+	cfg.TopN = 10
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *ProcessIOCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+	if cfg.TopN >= 0 {
+		c.topN = cfg.TopN
+	}
+	c.watchProcesses = cfg.WatchProcesses
+	c.matcher = NewProcessMatcher(cfg.WatchProcesses)
+	return nil
+}
+
+// Collect gathers per-process I/O rates using the same 2-pass approach as
+// CPUProcessCollector.
+// 1st pass: read I/O counters and name for all processes, compute rates
+// against the previous cycle
+// 2nd pass: collect detailed info (username, createTime) only for selected processes
+//
+// The first call only records baseline counters and returns nil. Processes
+// that are new since the previous cycle, whose counters went backwards, or
+// whose PID was reused by a different executable are skipped for one cycle.
+// Counters of processes owned by other users may be unreadable without
+// elevated privileges; those processes are skipped.
+func (c *ProcessIOCollector) Collect(ctx context.Context) (*MetricData, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	prev := c.last
+	elapsed := now.Sub(c.lastCollect).Seconds()
+	warmup := c.lastCollect.IsZero()
+
+	// 1st Pass: I/O counters + Name (2 syscalls per process).
+	next := make(map[int32]ioSample, len(procs))
+	var watchedList []ioQuickInfo
+	h := &ioMinHeap{}
+
+	for _, p := range procs {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		counters, err := p.IOCountersWithContext(ctx)
+		if err != nil || counters == nil {
+			continue
+		}
+		name, _ := p.NameWithContext(ctx)
+		if name == "" {
+			continue
+		}
+		next[p.Pid] = ioSample{name: name, counters: *counters}
+
+		old, ok := prev[p.Pid]
+		if warmup || !ok || old.name != name {
+			continue
+		}
+		rates, ok := ioRatesBetween(old.counters, *counters, elapsed)
+		if !ok {
+			continue
+		}
+
+		q := ioQuickInfo{
+			proc:    p,
+			name:    name,
+			watched: c.matcher.IsWatched(name),
+			rates:   rates,
+		}
+
+		if q.watched {
+			watchedList = append(watchedList, q)
+		} else {
+			// Min-heap: keep top remainingSlots items
+			remainingSlots := c.topN - len(watchedList)
+			if remainingSlots <= 0 {
+				continue
+			}
+			if h.Len() < remainingSlots {
+				heap.Push(h, q)
+			} else if q.rates.total() > (*h)[0].rates.total() {
+				(*h)[0] = q
+				heap.Fix(h, 0)
+			}
+		}
+	}
+
+	c.last = next
+	c.lastCollect = now
+	if warmup {
+		return nil, nil
+	}
+
+	// Sort watched by total bytes/s descending (for consistent ordering)
+	sort.Slice(watchedList, func(i, j int) bool {
+		return watchedList[i].rates.total() > watchedList[j].rates.total()
+	})
+
+	// Extract heap items sorted descending
+	heapItems := make([]ioQuickInfo, h.Len())
+	for i := h.Len() - 1; i >= 0; i-- {
+		heapItems[i] = heap.Pop(h).(ioQuickInfo)
+	}
+
+	// 2nd Pass: detailed info only for selected processes (2 syscalls per process)
+	processList := make([]ProcessIO, 0, len(watchedList)+len(heapItems))
+	for _, q := range append(watchedList, heapItems...) {
+		username, _ := q.proc.UsernameWithContext(ctx)
+		createTime, _ := q.proc.CreateTimeWithContext(ctx)
+
+		processList = append(processList, ProcessIO{
+			PID:              q.proc.Pid,
+			Name:             q.name,
+			Username:         username,
+			ReadBytesPerSec:  q.rates.readBytes,
+			WriteBytesPerSec: q.rates.writeBytes,
+			ReadOpsPerSec:    q.rates.readOps,
+			WriteOpsPerSec:   q.rates.writeOps,
+			CreateTime:       createTime,
+			Watched:          q.watched,
+		})
+	}
+
+	return &MetricData{
+		Type:      c.Name(),
+		Timestamp: now,
+		Data:      ProcessIOData{Processes: processList},
+	}, nil
+}
+
+// ioRatesBetween returns per-second rates between two counter snapshots.
+// ok is false when elapsed is not positive or any counter decreased.
+func ioRatesBetween(prev, cur process.IOCountersStat, elapsed float64) (ioRates, bool) {
+	if elapsed <= 0 ||
+		cur.ReadBytes < prev.ReadBytes || cur.WriteBytes < prev.WriteBytes ||
+		cur.ReadCount < prev.ReadCount || cur.WriteCount < prev.WriteCount {
+		return ioRates{}, false
+	}
+	return ioRates{
+		readBytes:  float64(cur.ReadBytes-prev.ReadBytes) / elapsed,
+		writeBytes: float64(cur.WriteBytes-prev.WriteBytes) / elapsed,
+		readOps:    float64(cur.ReadCount-prev.ReadCount) / elapsed,
+		writeOps:   float64(cur.WriteCount-prev.WriteCount) / elapsed,
+	}, true
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"resourceagent/internal/config"
+)
+
+func TestIORatesBetween(t *testing.T) {
+	prev := process.IOCountersStat{ReadCount: 10, WriteCount: 4, ReadBytes: 4096, WriteBytes: 1024}
+	cur := process.IOCountersStat{ReadCount: 30, WriteCount: 14, ReadBytes: 24576, WriteBytes: 3072}
+
+	r, ok := ioRatesBetween(prev, cur, 2)
+	if !ok {
+		t.Fatal("ioRatesBetween ok = false")
+	}
+	if r.readBytes != 10240 || r.writeBytes != 1024 || r.readOps != 10 || r.writeOps != 5 {
+		t.Errorf("rates = %+v, want read 10240 B/s, write 1024 B/s, 10/5 ops/s", r)
+	}
+	if r.total() != 11264 {
+		t.Errorf("total = %v, want 11264", r.total())
+	}
+}
+
+func TestIORatesBetween_Invalid(t *testing.T) {
+	prev := process.IOCountersStat{ReadCount: 10, ReadBytes: 4096}
+
+	if _, ok := ioRatesBetween(prev, prev, 0); ok {
+		t.Error("zero elapsed must be rejected")
+	}
+	if _, ok := ioRatesBetween(prev, process.IOCountersStat{ReadCount: 11, ReadBytes: 0}, 1); ok {
+		t.Error("decreasing counter must be rejected")
+	}
+}
+
+func TestProcessIOCollector_DefaultConfig(t *testing.T) {
+	cfg := NewProcessIOCollector().DefaultConfig()
+	if !cfg.Enabled || cfg.Interval != 30*time.Second || cfg.TopN != 10 {
+		t.Errorf("DefaultConfig = %+v, want enabled, 30s, TopN 10", cfg)
+	}
+}
+
+func TestProcessIOCollector_Collect_WarmupThenRates(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("NewProcess: %v", err)
+	}
+	if _, err := self.IOCounters(); err != nil {
+		t.Skipf("I/O counters unavailable in this environment: %v", err)
+	}
+	name, _ := self.Name()
+
+	c := NewProcessIOCollector()
+	if err := c.Configure(config.CollectorConfig{
+		Enabled:        true,
+		Interval:       30 * time.Second,
+		TopN:           3,
+		WatchProcesses: []string{name},
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metric, err := c.Collect(ctx)
+	if err != nil || metric != nil {
+		t.Fatalf("first Collect = (%v, %v), want (nil, nil) warmup", metric, err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	metric, err = c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if metric.Type != "ProcessIO" {
+		t.Errorf("Type = %q, want %q", metric.Type, "ProcessIO")
+	}
+	data, ok := metric.Data.(ProcessIOData)
+	if !ok {
+		t.Fatalf("Data is not ProcessIOData")
+	}
+
+	var found bool
+	nonWatched := 0
+	for i, p := range data.Processes {
+		if p.ReadBytesPerSec < 0 || p.WriteBytesPerSec < 0 || p.ReadOpsPerSec < 0 || p.WriteOpsPerSec < 0 {
+			t.Errorf("negative rate: %+v", p)
+		}
+		if p.Watched {
+			if i > 0 && !data.Processes[i-1].Watched {
+				t.Errorf("watched process %s at index %d after a non-watched one", p.Name, i)
+			}
+			if p.PID == self.Pid {
+				found = true
+			}
+		} else {
+			nonWatched++
+		}
+	}
+	if !found {
+		t.Errorf("test process %q (PID %d) not reported as watched", name, self.Pid)
+	}
+	if nonWatched > 3 {
+		t.Errorf("non-watched processes = %d, want at most TopN", nonWatched)
+	}
+}
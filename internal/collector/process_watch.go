@@ -2,22 +2,41 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
 
 	"resourceagent/internal/config"
 )
 
-// ProcessWatchCollector monitors required and forbidden processes.
-// Required processes should always be running; forbidden processes should never run.
+// ProcessWatchCollector monitors required and forbidden processes and
+// listening ports. Required processes should always be running and required
+// ports always listening; forbidden ones should never be.
 type ProcessWatchCollector struct {
 	BaseCollector
 	requiredProcesses  []string
 	forbiddenProcesses []string
 	requiredMatcher    *ProcessMatcher
 	forbiddenMatcher   *ProcessMatcher
+	requiredPorts      []portRule
+	forbiddenPorts     []portRule
+}
+
+// portRule is a parsed config.PortRule.
+type portRule struct {
+	spec    string // "tcp:5000" as configured
+	proto   string // "tcp" or "udp"
+	port    uint32
+	process string // expected owner, "" = any
+}
+
+// portKey identifies a listening socket by protocol and local port.
+type portKey struct {
+	proto string
+	port  uint32
 }
 
 // NewProcessWatchCollector creates a new process watch collector.
@@ -39,20 +58,42 @@ func (c *ProcessWatchCollector) Configure(cfg config.CollectorConfig) error {
 	c.forbiddenProcesses = cfg.ForbiddenProcesses
 	c.requiredMatcher = NewProcessMatcher(cfg.RequiredProcesses)
 	c.forbiddenMatcher = NewProcessMatcher(cfg.ForbiddenProcesses)
+
+	var err error
+	if c.requiredPorts, err = parsePortRules(cfg.RequiredPorts); err != nil {
+		return err
+	}
+	if c.forbiddenPorts, err = parsePortRules(cfg.ForbiddenPorts); err != nil {
+		return err
+	}
 	return nil
 }
 
-// Collect checks the running status of all watched processes.
+func parsePortRules(rules []config.PortRule) ([]portRule, error) {
+	parsed := make([]portRule, 0, len(rules))
+	for _, r := range rules {
+		proto, port, err := config.ParsePortSpec(r.Port)
+		if err != nil {
+			return nil, fmt.Errorf("process watch: %w", err)
+		}
+		parsed = append(parsed, portRule{spec: r.Port, proto: proto, port: uint32(port), process: r.Process})
+	}
+	return parsed, nil
+}
+
+// Collect checks the running status of all watched processes and ports.
 func (c *ProcessWatchCollector) Collect(ctx context.Context) (*MetricData, error) {
-	// Skip entirely when both lists are empty (no-op)
-	if !c.requiredMatcher.HasWatchList() && !c.forbiddenMatcher.HasWatchList() {
+	hasProcesses := c.requiredMatcher.HasWatchList() || c.forbiddenMatcher.HasWatchList()
+	hasPorts := len(c.requiredPorts) > 0 || len(c.forbiddenPorts) > 0
+	// Skip entirely when all lists are empty (no-op)
+	if !hasProcesses && !hasPorts {
 		return nil, nil
 	}
 
 	// Build a map of matched process name → PID from running processes
 	pidMap := make(map[string]int32) // lowered/original name → PID
 
-	{
+	if hasProcesses {
 		procs, err := process.ProcessesWithContext(ctx)
 		if err != nil {
 			return nil, err
@@ -100,13 +141,92 @@ func (c *ProcessWatchCollector) Collect(ctx context.Context) (*MetricData, error
 		})
 	}
 
+	var ports []PortWatchStatus
+	if hasPorts {
+		listeners, err := c.listeningSockets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nameOf := processNameLookup(ctx)
+		ports = make([]PortWatchStatus, 0, len(c.requiredPorts)+len(c.forbiddenPorts))
+		ports = append(ports, c.evaluatePortRules(c.requiredPorts, "required", listeners, nameOf)...)
+		ports = append(ports, c.evaluatePortRules(c.forbiddenPorts, "forbidden", listeners, nameOf)...)
+	}
+
 	return &MetricData{
 		Type:      c.Name(),
 		Timestamp: time.Now(),
-		Data:      ProcessWatchData{Statuses: statuses},
+		Data:      ProcessWatchData{Statuses: statuses, Ports: ports},
 	}, nil
 }
 
+// listeningSockets returns the owner PIDs of every listening socket for the
+// protocols used by the port rules. A TCP socket listens in the LISTEN
+// state; a UDP socket listens when it is not connected to a remote port.
+func (c *ProcessWatchCollector) listeningSockets(ctx context.Context) (map[portKey][]int32, error) {
+	protos := make(map[string]struct{}, 2)
+	for _, rules := range [][]portRule{c.requiredPorts, c.forbiddenPorts} {
+		for _, r := range rules {
+			protos[r.proto] = struct{}{}
+		}
+	}
+
+	listeners := make(map[portKey][]int32)
+	for proto := range protos {
+		conns, err := net.ConnectionsWithContext(ctx, proto)
+		if err != nil {
+			return nil, err
+		}
+		for _, conn := range conns {
+			if proto == "tcp" && conn.Status != "LISTEN" {
+				continue
+			}
+			if proto == "udp" && conn.Raddr.Port != 0 {
+				continue
+			}
+			key := portKey{proto: proto, port: conn.Laddr.Port}
+			listeners[key] = append(listeners[key], conn.Pid)
+		}
+	}
+	return listeners, nil
+}
+
+// evaluatePortRules checks each rule against the listening sockets. Owner
+// names are compared with the same case sensitivity as process names.
+//
+// On Linux, socket owners are resolved by scanning /proc/[pid]/fd, which
+// only covers the agent's own user unless it runs as root; sockets of other
+// users come with PID 0. Such a socket may belong to the expected process,
+// so a required rule counts it as listening with an unknown owner rather
+// than raising a false alert. A forbidden rule naming a Process still needs
+// a resolved owner to match.
+func (c *ProcessWatchCollector) evaluatePortRules(rules []portRule, typ string, listeners map[portKey][]int32, nameOf func(pid int32) string) []PortWatchStatus {
+	statuses := make([]PortWatchStatus, 0, len(rules))
+	for _, r := range rules {
+		s := PortWatchStatus{Port: r.spec, Process: r.process, Type: typ}
+		unknownOwner := false
+		for _, pid := range listeners[portKey{proto: r.proto, port: r.port}] {
+			owner := nameOf(pid)
+			if r.process == "" || matchesConfigName(r.process, owner, c.requiredMatcher) {
+				s.Listening, s.PID, s.Owner = true, pid, owner
+				break
+			}
+			if owner == "" {
+				unknownOwner = true
+				continue
+			}
+			if s.PID == 0 {
+				s.PID, s.Owner = pid, owner
+			}
+		}
+		if !s.Listening && unknownOwner && typ == "required" {
+			s.Listening, s.PID, s.Owner = true, 0, ""
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
 // findPID looks up a process name in the PID map using the matcher's case sensitivity.
 func findPID(pidMap map[string]int32, configName string, matcher *ProcessMatcher) (int32, bool) {
 	for runningName, pid := range pidMap {
@@ -2,6 +2,8 @@ package collector
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 	"runtime"
 	"testing"
@@ -164,6 +166,104 @@ func TestProcessWatchCollector_MetricType(t *testing.T) {
 	}
 }
 
+func TestProcessWatchCollector_Configure_InvalidPort(t *testing.T) {
+	c := NewProcessWatchCollector()
+	err := c.Configure(config.CollectorConfig{
+		Enabled:       true,
+		RequiredPorts: []config.PortRule{{Port: "tcp5000"}},
+	})
+	if err == nil {
+		t.Error("expected error for malformed port spec")
+	}
+}
+
+func TestEvaluatePortRules(t *testing.T) {
+	c := NewProcessWatchCollector()
+	if err := c.Configure(config.CollectorConfig{
+		Enabled: true,
+		RequiredPorts: []config.PortRule{
+			{Port: "tcp:5000", Process: "EqpHost.exe"},
+			{Port: "tcp:5001", Process: "EqpHost.exe"},
+			{Port: "tcp:5002"},
+			{Port: "tcp:5003", Process: "EqpHost.exe"},
+		},
+		ForbiddenPorts: []config.PortRule{{Port: "tcp:3389"}, {Port: "udp:161"}, {Port: "tcp:5003", Process: "EqpHost.exe"}},
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	listeners := map[portKey][]int32{
+		{proto: "tcp", port: 5000}: {0, 812}, // owner of the first socket not visible
+		{proto: "tcp", port: 5001}: {900},
+		{proto: "tcp", port: 3389}: {1100},
+		{proto: "tcp", port: 161}:  {1200}, // wrong protocol for udp:161
+		{proto: "tcp", port: 5003}: {0},    // other user's socket, owner unknown
+	}
+	names := map[int32]string{812: "EqpHost.exe", 900: "other.exe", 1100: "svchost.exe"}
+	nameOf := func(pid int32) string { return names[pid] }
+
+	required := c.evaluatePortRules(c.requiredPorts, "required", listeners, nameOf)
+	want := []PortWatchStatus{
+		{Port: "tcp:5000", Process: "EqpHost.exe", Listening: true, PID: 812, Owner: "EqpHost.exe", Type: "required"},
+		{Port: "tcp:5001", Process: "EqpHost.exe", Listening: false, PID: 900, Owner: "other.exe", Type: "required"},
+		{Port: "tcp:5002", Type: "required"},
+		{Port: "tcp:5003", Process: "EqpHost.exe", Listening: true, Type: "required"},
+	}
+	for i, w := range want {
+		if required[i] != w {
+			t.Errorf("required[%d] = %+v, want %+v", i, required[i], w)
+		}
+	}
+
+	forbidden := c.evaluatePortRules(c.forbiddenPorts, "forbidden", listeners, nameOf)
+	if !forbidden[0].Listening || forbidden[0].PID != 1100 || forbidden[0].Owner != "svchost.exe" {
+		t.Errorf("forbidden[0] = %+v, want tcp:3389 listening by svchost.exe", forbidden[0])
+	}
+	if forbidden[2].Listening {
+		t.Errorf("forbidden[2] = %+v, want an unknown owner not to match a forbidden Process", forbidden[2])
+	}
+	if forbidden[1].Listening {
+		t.Errorf("forbidden[1] = %+v, want udp:161 not listening", forbidden[1])
+	}
+}
+
+func TestProcessWatchCollector_CollectPorts(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	c := NewProcessWatchCollector()
+	if err := c.Configure(config.CollectorConfig{
+		Enabled:        true,
+		Interval:       60 * time.Second,
+		RequiredPorts:  []config.PortRule{{Port: fmt.Sprintf("tcp:%d", port), Process: selfProcessName(t)}},
+		ForbiddenPorts: []config.PortRule{{Port: "tcp:1"}},
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metric, err := c.Collect(ctx)
+	if err != nil {
+		t.Skipf("socket table unavailable in this environment: %v", err)
+	}
+	data := metric.Data.(ProcessWatchData)
+	if len(data.Statuses) != 0 || len(data.Ports) != 2 {
+		t.Fatalf("data = %+v, want 0 process and 2 port statuses", data)
+	}
+	if !data.Ports[0].Listening || data.Ports[0].PID != int32(os.Getpid()) {
+		t.Errorf("required port = %+v, want listening by PID %d", data.Ports[0], os.Getpid())
+	}
+	if data.Ports[1].Listening {
+		t.Errorf("forbidden port = %+v, want not listening", data.Ports[1])
+	}
+}
+
 // selfProcessName returns the name of the current process (the test binary).
 func selfProcessName(t *testing.T) string {
 	t.Helper()
@@ -105,9 +105,12 @@ func DefaultRegistry() *Registry {
 	_ = r.Register(NewMemoryCollector())
 	_ = r.Register(NewDiskCollector())
 	_ = r.Register(NewNetworkCollector())
+	_ = r.Register(NewConnectionsCollector())
 	_ = r.Register(NewTemperatureCollector())
 	_ = r.Register(NewCPUProcessCollector())
 	_ = r.Register(NewMemoryProcessCollector())
+	_ = r.Register(NewProcessIOCollector())
+	_ = r.Register(NewProcessDetailCollector())
 	_ = r.Register(NewFanCollector())
 	_ = r.Register(NewGpuCollector())
 	_ = r.Register(NewStorageSmartCollector())
@@ -116,6 +119,11 @@ func DefaultRegistry() *Registry {
 	_ = r.Register(NewUptimeCollector())
 	_ = r.Register(NewProcessWatchCollector())
 	_ = r.Register(NewStorageHealthCollector())
+	_ = r.Register(NewLogWatchCollector())
+	_ = r.Register(NewInventoryCollector())
+	_ = r.Register(NewSoftwareCollector())
+	_ = r.Register(NewPathWatchCollector())
+	_ = r.Register(NewProbeCollector())
 
 	return r
 }
@@ -34,6 +34,32 @@ type BufferStatsProvider interface {
 	BufferStats() (count, dropped, hwm int64)
 }
 
+// SpoolStatsProvider mirrors sender.SpoolStatsProvider. SelfMetricsCollector
+// probes the configured BufferStatsProvider for it, so no extra wiring is
+// needed in main.go. ok is false when no on-disk spool is attached.
+type SpoolStatsProvider interface {
+	SpoolStats() (depth, bytes, replayed, expired int64, ok bool)
+}
+
+// BreakerStatsProvider mirrors sender.BreakerStatsProvider and is probed the
+// same way as SpoolStatsProvider. ok is false when the transport has no
+// circuit breaker.
+type BreakerStatsProvider interface {
+	BreakerStats() (state, opens, failures int64, ok bool)
+}
+
+// DeliveryStatsProvider mirrors sender.DeliveryStatsProvider (kafka sender
+// ack tracking). ok is false when the transport does not track acks.
+type DeliveryStatsProvider interface {
+	DeliveryStats() (acked, failed, inFlight int64, lastErr string, ok bool)
+}
+
+// CompressionStatsProvider mirrors sender.CompressionStatsProvider (KafkaRest
+// request compression). ok is false when compression is off.
+type CompressionStatsProvider interface {
+	CompressionStats() (rawBytes, wireBytes int64, ok bool)
+}
+
 // SelfMetricsCollector emits a snapshot of agent runtime metrics on every
 // Collect cycle (Phase 2.5-1). Sent through the standard pipeline as
 // MetricData{Type: "SelfMetrics"}.
@@ -84,18 +110,60 @@ func (c *SelfMetricsCollector) Collect(_ context.Context) (*MetricData, error) {
 		bufCount, bufDropped, _ = c.bufferStats.BufferStats()
 	}
 
+	data := SelfMetricsData{
+		GoroutineCount:     c.stats.NumGoroutine(),
+		RSSBytes:           rss,
+		HeapAllocBytes:     c.stats.AllocBytes(),
+		HeapSysBytes:       c.stats.SysBytes(),
+		HandleCount:        handles,
+		BufferCount:        bufCount,
+		BufferDroppedTotal: bufDropped,
+	}
+	if sp, ok := c.bufferStats.(SpoolStatsProvider); ok {
+		if depth, bytes, replayed, expired, attached := sp.SpoolStats(); attached {
+			data.Spool = &SpoolMetrics{
+				Depth:         depth,
+				Bytes:         bytes,
+				ReplayedTotal: replayed,
+				ExpiredTotal:  expired,
+			}
+		}
+	}
+	if bp, ok := c.bufferStats.(BreakerStatsProvider); ok {
+		if state, opens, failures, attached := bp.BreakerStats(); attached {
+			data.Breaker = &BreakerMetrics{
+				State:               state,
+				OpenTotal:           opens,
+				ConsecutiveFailures: failures,
+			}
+		}
+	}
+	if dp, ok := c.bufferStats.(DeliveryStatsProvider); ok {
+		if acked, failed, inFlight, _, attached := dp.DeliveryStats(); attached {
+			data.Delivery = &DeliveryMetrics{
+				AckedTotal:  acked,
+				FailedTotal: failed,
+				InFlight:    inFlight,
+			}
+		}
+	}
+
+	if cp, ok := c.bufferStats.(CompressionStatsProvider); ok {
+		if raw, wire, attached := cp.CompressionStats(); attached {
+			data.Compression = &CompressionMetrics{
+				RawBytesTotal:  raw,
+				WireBytesTotal: wire,
+			}
+			if raw > 0 {
+				data.Compression.Ratio = float64(wire) / float64(raw)
+			}
+		}
+	}
+
 	return &MetricData{
 		Type:      c.Name(),
 		Timestamp: time.Now(),
-		Data: SelfMetricsData{
-			GoroutineCount:     c.stats.NumGoroutine(),
-			RSSBytes:           rss,
-			HeapAllocBytes:     c.stats.AllocBytes(),
-			HeapSysBytes:       c.stats.SysBytes(),
-			HandleCount:        handles,
-			BufferCount:        bufCount,
-			BufferDroppedTotal: bufDropped,
-		},
+		Data:      data,
 	}, nil
 }
 
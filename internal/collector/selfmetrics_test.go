@@ -85,6 +85,122 @@ func TestSelfMetricsCollector_BasicCollect(t *testing.T) {
 	}
 }
 
+type mockSpoolBufferStats struct {
+	mockBufferStats
+	attached bool
+}
+
+func (m *mockSpoolBufferStats) SpoolStats() (int64, int64, int64, int64, bool) {
+	return 12, 4096, 30, 2, m.attached
+}
+
+func TestSelfMetricsCollector_SpoolStats(t *testing.T) {
+	c := NewSelfMetricsCollector(&mockRuntimeStats{}, &mockSpoolBufferStats{attached: true})
+	md, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	d := md.Data.(SelfMetricsData)
+	if d.Spool == nil {
+		t.Fatal("Spool = nil, want stats when a spool is attached")
+	}
+	if d.Spool.Depth != 12 || d.Spool.Bytes != 4096 || d.Spool.ReplayedTotal != 30 || d.Spool.ExpiredTotal != 2 {
+		t.Errorf("Spool = %+v, want {12 4096 30 2}", *d.Spool)
+	}
+
+	c = NewSelfMetricsCollector(&mockRuntimeStats{}, &mockSpoolBufferStats{attached: false})
+	md, _ = c.Collect(context.Background())
+	if d := md.Data.(SelfMetricsData); d.Spool != nil {
+		t.Errorf("Spool = %+v, want nil when no spool is attached", *d.Spool)
+	}
+}
+
+type mockBreakerBufferStats struct {
+	mockBufferStats
+}
+
+func (m *mockBreakerBufferStats) BreakerStats() (int64, int64, int64, bool) {
+	return 2, 4, 7, true
+}
+
+func TestSelfMetricsCollector_BreakerStats(t *testing.T) {
+	c := NewSelfMetricsCollector(&mockRuntimeStats{}, &mockBreakerBufferStats{})
+	md, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	d := md.Data.(SelfMetricsData)
+	if d.Breaker == nil {
+		t.Fatal("Breaker = nil, want stats when the transport has a breaker")
+	}
+	if d.Breaker.State != 2 || d.Breaker.OpenTotal != 4 || d.Breaker.ConsecutiveFailures != 7 {
+		t.Errorf("Breaker = %+v, want {2 4 7}", *d.Breaker)
+	}
+	if d.Spool != nil {
+		t.Errorf("Spool = %+v, want nil for a provider without SpoolStats", *d.Spool)
+	}
+}
+
+type mockDeliveryBufferStats struct {
+	mockBufferStats
+	attached bool
+}
+
+func (m *mockDeliveryBufferStats) DeliveryStats() (int64, int64, int64, string, bool) {
+	return 500, 3, 20, "kafka: client has run out of available brokers", m.attached
+}
+
+func TestSelfMetricsCollector_DeliveryStats(t *testing.T) {
+	c := NewSelfMetricsCollector(&mockRuntimeStats{}, &mockDeliveryBufferStats{attached: true})
+	md, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	d := md.Data.(SelfMetricsData)
+	if d.Delivery == nil {
+		t.Fatal("Delivery = nil, want stats when the transport tracks acks")
+	}
+	if d.Delivery.AckedTotal != 500 || d.Delivery.FailedTotal != 3 || d.Delivery.InFlight != 20 {
+		t.Errorf("Delivery = %+v, want {500 3 20}", *d.Delivery)
+	}
+
+	c = NewSelfMetricsCollector(&mockRuntimeStats{}, &mockDeliveryBufferStats{attached: false})
+	md, _ = c.Collect(context.Background())
+	if d := md.Data.(SelfMetricsData); d.Delivery != nil {
+		t.Errorf("Delivery = %+v, want nil without ack tracking", *d.Delivery)
+	}
+}
+
+type mockCompressionBufferStats struct {
+	mockBufferStats
+	attached bool
+}
+
+func (m *mockCompressionBufferStats) CompressionStats() (int64, int64, bool) {
+	return 4000, 1000, m.attached
+}
+
+func TestSelfMetricsCollector_CompressionStats(t *testing.T) {
+	c := NewSelfMetricsCollector(&mockRuntimeStats{}, &mockCompressionBufferStats{attached: true})
+	md, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	d := md.Data.(SelfMetricsData)
+	if d.Compression == nil {
+		t.Fatal("Compression = nil, want stats when compression is on")
+	}
+	if d.Compression.RawBytesTotal != 4000 || d.Compression.WireBytesTotal != 1000 || d.Compression.Ratio != 0.25 {
+		t.Errorf("Compression = %+v, want {4000 1000 0.25}", *d.Compression)
+	}
+
+	c = NewSelfMetricsCollector(&mockRuntimeStats{}, &mockCompressionBufferStats{attached: false})
+	md, _ = c.Collect(context.Background())
+	if d := md.Data.(SelfMetricsData); d.Compression != nil {
+		t.Errorf("Compression = %+v, want nil when compression is off", *d.Compression)
+	}
+}
+
 func TestSelfMetricsCollector_HandleProbeFailureSwallowed(t *testing.T) {
 	stats := &mockRuntimeStats{
 		goroutines: 5,
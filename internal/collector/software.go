@@ -0,0 +1,211 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+// defaultSoftwareStateFile keeps the package list of the last cycle the
+// sender accepted, so a restart reports only changes made while it was down.
+const defaultSoftwareStateFile = "log/ResourceAgent/software_state.json"
+
+// SoftwareCollector enumerates installed packages (dpkg or rpm on Linux,
+// the Uninstall registry keys on Windows) and reports the package count
+// every cycle, plus the packages installed, removed or upgraded since the
+// previous cycle. The diff baseline only advances, and is saved to
+// StateFile, when the scheduler reports the cycle was accepted by the sender
+// (Sent), so the events of a lost send are reported again on the next
+// cycle and a restart only reports what changed while the agent was down.
+// The first cycle without saved state records a baseline and reports no
+// changes.
+type SoftwareCollector struct {
+	BaseCollector
+
+	mu          sync.Mutex
+	statePath   string
+	state       *softwareState // nil before the baseline
+	pending     *softwareState // next baseline, once pendingData is Sent
+	pendingData *MetricData
+	loaded      bool // state restored from statePath
+	list        func(ctx context.Context) (source string, pkgs []SoftwarePackage, err error)
+}
+
+// softwareState is the persisted package list, by name.
+type softwareState struct {
+	Source   string                     `json:"source"`
+	Packages map[string]SoftwarePackage `json:"packages"`
+}
+
+// NewSoftwareCollector creates a new installed software collector.
+func NewSoftwareCollector() *SoftwareCollector {
+	return &SoftwareCollector{
+		BaseCollector: NewBaseCollector("Software"),
+		statePath:     defaultSoftwareStateFile,
+		list:          listInstalledSoftware,
+	}
+}
+
+// DefaultConfig returns the default CollectorConfig for the software collector.
+func (c *SoftwareCollector) DefaultConfig() config.CollectorConfig {
+	cfg := c.BaseCollector.DefaultConfig()
+	cfg.Interval = time.Hour
+	return cfg
+}
+
+// Configure applies the configuration to the collector.
+func (c *SoftwareCollector) Configure(cfg config.CollectorConfig) error {
+	c.SetEnabled(cfg.Enabled)
+	if cfg.Interval > 0 {
+		c.SetInterval(cfg.Interval)
+	}
+
+	statePath := cfg.StateFile
+	if statePath == "" {
+		statePath = defaultSoftwareStateFile
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if statePath != c.statePath {
+		c.statePath = statePath
+		c.loaded = false
+	}
+	return nil
+}
+
+// Collect enumerates installed packages and diffs them against the previous
+// cycle. Returns nil when no package source is available on this host.
+func (c *SoftwareCollector) Collect(ctx context.Context) (*MetricData, error) {
+	source, pkgs, err := c.list(ctx)
+	if err != nil {
+		// Never diff a partial list: every missing package would be
+		// reported as removed.
+		return nil, err
+	}
+	if source == "" {
+		return nil, nil
+	}
+	cur := softwareState{Source: source, Packages: mergePackages(pkgs)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log := logger.WithComponent("collector")
+	if !c.loaded {
+		var saved softwareState
+		if err := loadJSONState(c.statePath, &saved); err != nil {
+			log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).
+				Msg("Failed to load software state, recording a new baseline")
+		}
+		c.state = nil
+		if saved.Packages != nil {
+			c.state = &saved
+		}
+		c.loaded = true
+	}
+
+	data := SoftwareData{Source: source, PackageCount: len(cur.Packages)}
+	changed := true
+	if c.state != nil && c.state.Source == source {
+		data.Changes = diffSoftware(c.state.Packages, cur.Packages)
+		changed = len(data.Changes) > 0
+		logSoftwareChanges(data.Changes)
+	}
+
+	m := &MetricData{
+		Type:      c.Name(),
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+	c.pending, c.pendingData = nil, nil
+	if changed {
+		c.pending, c.pendingData = &cur, m
+	}
+	return m, nil
+}
+
+// Sent advances the diff baseline to the package list of data, the result
+// of the latest Collect, and saves it to StateFile.
+func (c *SoftwareCollector) Sent(data *MetricData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil || c.pendingData != data {
+		return
+	}
+	c.state = c.pending
+	c.pending, c.pendingData = nil, nil
+	if err := saveJSONState(c.statePath, c.state); err != nil {
+		log := logger.WithComponent("collector")
+		log.Warn().Str("collector", c.Name()).Str("state_file", c.statePath).Err(err).Msg("Failed to save software state")
+	}
+}
+
+// mergePackages indexes pkgs by name. A name listed more than once (multiple
+// versions side by side, or the same entry in both registry views) keeps
+// each distinct version, sorted and comma separated.
+func mergePackages(pkgs []SoftwarePackage) map[string]SoftwarePackage {
+	versions := make(map[string]map[string]bool, len(pkgs))
+	out := make(map[string]SoftwarePackage, len(pkgs))
+	for _, p := range pkgs {
+		if p.Name == "" {
+			continue
+		}
+		if versions[p.Name] == nil {
+			versions[p.Name] = make(map[string]bool)
+		}
+		versions[p.Name][p.Version] = true
+		merged := out[p.Name]
+		merged.Name = p.Name
+		if merged.Publisher == "" {
+			merged.Publisher = p.Publisher
+		}
+		out[p.Name] = merged
+	}
+	for name, vs := range versions {
+		list := make([]string, 0, len(vs))
+		for v := range vs {
+			list = append(list, v)
+		}
+		sort.Strings(list)
+		merged := out[name]
+		merged.Version = strings.Join(list, ", ")
+		out[name] = merged
+	}
+	return out
+}
+
+// diffSoftware lists packages installed, removed or whose version changed,
+// sorted by name. A downgrade is reported as "upgraded" too.
+func diffSoftware(old, cur map[string]SoftwarePackage) []SoftwareChange {
+	var changes []SoftwareChange
+	for name, p := range cur {
+		o, ok := old[name]
+		switch {
+		case !ok:
+			changes = append(changes, SoftwareChange{Name: name, Type: "installed", NewVersion: p.Version, Publisher: p.Publisher})
+		case o.Version != p.Version:
+			changes = append(changes, SoftwareChange{Name: name, Type: "upgraded", OldVersion: o.Version, NewVersion: p.Version, Publisher: p.Publisher})
+		}
+	}
+	for name, o := range old {
+		if _, ok := cur[name]; !ok {
+			changes = append(changes, SoftwareChange{Name: name, Type: "removed", OldVersion: o.Version, Publisher: o.Publisher})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func logSoftwareChanges(changes []SoftwareChange) {
+	log := logger.WithComponent("software")
+	for _, ch := range changes {
+		log.Info().Str("package", ch.Name).Str("type", ch.Type).
+			Str("old_version", ch.OldVersion).Str("new_version", ch.NewVersion).Str("publisher", ch.Publisher).
+			Msg("SOFTWARE_CHANGED: installed package changed")
+	}
+}
@@ -0,0 +1,113 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// rpmQueryTimeout bounds "rpm -qa"; a large database takes a few seconds.
+const rpmQueryTimeout = 20 * time.Second
+
+// dpkgStatusPath is the dpkg database (overridden in tests).
+var dpkgStatusPath = "/var/lib/dpkg/status"
+
+// rpmQueryFunc is the seam through which listInstalledSoftware queries the
+// rpm database. Tests swap it to feed recorded output without rpm.
+var rpmQueryFunc = func(ctx context.Context) ([]byte, error) {
+	path, err := exec.LookPath("rpm")
+	if err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, path, "-qa", "--queryformat", `%{NAME}\t%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\n`).Output()
+}
+
+// listInstalledSoftware reads the dpkg status database when it exists
+// (Debian, Ubuntu), otherwise queries rpm (RHEL, CentOS, SUSE). Returns an
+// empty source when neither is available.
+func listInstalledSoftware(ctx context.Context) (string, []SoftwarePackage, error) {
+	f, err := os.Open(dpkgStatusPath)
+	if err == nil {
+		defer f.Close()
+		pkgs, err := parseDpkgStatus(f)
+		return "dpkg", pkgs, err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, rpmQueryTimeout)
+	defer cancel()
+	output, err := rpmQueryFunc(execCtx)
+	if err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return "", nil, nil // rpm not installed
+		}
+		return "", nil, err
+	}
+	return "rpm", parseRPMQuery(output), nil
+}
+
+// parseDpkgStatus returns the packages whose Status is "install ok
+// installed" from a dpkg status file (RFC 822 style stanzas separated by
+// blank lines). Packages installed for several architectures appear once
+// per architecture and are merged by name later.
+func parseDpkgStatus(r io.Reader) ([]SoftwarePackage, error) {
+	var (
+		pkgs      []SoftwarePackage
+		cur       SoftwarePackage
+		installed bool
+	)
+	flush := func() {
+		if cur.Name != "" && installed {
+			pkgs = append(pkgs, cur)
+		}
+		cur, installed = SoftwarePackage{}, false
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024) // long Description lines
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue // continuation of a multi-line field
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			cur.Name = value
+		case "Version":
+			cur.Version = value
+		case "Status":
+			installed = value == "install ok installed"
+		}
+	}
+	flush()
+	return pkgs, sc.Err()
+}
+
+// parseRPMQuery parses "NAME<TAB>[EPOCH:]VERSION-RELEASE" lines. The
+// gpg-pubkey entries rpm lists for imported signing keys are skipped.
+func parseRPMQuery(output []byte) []SoftwarePackage {
+	var pkgs []SoftwarePackage
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		name, version, ok := strings.Cut(strings.TrimSpace(string(line)), "\t")
+		if !ok || name == "" || name == "gpg-pubkey" {
+			continue
+		}
+		pkgs = append(pkgs, SoftwarePackage{Name: name, Version: version})
+	}
+	return pkgs
+}
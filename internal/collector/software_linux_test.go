@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dpkgStatus = `Package: curl
+Status: install ok installed
+Priority: optional
+Architecture: amd64
+Version: 7.81.0-1ubuntu1.14
+Description: command line tool for transferring data with URL syntax
+ This is a command line tool and library for transferring data with URLs.
+ .
+ Version: 9.9 (continuation lines are not fields)
+
+Package: telnet
+Status: deinstall ok config-files
+Version: 0.17-44build1
+
+Package: libc6
+Status: install ok installed
+Architecture: i386
+Version: 2.35-0ubuntu3.6
+`
+
+func TestParseDpkgStatus(t *testing.T) {
+	pkgs, err := parseDpkgStatus(strings.NewReader(dpkgStatus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SoftwarePackage{
+		{Name: "curl", Version: "7.81.0-1ubuntu1.14"},
+		{Name: "libc6", Version: "2.35-0ubuntu3.6"},
+	}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("parseDpkgStatus = %+v, want %+v", pkgs, want)
+	}
+}
+
+func TestParseRPMQuery(t *testing.T) {
+	output := "bash\t5.1.8-6.el9\nkernel\t5.14.0-362.el9\ngpg-pubkey\tfd431d51-4ae0493b\nperl-Time-Local\t2:1.300-7.el9\n\n"
+	want := []SoftwarePackage{
+		{Name: "bash", Version: "5.1.8-6.el9"},
+		{Name: "kernel", Version: "5.14.0-362.el9"},
+		{Name: "perl-Time-Local", Version: "2:1.300-7.el9"},
+	}
+	if got := parseRPMQuery([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRPMQuery = %+v, want %+v", got, want)
+	}
+}
+
+func TestListInstalledSoftware_Sources(t *testing.T) {
+	origDpkg, origRPM := dpkgStatusPath, rpmQueryFunc
+	defer func() { dpkgStatusPath, rpmQueryFunc = origDpkg, origRPM }()
+
+	dir := t.TempDir()
+	dpkgStatusPath = filepath.Join(dir, "status")
+	if err := os.WriteFile(dpkgStatusPath, []byte(dpkgStatus), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if source, pkgs, err := listInstalledSoftware(context.Background()); source != "dpkg" || len(pkgs) != 2 || err != nil {
+		t.Errorf("with dpkg status = (%q, %d pkgs, %v), want dpkg", source, len(pkgs), err)
+	}
+
+	dpkgStatusPath = filepath.Join(dir, "missing")
+	rpmQueryFunc = func(context.Context) ([]byte, error) { return []byte("bash\t5.1.8-6.el9\n"), nil }
+	if source, pkgs, err := listInstalledSoftware(context.Background()); source != "rpm" || len(pkgs) != 1 || err != nil {
+		t.Errorf("with rpm = (%q, %d pkgs, %v), want rpm", source, len(pkgs), err)
+	}
+
+	rpmQueryFunc = func(context.Context) ([]byte, error) { return nil, &exec.Error{Name: "rpm", Err: exec.ErrNotFound} }
+	if source, _, err := listInstalledSoftware(context.Background()); source != "" || err != nil {
+		t.Errorf("without dpkg or rpm = (%q, %v), want no source", source, err)
+	}
+
+	rpmQueryFunc = func(context.Context) ([]byte, error) { return nil, errors.New("rpmdb open failed") }
+	if _, _, err := listInstalledSoftware(context.Background()); err == nil {
+		t.Error("rpm failure not returned")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !linux
+
+package collector
+
+import "context"
+
+// listInstalledSoftware has no package source on this platform.
+func listInstalledSoftware(context.Context) (string, []SoftwarePackage, error) {
+	return "", nil, nil
+}
@@ -0,0 +1,146 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"resourceagent/internal/config"
+)
+
+func newTestSoftware(t *testing.T, statePath string, list func() ([]SoftwarePackage, error)) *SoftwareCollector {
+	t.Helper()
+	c := NewSoftwareCollector()
+	if err := c.Configure(config.CollectorConfig{Enabled: true, StateFile: statePath}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	c.list = func(context.Context) (string, []SoftwarePackage, error) {
+		pkgs, err := list()
+		return "dpkg", pkgs, err
+	}
+	return c
+}
+
+// collectSoftware runs one cycle and, like the scheduler after a
+// successful send, reports the result as Sent.
+func collectSoftware(t *testing.T, c *SoftwareCollector) SoftwareData {
+	t.Helper()
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	c.Sent(m)
+	d, ok := m.Data.(SoftwareData)
+	if !ok {
+		t.Fatalf("Data = %T, want SoftwareData", m.Data)
+	}
+	return d
+}
+
+func TestMergePackages(t *testing.T) {
+	got := mergePackages([]SoftwarePackage{
+		{Name: "Microsoft Visual C++ 2015 Redistributable", Version: "14.0.24215", Publisher: "Microsoft Corporation"},
+		{Name: "Microsoft Visual C++ 2015 Redistributable", Version: "14.0.23026"},
+		{Name: "7-Zip", Version: "19.00"},
+		{Name: "7-Zip", Version: "19.00"}, // same entry in both registry views
+		{Name: "", Version: "1.0"},
+	})
+	want := map[string]SoftwarePackage{
+		"Microsoft Visual C++ 2015 Redistributable": {Name: "Microsoft Visual C++ 2015 Redistributable", Version: "14.0.23026, 14.0.24215", Publisher: "Microsoft Corporation"},
+		"7-Zip": {Name: "7-Zip", Version: "19.00"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergePackages = %+v, want %+v", got, want)
+	}
+}
+
+func TestSoftware_BaselineThenChanges(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "software.json")
+	pkgs := []SoftwarePackage{
+		{Name: "openssl", Version: "3.0.2-0ubuntu1.10"},
+		{Name: "curl", Version: "7.81.0-1ubuntu1.14"},
+		{Name: "telnet", Version: "0.17-44build1"},
+	}
+	c := newTestSoftware(t, statePath, func() ([]SoftwarePackage, error) { return pkgs, nil })
+
+	d := collectSoftware(t, c)
+	if d.PackageCount != 3 || len(d.Changes) != 0 {
+		t.Fatalf("baseline = %+v, want count 3 without changes", d)
+	}
+
+	pkgs = []SoftwarePackage{
+		{Name: "openssl", Version: "3.0.2-0ubuntu1.12"},
+		{Name: "curl", Version: "7.81.0-1ubuntu1.14"},
+		{Name: "vendor-tool", Version: "2.2.0"},
+	}
+	// Changes made while the agent was down are reported after a restart.
+	restarted := newTestSoftware(t, statePath, func() ([]SoftwarePackage, error) { return pkgs, nil })
+	d = collectSoftware(t, restarted)
+	want := []SoftwareChange{
+		{Name: "openssl", Type: "upgraded", OldVersion: "3.0.2-0ubuntu1.10", NewVersion: "3.0.2-0ubuntu1.12"},
+		{Name: "telnet", Type: "removed", OldVersion: "0.17-44build1"},
+		{Name: "vendor-tool", Type: "installed", NewVersion: "2.2.0"},
+	}
+	if d.PackageCount != 3 || !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("after changes = %+v, want %+v", d, want)
+	}
+
+	if d = collectSoftware(t, restarted); len(d.Changes) != 0 || d.PackageCount != 3 {
+		t.Errorf("unchanged cycle = %+v, want count only", d)
+	}
+}
+
+func TestSoftware_ListErrorKeepsState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "software.json")
+	var listErr error
+	c := newTestSoftware(t, statePath, func() ([]SoftwarePackage, error) {
+		return []SoftwarePackage{{Name: "curl", Version: "7.81"}}, listErr
+	})
+	collectSoftware(t, c)
+
+	listErr = errors.New("rpm: database locked")
+	if m, err := c.Collect(context.Background()); m != nil || err == nil {
+		t.Fatalf("Collect() = (%v, %v), want error", m, err)
+	}
+	listErr = nil
+	if d := collectSoftware(t, c); len(d.Changes) != 0 {
+		t.Errorf("changes after a failed cycle = %+v, want none", d.Changes)
+	}
+}
+
+func TestSoftware_UnsentChangesAreResent(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "software.json")
+	pkgs := []SoftwarePackage{{Name: "curl", Version: "7.81"}}
+	c := newTestSoftware(t, statePath, func() ([]SoftwarePackage, error) { return pkgs, nil })
+	collectSoftware(t, c)
+
+	// The install is collected but the send fails: no Sent.
+	pkgs = append(pkgs, SoftwarePackage{Name: "vendor-tool", Version: "2.2.0"})
+	if m, err := c.Collect(context.Background()); m == nil || err != nil {
+		t.Fatalf("Collect() = (%v, %v), want the install", m, err)
+	}
+
+	// A restart still diffs against the last accepted list.
+	restarted := newTestSoftware(t, statePath, func() ([]SoftwarePackage, error) { return pkgs, nil })
+	want := []SoftwareChange{{Name: "vendor-tool", Type: "installed", NewVersion: "2.2.0"}}
+	if d := collectSoftware(t, restarted); !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("after restart = %+v, want %+v", d.Changes, want)
+	}
+	if d := collectSoftware(t, restarted); len(d.Changes) != 0 {
+		t.Errorf("changes reported again after they were Sent: %+v", d.Changes)
+	}
+	// A stale MetricData does not advance the baseline.
+	pkgs = pkgs[:1]
+	stale, _ := restarted.Collect(context.Background())
+	fresh, _ := restarted.Collect(context.Background())
+	restarted.Sent(stale)
+	if restarted.state.Packages["vendor-tool"].Name == "" {
+		t.Error("baseline advanced by a stale Sent")
+	}
+	restarted.Sent(fresh)
+	if _, ok := restarted.state.Packages["vendor-tool"]; ok {
+		t.Error("baseline not advanced by Sent")
+	}
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+const uninstallKeyPath = `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`
+
+// listInstalledSoftware reads the machine-wide Uninstall registry keys in
+// both the 64-bit and 32-bit (WOW6432Node) views, mirroring "Programs and
+// Features": entries without a DisplayName, marked SystemComponent, or
+// belonging to a parent product (updates, patches) are skipped. Per-user
+// installs under HKCU are not visible to the service account.
+func listInstalledSoftware(ctx context.Context) (string, []SoftwarePackage, error) {
+	var pkgs []SoftwarePackage
+	for _, view := range []uint32{registry.WOW64_64KEY, registry.WOW64_32KEY} {
+		if ctx.Err() != nil {
+			return "", nil, ctx.Err()
+		}
+		found, err := readUninstallKey(view)
+		if err != nil {
+			return "", nil, err
+		}
+		pkgs = append(pkgs, found...)
+	}
+	return "registry", pkgs, nil
+}
+
+func readUninstallKey(view uint32) ([]SoftwarePackage, error) {
+	root, err := registry.OpenKey(registry.LOCAL_MACHINE, uninstallKeyPath, registry.ENUMERATE_SUB_KEYS|view)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", uninstallKeyPath, err)
+	}
+	defer root.Close()
+
+	names, err := root.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, fmt.Errorf("enumerate %s: %w", uninstallKeyPath, err)
+	}
+
+	var pkgs []SoftwarePackage
+	for _, name := range names {
+		k, err := registry.OpenKey(root, name, registry.QUERY_VALUE|view)
+		if err != nil {
+			continue // removed while enumerating, or access denied
+		}
+		displayName, _, _ := k.GetStringValue("DisplayName")
+		systemComponent, _, _ := k.GetIntegerValue("SystemComponent")
+		parent, _, _ := k.GetStringValue("ParentKeyName")
+		if displayName = strings.TrimSpace(displayName); displayName != "" && systemComponent != 1 && parent == "" {
+			version, _, _ := k.GetStringValue("DisplayVersion")
+			publisher, _, _ := k.GetStringValue("Publisher")
+			pkgs = append(pkgs, SoftwarePackage{
+				Name:      displayName,
+				Version:   strings.TrimSpace(version),
+				Publisher: strings.TrimSpace(publisher),
+			})
+		}
+		k.Close()
+	}
+	return pkgs, nil
+}
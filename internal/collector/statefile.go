@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// loadJSONState reads a collector state file written by saveJSONState into v.
+// A missing file is not an error and leaves v unchanged.
+func loadJSONState(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// saveJSONState writes v to path via a temp file and rename so a crash
+// mid-write never leaves a corrupt state file.
+func saveJSONState(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -21,6 +21,11 @@ var smartctlChecked bool
 
 // platformConfigure runs platform-specific setup during Configure.
 func (c *StorageHealthCollector) platformConfigure() {
+	lookupSmartctl(c.Name())
+}
+
+// lookupSmartctl resolves smartctl once for StorageHealth and StorageSmart.
+func lookupSmartctl(collector string) {
 	if smartctlChecked {
 		return
 	}
@@ -28,7 +33,7 @@ func (c *StorageHealthCollector) platformConfigure() {
 	path, err := exec.LookPath("smartctl")
 	if err != nil {
 		log := logger.WithComponent("collector")
-		log.Warn().Str("collector", c.Name()).Msg("smartctl not found, StorageHealth will return empty data on Linux")
+		log.Warn().Str("collector", collector).Msg("smartctl not found, StorageHealth and StorageSmart will return empty data on Linux")
 		smartctlPathCached = ""
 		return
 	}
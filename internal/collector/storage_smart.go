@@ -35,6 +35,7 @@ func (c *StorageSmartCollector) Configure(cfg config.CollectorConfig) error {
 		c.SetInterval(cfg.Interval)
 	}
 	c.includeDrives = cfg.Disks // Reuse Disks for drive filtering
+	c.platformConfigure()
 	return nil
 }
 
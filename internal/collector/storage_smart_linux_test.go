@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const smartctlNvmeJSON = `{
+  "smartctl": {"exit_status": 0},
+  "device": {"name": "/dev/nvme0n1", "type": "nvme", "protocol": "NVMe"},
+  "model_name": "Samsung SSD 970 EVO Plus 1TB",
+  "temperature": {"current": 38},
+  "power_on_time": {"hours": 8760},
+  "power_cycle_count": 1250,
+  "nvme_smart_health_information_log": {
+    "percentage_used": 2,
+    "media_errors": 0,
+    "unsafe_shutdowns": 5,
+    "data_units_written": 102400000
+  }
+}`
+
+const smartctlSataSsdJSON = `{
+  "device": {"name": "/dev/sda", "type": "sat", "protocol": "ATA"},
+  "rotation_rate": 0,
+  "logical_block_size": 512,
+  "temperature": {"current": 31},
+  "power_on_time": {"hours": 15000},
+  "power_cycle_count": 500,
+  "ata_smart_attributes": {"table": [
+    {"id": 9, "name": "Power_On_Hours", "value": 95, "raw": {"value": 15000}},
+    {"id": 174, "name": "Unexpect_Power_Loss_Ct", "value": 100, "raw": {"value": 12}},
+    {"id": 187, "name": "Reported_Uncorrect", "value": 100, "raw": {"value": 3}},
+    {"id": 198, "name": "Offline_Uncorrectable", "value": 100, "raw": {"value": 7}},
+    {"id": 202, "name": "Percent_Lifetime_Remain", "value": 91, "raw": {"value": 9}},
+    {"id": 241, "name": "Total_LBAs_Written", "value": 100, "raw": {"value": 2000000000}}
+  ]}
+}`
+
+const smartctlHddJSON = `{
+  "device": {"name": "/dev/sdb", "type": "sat", "protocol": "ATA"},
+  "rotation_rate": 7200,
+  "temperature": {"current": 35},
+  "power_on_time": {"hours": 20000},
+  "power_cycle_count": 300,
+  "ata_smart_attributes": {"table": [
+    {"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "raw": {"value": 0}},
+    {"id": 202, "name": "Data_Address_Mark_Errs", "value": 100, "raw": {"value": 0}},
+    {"id": 198, "name": "Offline_Uncorrectable", "value": 100, "raw": {"value": 2}}
+  ]}
+}`
+
+func TestParseSmartctlJSON_NVMe(t *testing.T) {
+	s := parseSmartctlJSON("nvme0n1", []byte(smartctlNvmeJSON))
+	if s == nil {
+		t.Fatal("parseSmartctlJSON returned nil")
+	}
+	if s.Name != "nvme0n1" || s.Type != "NVMe" {
+		t.Errorf("Name/Type = %q/%q, want nvme0n1/NVMe", s.Name, s.Type)
+	}
+	if *s.Temperature != 38 || *s.RemainingLife != 98 || *s.MediaErrors != 0 ||
+		*s.PowerCycles != 1250 || *s.UnsafeShutdowns != 5 || *s.PowerOnHours != 8760 {
+		t.Errorf("sensor = %+v", s)
+	}
+	if *s.TotalBytesWritten != 102400000*512000 {
+		t.Errorf("TotalBytesWritten = %d, want data units x 512000", *s.TotalBytesWritten)
+	}
+}
+
+func TestParseSmartctlJSON_ATA(t *testing.T) {
+	ssd := parseSmartctlJSON("sda", []byte(smartctlSataSsdJSON))
+	if ssd == nil || ssd.Type != "SSD" {
+		t.Fatalf("ssd = %+v, want Type SSD", ssd)
+	}
+	if *ssd.RemainingLife != 91 || *ssd.UnsafeShutdowns != 12 || *ssd.TotalBytesWritten != 2000000000*512 {
+		t.Errorf("ssd = %+v", ssd)
+	}
+	if ssd.MediaErrors == nil || *ssd.MediaErrors != 3 {
+		t.Errorf("ssd MediaErrors = %v, want 3 from attribute 187", ssd.MediaErrors)
+	}
+
+	hdd := parseSmartctlJSON("sdb", []byte(smartctlHddJSON))
+	if hdd == nil || hdd.Type != "HDD" {
+		t.Fatalf("hdd = %+v, want Type HDD", hdd)
+	}
+	// 202 is Data_Address_Mark_Errs on an HDD, not remaining life.
+	if hdd.RemainingLife != nil || hdd.TotalBytesWritten != nil || *hdd.PowerOnHours != 20000 {
+		t.Errorf("hdd = %+v", hdd)
+	}
+	if hdd.MediaErrors == nil || *hdd.MediaErrors != 2 {
+		t.Errorf("hdd MediaErrors = %v, want 2 from attribute 198 (no 187)", hdd.MediaErrors)
+	}
+
+	if s := parseSmartctlJSON("sdc", []byte("smartctl 5.43 ...\n")); s != nil {
+		t.Errorf("non-JSON output = %+v, want nil", s)
+	}
+}
+
+func TestAtaWriteUnit(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockSize int64
+		want      int64
+	}{
+		{"Total_LBAs_Written", 4096, 4096},
+		{"Total_LBAs_Written", 0, 512},
+		{"Host_Writes_32MiB", 512, 32 << 20},
+		{"Lifetime_Writes_GiB", 512, 1 << 30},
+	}
+	for _, tt := range tests {
+		if got := ataWriteUnit(tt.name, tt.blockSize); got != tt.want {
+			t.Errorf("ataWriteUnit(%q, %d) = %d, want %d", tt.name, tt.blockSize, got, tt.want)
+		}
+	}
+}
+
+func TestCachedSmartAttributes(t *testing.T) {
+	origRun := smartctlAttrRunFunc
+	calls := 0
+	smartctlAttrRunFunc = func(ctx context.Context, path, devPath string) ([]byte, error) {
+		calls++
+		if devPath != "/dev/nvme0n1" {
+			t.Errorf("devPath = %q", devPath)
+		}
+		return []byte(smartctlNvmeJSON), nil
+	}
+	defer func() {
+		smartctlAttrRunFunc = origRun
+		smartAttrCache.entries = make(map[string]smartAttrCacheEntry)
+	}()
+	smartAttrCache.entries = make(map[string]smartAttrCacheEntry)
+
+	for i := 0; i < 3; i++ {
+		if s := cachedSmartAttributes(context.Background(), "nvme0n1"); s == nil || s.Type != "NVMe" {
+			t.Fatalf("call %d: sensor = %+v", i, s)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("smartctl runs = %d, want 1 within the cache TTL", calls)
+	}
+
+	entry := smartAttrCache.entries["nvme0n1"]
+	entry.timestamp = time.Now().Add(-smartAttrCacheTTL)
+	smartAttrCache.entries["nvme0n1"] = entry
+	cachedSmartAttributes(context.Background(), "nvme0n1")
+	if calls != 2 {
+		t.Errorf("smartctl runs = %d, want 2 after the cache expired", calls)
+	}
+}
@@ -4,12 +4,275 @@ package collector
 
 import (
 	"context"
+	"encoding/json"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"resourceagent/internal/logger"
+)
+
+const (
+	// smartctlAttrTimeout bounds a single "smartctl -a" run. Reading the
+	// attribute tables can take a few seconds on a spinning-up HDD.
+	smartctlAttrTimeout = 10 * time.Second
+	// smartAttrCacheTTL is how long a device's attributes are reused before
+	// smartctl is run again. S.M.A.R.T counters move slowly and smartctl
+	// wakes idle disks, so collecting more often than this only adds I/O.
+	smartAttrCacheTTL = 300 * time.Second
+	// nvmeDataUnitBytes is the NVMe "Data Units Written" unit (1000 sectors).
+	nvmeDataUnitBytes = 512 * 1000
 )
 
-// collectStorageMetrics returns empty data on Linux/Darwin.
-// Future: Could implement smartctl parsing for S.M.A.R.T data on Linux.
+// smartctlAttrRunFunc is the seam through which querySmartctlAttributes runs
+// smartctl. Tests swap it to feed recorded JSON without the binary.
+var smartctlAttrRunFunc = func(ctx context.Context, path, devPath string) ([]byte, error) {
+	return exec.CommandContext(ctx, path, "-a", "--json", devPath).Output()
+}
+
+// platformConfigure runs platform-specific setup during Configure.
+func (c *StorageSmartCollector) platformConfigure() {
+	lookupSmartctl(c.Name())
+}
+
+type smartAttrCacheEntry struct {
+	sensor    *StorageSmartSensor // nil when the device reported no S.M.A.R.T data
+	timestamp time.Time
+}
+
+// smartAttrCache holds the last smartctl result per device name.
+var smartAttrCache = struct {
+	sync.Mutex
+	entries map[string]smartAttrCacheEntry
+}{entries: make(map[string]smartAttrCacheEntry)}
+
+// collectStorageMetrics collects S.M.A.R.T attributes on Linux by running
+// "smartctl -a --json" (smartctl 7.0+) for each physical block device found
+// by enumerateBlockDevices. Drives are named by kernel device (sda, nvme0n1),
+// as in StorageHealth. Results are cached per device for smartAttrCacheTTL.
+// On Darwin (macOS), or when smartctl is not installed, returns empty.
 func (c *StorageSmartCollector) collectStorageMetrics(ctx context.Context) ([]StorageSmartSensor, error) {
-	// S.M.A.R.T metrics via LHM are only available on Windows
-	// Return nil to indicate no data (not an error)
-	return nil, nil
+	if runtime.GOOS == "darwin" {
+		return nil, nil
+	}
+
+	if smartctlPathCached == "" {
+		return nil, nil
+	}
+
+	devices, err := enumerateBlockDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	var storages []StorageSmartSensor
+	for _, dev := range devices {
+		select {
+		case <-ctx.Done():
+			return storages, ctx.Err()
+		default:
+		}
+
+		// Skip if specific drives are configured and this one isn't in the list
+		if len(c.includeDrives) > 0 && !c.shouldInclude(dev) {
+			continue
+		}
+
+		if s := cachedSmartAttributes(ctx, dev); s != nil {
+			storages = append(storages, *s)
+		}
+	}
+
+	return storages, nil
+}
+
+// cachedSmartAttributes returns the cached sensor for dev, running smartctl
+// when the entry is missing or older than smartAttrCacheTTL.
+func cachedSmartAttributes(ctx context.Context, dev string) *StorageSmartSensor {
+	smartAttrCache.Lock()
+	entry, ok := smartAttrCache.entries[dev]
+	smartAttrCache.Unlock()
+	if ok && time.Since(entry.timestamp) < smartAttrCacheTTL {
+		return entry.sensor
+	}
+
+	sensor := querySmartctlAttributes(ctx, dev)
+	if ctx.Err() != nil {
+		// Interrupted by shutdown or the collection deadline: don't cache
+		// the miss, try again next cycle.
+		return sensor
+	}
+
+	smartAttrCache.Lock()
+	smartAttrCache.entries[dev] = smartAttrCacheEntry{sensor: sensor, timestamp: time.Now()}
+	smartAttrCache.Unlock()
+	return sensor
+}
+
+// querySmartctlAttributes runs "smartctl -a --json /dev/<dev>". As with -H,
+// the exit code is a bitmask; bits 2-7 (SMART command failed, disk failing,
+// past errors) still come with a full JSON report, while bits 0-1 (parse
+// error, device open failure) mean there is nothing to read.
+func querySmartctlAttributes(ctx context.Context, dev string) *StorageSmartSensor {
+	execCtx, cancel := context.WithTimeout(ctx, smartctlAttrTimeout)
+	defer cancel()
+
+	output, err := smartctlAttrRunFunc(execCtx, smartctlPathCached, "/dev/"+dev)
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode()&0x03 != 0 {
+			log := logger.WithComponent("collector")
+			log.Debug().Str("collector", "StorageSmart").Str("device", dev).Err(err).Msg("smartctl -a returned no data")
+			return nil
+		}
+	}
+
+	return parseSmartctlJSON(dev, output)
+}
+
+// smartctlReport is the subset of "smartctl --json" output used here.
+type smartctlReport struct {
+	Device struct {
+		Protocol string `json:"protocol"` // ATA, NVMe, SCSI
+	} `json:"device"`
+	RotationRate     *int64 `json:"rotation_rate"` // 0 for SSDs (ATA only)
+	LogicalBlockSize int64  `json:"logical_block_size"`
+	Temperature      struct {
+		Current *float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours *int64 `json:"hours"`
+	} `json:"power_on_time"`
+	PowerCycleCount *int64 `json:"power_cycle_count"`
+	NvmeLog         *struct {
+		PercentageUsed   *float64 `json:"percentage_used"`
+		MediaErrors      *int64   `json:"media_errors"`
+		UnsafeShutdowns  *int64   `json:"unsafe_shutdowns"`
+		DataUnitsWritten *int64   `json:"data_units_written"`
+	} `json:"nvme_smart_health_information_log"`
+	AtaAttributes struct {
+		Table []smartctlAtaAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+type smartctlAtaAttribute struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"` // normalized (vendor scaled, usually 100 = new)
+	Raw   struct {
+		Value int64 `json:"value"`
+	} `json:"raw"`
+}
+
+// ATA attributes whose normalized value is the remaining life in percent,
+// in order of preference: SSD_Life_Left, Media_Wearout_Indicator (Intel),
+// Percent_Lifetime_Remain (Crucial/Micron), Wear_Leveling_Count (Samsung),
+// Remaining_Lifetime_Perc. SSD only: on HDDs these IDs mean something else
+// (202 Data_Address_Mark_Errs, 169 vendor specific).
+var ataRemainingLifeIDs = []int{231, 233, 202, 177, 169}
+
+// parseSmartctlJSON maps a smartctl JSON report onto StorageSmartSensor.
+// Temperature, power-on hours and power cycles come from the top-level
+// fields smartctl normalizes across protocols. NVMe fills the rest from the
+// health information log; ATA from the attribute table:
+//
+//   - RemainingLife (SSD only): see ataRemainingLifeIDs
+//   - MediaErrors: 187 Reported_Uncorrect, else 198 Offline_Uncorrectable
+//   - UnsafeShutdowns: 174 Unexpect_Power_Loss_Ct
+//   - TotalBytesWritten: 241 Total_LBAs_Written (x logical block size, or
+//     the unit in the attribute name for *_32MiB / *_GiB variants)
+//
+// Returns nil when output is not a smartctl JSON report (smartctl < 7.0).
+func parseSmartctlJSON(dev string, output []byte) *StorageSmartSensor {
+	var r smartctlReport
+	if err := json.Unmarshal(output, &r); err != nil {
+		return nil
+	}
+
+	s := &StorageSmartSensor{
+		Name:         dev,
+		Type:         smartctlDiskType(dev, &r),
+		Temperature:  r.Temperature.Current,
+		PowerOnHours: r.PowerOnTime.Hours,
+		PowerCycles:  r.PowerCycleCount,
+	}
+
+	if nvme := r.NvmeLog; nvme != nil {
+		if nvme.PercentageUsed != nil {
+			life := 100 - *nvme.PercentageUsed
+			if life < 0 {
+				life = 0 // percentage_used may exceed 100 past rated endurance
+			}
+			s.RemainingLife = &life
+		}
+		s.MediaErrors = nvme.MediaErrors
+		s.UnsafeShutdowns = nvme.UnsafeShutdowns
+		if nvme.DataUnitsWritten != nil {
+			written := *nvme.DataUnitsWritten * nvmeDataUnitBytes
+			s.TotalBytesWritten = &written
+		}
+		return s
+	}
+
+	attrs := make(map[int]smartctlAtaAttribute, len(r.AtaAttributes.Table))
+	for _, a := range r.AtaAttributes.Table {
+		attrs[a.ID] = a
+	}
+	if s.Type == "SSD" {
+		for _, id := range ataRemainingLifeIDs {
+			if a, ok := attrs[id]; ok {
+				life := float64(a.Value)
+				s.RemainingLife = &life
+				break
+			}
+		}
+	}
+	for _, id := range []int{187, 198} {
+		if a, ok := attrs[id]; ok {
+			n := a.Raw.Value
+			s.MediaErrors = &n
+			break
+		}
+	}
+	if a, ok := attrs[174]; ok {
+		n := a.Raw.Value
+		s.UnsafeShutdowns = &n
+	}
+	if a, ok := attrs[241]; ok {
+		written := a.Raw.Value * ataWriteUnit(a.Name, r.LogicalBlockSize)
+		s.TotalBytesWritten = &written
+	}
+
+	return s
+}
+
+// ataWriteUnit returns the byte size of one raw unit of attribute 241.
+func ataWriteUnit(name string, logicalBlockSize int64) int64 {
+	switch {
+	case strings.HasSuffix(name, "_32MiB"):
+		return 32 << 20
+	case strings.HasSuffix(name, "_GiB"):
+		return 1 << 30
+	case logicalBlockSize > 0:
+		return logicalBlockSize
+	default:
+		return 512
+	}
+}
+
+// smartctlDiskType reports NVMe for the NVMe protocol, otherwise SSD/HDD by
+// ATA rotation rate, falling back to /sys/block rotational.
+func smartctlDiskType(dev string, r *smartctlReport) string {
+	if r.Device.Protocol == "NVMe" {
+		return "NVMe"
+	}
+	if r.RotationRate != nil {
+		if *r.RotationRate == 0 {
+			return "SSD"
+		}
+		return "HDD"
+	}
+	return detectDiskType(dev)
 }
@@ -6,6 +6,9 @@ import (
 	"context"
 )
 
+// platformConfigure is a no-op on Windows (S.M.A.R.T comes from LhmHelper).
+func (c *StorageSmartCollector) platformConfigure() {}
+
 // collectStorageMetrics collects S.M.A.R.T metrics using LibreHardwareMonitor helper.
 // Windows-specific implementation that uses shared LhmProvider for efficiency.
 func (c *StorageSmartCollector) collectStorageMetrics(ctx context.Context) ([]StorageSmartSensor, error) {
@@ -0,0 +1,65 @@
+// fake_plugin.go is a test helper that simulates an external plugin
+// collector. It is compiled and run by plugin tests.
+//
+// The first argument selects the protocol ("oneshot" or "daemon") and the
+// second the behaviour:
+//
+//	"normal"  - Respond with valid metrics (default)
+//	"error"   - Respond with an error JSON
+//	"fail"    - Write to stderr and exit 2 (oneshot)
+//	"garbage" - Respond with a line that is not JSON
+//	"slow"    - Never respond (for timeout testing)
+//	"crash"   - Respond once then exit (daemon)
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	protocol, mode := "oneshot", "normal"
+	if len(os.Args) > 1 {
+		protocol = os.Args[1]
+	}
+	if len(os.Args) > 2 {
+		mode = os.Args[2]
+	}
+
+	if protocol == "oneshot" {
+		fmt.Fprintln(os.Stderr, "fake plugin starting")
+		switch mode {
+		case "normal":
+			fmt.Println(`{"metrics": [{"name": "wafer_count", "value": 1520}, {"name": "queue_depth", "value": 3, "proc": "LoaderA", "pid": 42}, {"name": "", "value": 1}]}`)
+		case "error":
+			fmt.Println(`{"metrics": [], "error": "camera offline"}`)
+		case "fail":
+			fmt.Fprintln(os.Stderr, "boom")
+			os.Exit(2)
+		case "garbage":
+			fmt.Println("not json")
+		case "slow":
+			time.Sleep(30 * time.Second)
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	requests := 0
+	for scanner.Scan() {
+		requests++
+		switch mode {
+		case "normal":
+			fmt.Printf("{\"metrics\": [{\"name\": \"requests\", \"value\": %d}, {\"name\": \"pid\", \"value\": %d}]}\n", requests, os.Getpid())
+		case "crash":
+			fmt.Printf("{\"metrics\": [{\"name\": \"requests\", \"value\": %d}]}\n", requests)
+			os.Exit(1)
+		case "error":
+			fmt.Println(`{"error": "sensor read failed"}`)
+		case "garbage":
+			fmt.Println("not json")
+		}
+	}
+}
@@ -43,6 +43,7 @@ type MemoryData struct {
 // DiskData contains disk usage and I/O metrics.
 type DiskData struct {
 	Partitions []DiskPartition `json:"partitions"`
+	Devices    []DiskDevice    `json:"devices,omitempty"`
 }
 
 // DiskPartition contains metrics for a single disk partition.
@@ -66,11 +67,25 @@ type DiskPartition struct {
 	WriteTime     uint64  `json:"write_time_ms,omitempty"`
 }
 
+// DiskDevice contains I/O rates for a single physical disk, computed from
+// the counter deltas between two collection cycles.
+type DiskDevice struct {
+	Name             string  `json:"name"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+	AwaitMs          float64 `json:"await_ms"`     // Average time per completed I/O
+	UtilPercent      float64 `json:"util_percent"` // Share of time with I/O in flight
+	QueueDepth       uint64  `json:"queue_depth"`  // I/Os in flight at collection time
+}
+
 // NetworkData contains network interface metrics.
 type NetworkData struct {
 	Interfaces       []NetworkInterface `json:"interfaces"`
 	TCPInboundCount  int                `json:"tcp_inbound_count"`
 	TCPOutboundCount int                `json:"tcp_outbound_count"`
+	Events           []NetworkEvent     `json:"events,omitempty"`
 }
 
 // NetworkInterface contains metrics for a single network interface.
@@ -86,6 +101,67 @@ type NetworkInterface struct {
 	DropsOut      uint64  `json:"drops_out"`
 	BytesSentRate float64 `json:"bytes_sent_rate,omitempty"`
 	BytesRecvRate float64 `json:"bytes_recv_rate,omitempty"`
+	ErrorsInRate  float64 `json:"errors_in_rate,omitempty"`
+	ErrorsOutRate float64 `json:"errors_out_rate,omitempty"`
+	DropsInRate   float64 `json:"drops_in_rate,omitempty"`
+	DropsOutRate  float64 `json:"drops_out_rate,omitempty"`
+
+	// Link state. SpeedMbps and Duplex are zero/empty when the driver does
+	// not report them (virtual NICs, Wi-Fi on Linux, macOS).
+	OperUp      bool     `json:"oper_up"`
+	MAC         string   `json:"mac,omitempty"`
+	Addresses   []string `json:"addresses,omitempty"`
+	SpeedMbps   float64  `json:"speed_mbps,omitempty"`
+	Duplex      string   `json:"duplex,omitempty"`       // "full" or "half"
+	UtilPercent *float64 `json:"util_percent,omitempty"` // nil when SpeedMbps is unknown
+}
+
+// NetworkEvent records an interface state change detected between cycles.
+type NetworkEvent struct {
+	Interface string `json:"interface"`
+	Type      string `json:"type"` // "link_down", "link_up" or "address_changed"
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+}
+
+// ConnectionsData contains the TCP connection breakdown.
+type ConnectionsData struct {
+	States    []TCPStateCount `json:"states"`
+	Listeners []ListeningPort `json:"listeners"`
+	Ports     []PortSummary   `json:"ports,omitempty"`
+	Endpoints []EndpointCount `json:"endpoints,omitempty"`
+}
+
+// TCPStateCount is the number of TCP sockets in one state.
+type TCPStateCount struct {
+	State string `json:"state"` // Linux naming: ESTABLISHED, CLOSE_WAIT, FIN_WAIT1...
+	Count int    `json:"count"`
+}
+
+// ListeningPort is a listening socket and its owning process.
+// PID is 0 and ProcessName empty when the owner is not visible (another
+// user's process on Linux without root).
+type ListeningPort struct {
+	Protocol    string `json:"protocol"` // "tcp"
+	Address     string `json:"address"`
+	Port        uint32 `json:"port"`
+	PID         int32  `json:"pid"`
+	ProcessName string `json:"process_name,omitempty"`
+}
+
+// PortSummary is the established connection count for a WatchPorts entry,
+// reported even when it is 0.
+type PortSummary struct {
+	Port        uint32 `json:"port"`
+	Established int    `json:"established"`
+}
+
+// EndpointCount is the number of established connections between a
+// WatchPorts port and one remote host.
+type EndpointCount struct {
+	Port     uint32 `json:"port"`
+	RemoteIP string `json:"remote_ip"`
+	Count    int    `json:"count"`
 }
 
 // TemperatureData contains system temperature metrics.
@@ -145,6 +221,47 @@ type ProcessMemory struct {
 	Watched       bool    `json:"watched,omitempty"`
 }
 
+// ProcessIOData contains per-process disk I/O rates.
+type ProcessIOData struct {
+	Processes []ProcessIO `json:"processes"`
+}
+
+// ProcessIO contains I/O rates for a single process, computed from the
+// counter deltas between two collection cycles.
+type ProcessIO struct {
+	PID              int32   `json:"pid"`
+	Name             string  `json:"name"`
+	Username         string  `json:"username,omitempty"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+	CreateTime       int64   `json:"create_time,omitempty"`
+	Watched          bool    `json:"watched,omitempty"`
+}
+
+// ProcessDetailData contains health details of watched processes.
+type ProcessDetailData struct {
+	Processes []ProcessDetail `json:"processes"`
+}
+
+// ProcessDetail contains health details for a single watched process.
+// NumHandles is the kernel handle count on Windows and the open fd count on
+// Linux; it is nil when the count could not be read (access denied).
+type ProcessDetail struct {
+	PID           int32   `json:"pid"`
+	Name          string  `json:"name"`
+	Exe           string  `json:"exe,omitempty"`
+	Cmdline       string  `json:"cmdline,omitempty"` // JSON only, truncated to processDetailMaxCmdlineBytes
+	NumThreads    int32   `json:"num_threads"`
+	NumHandles    *int32  `json:"num_handles,omitempty"`
+	NumChildren   int     `json:"num_children"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	CreateTime    int64   `json:"create_time,omitempty"`
+	HandleGrowing bool    `json:"handle_growing"`
+	ThreadGrowing bool    `json:"thread_growing"`
+}
+
 // GpuData contains GPU metrics.
 type GpuData struct {
 	Gpus []GpuSensor `json:"gpus"`
@@ -205,6 +322,7 @@ type MotherboardTempSensor struct {
 // ProcessWatchData contains process watch results for required and forbidden processes.
 type ProcessWatchData struct {
 	Statuses []ProcessWatchStatus `json:"statuses"`
+	Ports    []PortWatchStatus    `json:"ports,omitempty"`
 }
 
 // ProcessWatchStatus represents the status of a single watched process.
@@ -215,6 +333,195 @@ type ProcessWatchStatus struct {
 	Type    string `json:"type"` // "required" or "forbidden"
 }
 
+// PortWatchStatus represents the status of a single listening-port rule.
+// Listening is true when a socket listens on Port and, if the rule names a
+// Process, is owned by it. PID and Owner describe the matching socket, or
+// any socket on the port when the owner did not match. A required rule whose
+// socket owner cannot be resolved reports Listening with PID 0 and no Owner.
+type PortWatchStatus struct {
+	Port      string `json:"port"`              // "tcp:5000"
+	Process   string `json:"process,omitempty"` // expected owner from the rule
+	Listening bool   `json:"listening"`
+	PID       int32  `json:"pid"`
+	Owner     string `json:"owner,omitempty"`
+	Type      string `json:"type"` // "required" or "forbidden"
+}
+
+// LogWatchData contains per-file pattern match counts for one interval.
+type LogWatchData struct {
+	Files  []LogWatchFile  `json:"files"`
+	Events []LogWatchEvent `json:"events,omitempty"`
+}
+
+// LogWatchFile contains the match counts of one watched file. Matches has
+// one entry per configured pattern, in config order, including zero counts.
+type LogWatchFile struct {
+	Path      string          `json:"path"`
+	BytesRead int64           `json:"bytes_read"`
+	Rotated   bool            `json:"rotated"` // rotated or truncated since the last cycle
+	Matches   []LogWatchMatch `json:"matches"`
+}
+
+// LogWatchMatch is the number of lines matching one pattern.
+type LogWatchMatch struct {
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
+}
+
+// LogWatchEvent is one matching line, forwarded for the first
+// MaxEventLines matches of an interval.
+type LogWatchEvent struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+	Line    string `json:"line"` // truncated to logWatchMaxLineBytes
+}
+
+// PathWatchData contains the size and freshness of watched paths.
+type PathWatchData struct {
+	Paths []PathWatchStatus `json:"paths"`
+}
+
+// PathWatchStatus aggregates the regular files matched by one configured
+// path rule. NewestAgeSeconds is nil when no file matched. When Truncated is
+// set the walk stopped early (MaxFiles entries or the collection deadline),
+// the totals are lower bounds, Stale is cleared by a directory modified
+// within MaxAge, and OverQuota is set only if the partial total is already
+// above quota.
+type PathWatchStatus struct {
+	Path             string   `json:"path"`
+	FileCount        int      `json:"file_count"`
+	TotalBytes       int64    `json:"total_bytes"`
+	NewestFile       string   `json:"newest_file,omitempty"`
+	NewestAgeSeconds *float64 `json:"newest_age_seconds,omitempty"`
+	LargestFile      string   `json:"largest_file,omitempty"`
+	LargestBytes     int64    `json:"largest_bytes"`
+	Truncated        bool     `json:"truncated"`
+	Stale            bool     `json:"stale"`      // newest file older than MaxAge, or no file at all
+	OverQuota        bool     `json:"over_quota"` // TotalBytes above QuotaMB
+}
+
+// ProbeData contains the reachability probe results, in configuration order.
+type ProbeData struct {
+	Probes []ProbeResult `json:"probes"`
+}
+
+// ProbeResult is the outcome of one TCP or HTTP probe. LatencyMs is the time
+// to connect (tcp) or to receive the response headers (http), or the time
+// until the probe failed.
+type ProbeResult struct {
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Address    string  `json:"address"`
+	Proxy      bool    `json:"proxy"` // dialed through SOCKSProxy
+	Success    bool    `json:"success"`
+	LatencyMs  float64 `json:"latency_ms"`
+	StatusCode int     `json:"status_code,omitempty"` // http only; 0 without a response
+	Error      string  `json:"error,omitempty"`
+}
+
+// PluginData contains the metrics reported by an external plugin collector.
+type PluginData struct {
+	Plugin  string         `json:"plugin"` // collector name from Monitor.json
+	Metrics []PluginMetric `json:"metrics"`
+}
+
+// PluginMetric is one value of a plugin response. Proc and PID become the
+// EARS proc and pid; Proc defaults to the plugin name.
+type PluginMetric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Proc  string  `json:"proc,omitempty"`
+	PID   int     `json:"pid,omitempty"`
+}
+
+// InventoryData is a hardware and OS inventory snapshot. It is sent only
+// when Hash differs from the last snapshot sent; Changes lists what differs
+// from that snapshot (empty for the first one).
+type InventoryData struct {
+	Hash             string            `json:"hash"` // SHA-256 of the snapshot without Hash and Changes
+	AgentVersion     string            `json:"agent_version"`
+	CPU              InventoryCPU      `json:"cpu"`
+	MemoryTotalBytes uint64            `json:"memory_total_bytes"`
+	Disks            []InventoryDisk   `json:"disks"`
+	NICs             []InventoryNIC    `json:"nics"`
+	OS               InventoryOS       `json:"os"`
+	Board            InventoryBoard    `json:"board"`
+	Changes          []InventoryChange `json:"changes,omitempty"`
+}
+
+// InventoryCPU describes the installed processors.
+type InventoryCPU struct {
+	Model        string `json:"model"`
+	Cores        int    `json:"cores"`
+	LogicalCores int    `json:"logical_cores"`
+}
+
+// InventoryDisk is one fixed physical disk. Removable media is excluded.
+type InventoryDisk struct {
+	Name      string `json:"name"` // sda, nvme0n1 (Linux) or PhysicalDrive0 (Windows)
+	Model     string `json:"model"`
+	Serial    string `json:"serial,omitempty"`
+	SizeBytes uint64 `json:"size_bytes"`
+}
+
+// InventoryNIC is one physical network adapter.
+type InventoryNIC struct {
+	Name string `json:"name"`
+	MAC  string `json:"mac"`
+}
+
+// InventoryOS describes the operating system.
+type InventoryOS struct {
+	Name    string `json:"name"`    // "Microsoft Windows 10 Enterprise LTSC", "ubuntu"
+	Version string `json:"version"` // platform version
+	Kernel  string `json:"kernel"`
+	Arch    string `json:"arch"`
+}
+
+// InventoryBoard contains BIOS and mainboard identification. Fields are
+// empty where the platform does not expose them (macOS).
+type InventoryBoard struct {
+	BIOSVendor    string `json:"bios_vendor,omitempty"`
+	BIOSVersion   string `json:"bios_version,omitempty"`
+	BoardVendor   string `json:"board_vendor,omitempty"`
+	BoardProduct  string `json:"board_product,omitempty"`
+	SystemVendor  string `json:"system_vendor,omitempty"`
+	SystemProduct string `json:"system_product,omitempty"`
+}
+
+// InventoryChange is one difference from the previously sent snapshot.
+type InventoryChange struct {
+	Component string `json:"component"` // "cpu", "memory", "disk", "nic", "os", "board", "agent"
+	Type      string `json:"type"`      // "added", "removed" or "changed"
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+}
+
+// SoftwareData contains the installed package count and the packages
+// installed, removed or upgraded since the previous cycle.
+type SoftwareData struct {
+	Source       string           `json:"source"` // "dpkg", "rpm" or "registry"
+	PackageCount int              `json:"package_count"`
+	Changes      []SoftwareChange `json:"changes,omitempty"`
+}
+
+// SoftwarePackage is one installed package. Version lists every installed
+// version, comma separated, when a name is installed more than once.
+type SoftwarePackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Publisher string `json:"publisher,omitempty"` // Windows only
+}
+
+// SoftwareChange is one package difference from the previous cycle.
+type SoftwareChange struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"` // "installed", "removed" or "upgraded"
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	Publisher  string `json:"publisher,omitempty"`
+}
+
 // StorageHealthData contains health status for storage devices.
 type StorageHealthData struct {
 	Disks []StorageHealthDisk `json:"disks"`
@@ -247,4 +554,45 @@ type SelfMetricsData struct {
 	HandleCount        uint32 `json:"handle_count"` // Windows HANDLE / Linux fd count (Phase 2.5-1.6)
 	BufferCount        int64  `json:"buffer_count"`
 	BufferDroppedTotal int64  `json:"buffer_dropped_total"`
+
+	// Spool is set only when the sender has an on-disk spool attached.
+	Spool *SpoolMetrics `json:"spool,omitempty"`
+	// Breaker is set only when the transport has a circuit breaker.
+	Breaker *BreakerMetrics `json:"breaker,omitempty"`
+	// Delivery is set only when the transport tracks per-record acks (kafka).
+	Delivery *DeliveryMetrics `json:"delivery,omitempty"`
+	// Compression is set only when KafkaRest request compression is on.
+	Compression *CompressionMetrics `json:"compression,omitempty"`
+}
+
+// SpoolMetrics contains on-disk spool observability for SelfMetricsData.
+type SpoolMetrics struct {
+	Depth         int64 `json:"depth"`
+	Bytes         int64 `json:"bytes"`
+	ReplayedTotal int64 `json:"replayed_total"`
+	ExpiredTotal  int64 `json:"expired_total"`
+}
+
+// BreakerMetrics contains KafkaRest circuit breaker observability for
+// SelfMetricsData. State is 0=closed, 1=half-open, 2=open.
+type BreakerMetrics struct {
+	State               int64 `json:"state"`
+	OpenTotal           int64 `json:"open_total"`
+	ConsecutiveFailures int64 `json:"consecutive_failures"`
+}
+
+// CompressionMetrics contains KafkaRest request compression observability
+// for SelfMetricsData. Ratio is WireBytesTotal/RawBytesTotal (1 means no
+// saving; 0 before the first delivered request).
+type CompressionMetrics struct {
+	RawBytesTotal  int64   `json:"raw_bytes_total"`
+	WireBytesTotal int64   `json:"wire_bytes_total"`
+	Ratio          float64 `json:"ratio"`
+}
+
+// DeliveryMetrics contains kafka producer ack tracking for SelfMetricsData.
+type DeliveryMetrics struct {
+	AckedTotal  int64 `json:"acked_total"`
+	FailedTotal int64 `json:"failed_total"`
+	InFlight    int64 `json:"in_flight"`
 }
@@ -6,10 +6,24 @@ import (
 	"context"
 )
 
-// collectVoltageMetrics returns empty data on Linux/Darwin.
-// Future: Could implement hwmon/sysfs parsing for voltage sensors on Linux.
+// collectVoltageMetrics collects voltages from sysfs hwmon (in*_input, millivolts) on Linux.
+// Sensors are named by in*_label when the driver provides one (e.g. "Vcore"),
+// otherwise "<device> Voltage #N". On Darwin (macOS), returns empty.
 func (c *VoltageCollector) collectVoltageMetrics(ctx context.Context) ([]VoltageSensor, error) {
-	// Voltage metrics via LHM are only available on Windows
-	// Return nil to indicate no data (not an error)
-	return nil, nil
+	readings, err := readHwmonInputs(ctx, "in", "Voltage")
+
+	var sensors []VoltageSensor
+	for _, r := range readings {
+		// Skip if specific sensors are configured and this one isn't in the list
+		if len(c.includeSensors) > 0 && !c.shouldInclude(r.label) {
+			continue
+		}
+
+		sensors = append(sensors, VoltageSensor{
+			Name:    r.label,
+			Voltage: r.value / 1000,
+		})
+	}
+
+	return sensors, err
 }
@@ -3,25 +3,181 @@ package config
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Config is the root configuration structure.
 type Config struct {
-	SenderType                  string         `json:"SenderType"` // "kafka", "kafkarest", or "file"
-	Kafka                       KafkaConfig    `json:"Kafka"`
-	Batch                       BatchConfig    `json:"Batch"`
-	File                        FileConfig     `json:"File"`
-	VirtualAddressList          string         `json:"VirtualAddressList"`
-	Redis                       RedisConfig    `json:"Redis"`
-	PrivateIPAddressPattern     string         `json:"PrivateIPAddressPattern"`
-	SOCKSProxy                  SOCKSConfig    `json:"SocksProxy"`
-	ServiceDiscoveryPort        int            `json:"ServiceDiscoveryPort"`
-	ResourceMonitorTopic        string         `json:"ResourceMonitorTopic"`
-	TimeDiffSyncInterval        int            `json:"TimeDiffSyncInterval"` // seconds, default 3600
-	UpdateServerAddressInterval time.Duration  `json:"-"`                    // parsed from duration string, default 5m
-	KafkaRestAddress            string         `json:"-"`                    // runtime only, from ServiceDiscovery
-	EqpInfo                     *EqpInfoConfig `json:"-"`                    // runtime only, not serialized
+	SenderType                  string              `json:"SenderType"` // "kafka", "kafkarest", "otlp", "influx", "mqtt", "file", or "composite"
+	Kafka                       KafkaConfig         `json:"Kafka"`
+	KafkaRest                   KafkaRestConfig     `json:"KafkaRest"`
+	Batch                       BatchConfig         `json:"Batch"`
+	Spool                       SpoolConfig         `json:"Spool"`
+	Prometheus                  PrometheusConfig    `json:"Prometheus"`
+	OTLP                        OTLPConfig          `json:"OTLP"`
+	Influx                      InfluxConfig        `json:"Influx"`
+	MQTT                        MQTTConfig          `json:"MQTT"`
+	File                        FileConfig          `json:"File"`
+	Destinations                []DestinationConfig `json:"Destinations"` // SenderType "composite" only
+	VirtualAddressList          string              `json:"VirtualAddressList"`
+	Redis                       RedisConfig         `json:"Redis"`
+	PrivateIPAddressPattern     string              `json:"PrivateIPAddressPattern"`
+	SOCKSProxy                  SOCKSConfig         `json:"SocksProxy"`
+	ServiceDiscoveryPort        int                 `json:"ServiceDiscoveryPort"`
+	ResourceMonitorTopic        string              `json:"ResourceMonitorTopic"`
+	TimeDiffSyncInterval        int                 `json:"TimeDiffSyncInterval"` // seconds, default 3600
+	UpdateServerAddressInterval time.Duration       `json:"-"`                    // parsed from duration string, default 5m
+	KafkaRestAddress            string              `json:"-"`                    // runtime only, from ServiceDiscovery
+	EqpInfo                     *EqpInfoConfig      `json:"-"`                    // runtime only, not serialized
+}
+
+// KafkaRestConfig contains settings specific to the KafkaRest HTTP
+// transport (SenderType "kafkarest"). Buffering and spool reuse Batch and
+// Spool; the proxy address comes from ServiceDiscovery.
+type KafkaRestConfig struct {
+	// APIVersion selects the REST proxy API: "v2" (default,
+	// POST /topics/{topic}) or "v3" (POST
+	// /v3/clusters/{ClusterID}/topics/{topic}/records, streamed records
+	// with per-record results).
+	APIVersion string `json:"APIVersion"`
+	// ClusterID is the Kafka cluster ID in v3 URLs (required for v3).
+	ClusterID string `json:"ClusterID"`
+	// EmbeddedFormat is the record encoding: "json" (default), "binary"
+	// (base64 of the JSON value), or "avro" (registered schema, see
+	// ValueSchemaID).
+	EmbeddedFormat string `json:"EmbeddedFormat"`
+	// ValueSchemaID is the Schema Registry ID of the value schema
+	// (required for "avro").
+	ValueSchemaID int `json:"ValueSchemaID"`
+	// KeySchemaID is the Schema Registry ID of a string key schema for
+	// "avro". 0 sends records without a key.
+	KeySchemaID int `json:"KeySchemaID"`
+	// Compression is the request Content-Encoding: "none" (default),
+	// "gzip", or "zstd" (only if the proxy supports it). A proxy answering
+	// HTTP 415 makes the transport fall back to uncompressed requests.
+	Compression string `json:"Compression"`
+	// CompressionMinBytes is the smallest request body that is compressed;
+	// smaller bodies are sent as-is.
+	CompressionMinBytes int `json:"CompressionMinBytes"`
+}
+
+// PrometheusConfig controls the local Prometheus /metrics exporter, which
+// runs alongside the configured sender.
+type PrometheusConfig struct {
+	Enabled       bool   `json:"Enabled"`
+	ListenAddress string `json:"ListenAddress"` // host:port; use ":9464" to allow remote scrapes
+}
+
+// OTLPConfig contains settings for the OTLP/HTTP metrics sender
+// (SenderType "otlp"). Buffering, retry and spool reuse Batch and Spool.
+type OTLPConfig struct {
+	// Endpoint is the collector's OTLP/HTTP base URL or full metrics URL
+	// (e.g. "http://otel-collector:4318"); "/v1/metrics" is appended when
+	// the URL has no path.
+	Endpoint string `json:"Endpoint"`
+	// Headers are added to every export request (e.g. an API key).
+	Headers map[string]string `json:"Headers"`
+}
+
+// InfluxConfig contains settings for the InfluxDB v2 line-protocol sender
+// (SenderType "influx"). Buffering, retry and spool reuse Batch and Spool.
+type InfluxConfig struct {
+	URL    string `json:"URL"`    // server base URL, e.g. "http://influxdb:8086"
+	Org    string `json:"Org"`    // organization name or ID
+	Bucket string `json:"Bucket"` // destination bucket (required)
+	Token  string `json:"Token"`  // API token, sent as "Authorization: Token ..."
+}
+
+// MQTTConfig contains settings for the MQTT sender (SenderType "mqtt").
+// The reconnect buffer is capped by Batch.MaxBufferedRecords.
+type MQTTConfig struct {
+	Broker   string `json:"Broker"`   // "tcp://host:1883", or "ssl://host:8883" with TLS
+	ClientID string `json:"ClientID"` // default "resourceagent-<eqpid>"
+	Username string `json:"Username"`
+	Password string `json:"Password"`
+	// TopicTemplate is expanded per MetricData with {process}, {model},
+	// {eqpid}, {line} and {type} (collector name).
+	TopicTemplate string `json:"TopicTemplate"`
+	// StatusTopic receives the retained "online"/"offline" agent status and
+	// the "offline" last will. Same placeholders ({type} is "status").
+	StatusTopic string `json:"StatusTopic"`
+	QoS         int    `json:"QoS"`    // 0 or 1
+	Format      string `json:"Format"` // payload: "grok" (default) or "json" (ParsedDataList)
+	EnableTLS   bool   `json:"EnableTLS"`
+	TLSCertFile string `json:"TLSCertFile"`
+	TLSKeyFile  string `json:"TLSKeyFile"`
+	TLSCAFile   string `json:"TLSCAFile"`
+	// KeepAlive is the MQTT keep-alive (PINGREQ) interval.
+	KeepAlive time.Duration `json:"KeepAlive"`
+	// MaxReconnectInterval caps the reconnect backoff.
+	MaxReconnectInterval time.Duration `json:"MaxReconnectInterval"`
+}
+
+// DestinationConfig describes one output of the composite sender. Each
+// destination reuses the top-level section of its Type (Kafka/Batch/Spool
+// for kafka and kafkarest, OTLP or Influx plus Batch/Spool for otlp and
+// influx, MQTT for mqtt, File for file).
+type DestinationConfig struct {
+	Name string `json:"Name"` // log/stat label; defaults to Type
+	Type string `json:"Type"` // "kafkarest", "kafka", "otlp", "influx", "mqtt", or "file"
+	// Include limits the destination to these MetricData.Type values
+	// (collector names, case-insensitive). Empty means all types.
+	Include []string `json:"Include"`
+	// Exclude drops these MetricData.Type values; applied after Include.
+	Exclude []string `json:"Exclude"`
+	// QueueSize bounds the per-destination queue of pending sends. A full
+	// queue drops new data for this destination only. 0 uses the default.
+	QueueSize int `json:"QueueSize"`
+}
+
+// NetworkSenderType returns the network sender in use: "kafkarest",
+// "kafka", "otlp", "influx", "mqtt", or "" when output goes to files only. Any network sender
+// needs the Redis/ServiceDiscovery bootstrap for EqpInfo. For SenderType
+// "composite" the Kafka-family destination wins (validation allows only
+// one), then the first other network destination.
+func (c *Config) NetworkSenderType() string {
+	senderType := strings.ToLower(c.SenderType)
+	switch senderType {
+	case "":
+		return "kafka" // NewSender default for backward compatibility
+	case "file":
+		return ""
+	case "composite":
+		other := ""
+		for _, d := range c.Destinations {
+			switch t := strings.ToLower(d.Type); t {
+			case "kafka", "kafkarest":
+				return t
+			case "file":
+			default:
+				if other == "" {
+					other = t
+				}
+			}
+		}
+		return other
+	default:
+		return senderType
+	}
+}
+
+// UsesSenderType reports whether senderType is the configured sender or one
+// of the composite destinations.
+func (c *Config) UsesSenderType(senderType string) bool {
+	if strings.EqualFold(c.SenderType, senderType) {
+		return true
+	}
+	if !strings.EqualFold(c.SenderType, "composite") {
+		return false
+	}
+	for _, d := range c.Destinations {
+		if strings.EqualFold(d.Type, senderType) {
+			return true
+		}
+	}
+	return false
 }
 
 // FileConfig contains settings for the file sender.
@@ -31,7 +187,7 @@ type FileConfig struct {
 	MaxBackups int    `json:"MaxBackups"`
 	Console    bool   `json:"Console"`
 	Pretty     bool   `json:"Pretty"`
-	Format     string `json:"Format"` // Output format: "json" or "grok" (default: "grok", "legacy" also accepted)
+	Format     string `json:"Format"` // Output format: "json", "grok" or "influx" (default: "grok", "legacy" also accepted)
 }
 
 // KafkaConfig contains Kafka connection settings.
@@ -48,6 +204,10 @@ type KafkaConfig struct {
 	SASLMechanism string        `json:"SASLMechanism"`
 	SASLUser      string        `json:"SASLUser"`
 	SASLPassword  string        `json:"SASLPassword"`
+	// SyncDelivery makes SaramaTransport.Deliver wait until every record is
+	// acked by the broker (bounded by the Send context) instead of returning
+	// once records are enqueued.
+	SyncDelivery bool `json:"SyncDelivery"`
 }
 
 // BatchConfig contains batch/flush settings shared across all sender types.
@@ -63,6 +223,30 @@ type BatchConfig struct {
 	// 0 disables the cap (test/back-compat); production callers should use
 	// the validated default supplied by validate.go.
 	MaxBufferedRecords int `json:"MaxBufferedRecords"`
+	// MaxRetryBackoff caps the exponential backoff between failed flush
+	// attempts of BufferedHTTPTransport (RetryBackoff doubles per failure).
+	MaxRetryBackoff time.Duration `json:"MaxRetryBackoff"`
+	// BreakerThreshold is the number of consecutive failed flushes after
+	// which the KafkaRest circuit breaker opens. 0 disables the open state
+	// (backoff only; test/back-compat, like MaxBufferedRecords).
+	BreakerThreshold int `json:"BreakerThreshold"`
+	// BreakerOpenTimeout is how long the breaker stays open before a single
+	// half-open probe is allowed (a random 0-20% is added per agent).
+	BreakerOpenTimeout time.Duration `json:"BreakerOpenTimeout"`
+}
+
+// SpoolConfig contains settings for the on-disk spool used by
+// BufferedHTTPTransport. When enabled, records evicted by MaxBufferedRecords
+// and batches that exhausted MaxRetries are appended to size-capped segment
+// files under Dir instead of being dropped, and replayed in order once
+// KafkaRest is reachable again.
+type SpoolConfig struct {
+	Enabled             bool          `json:"Enabled"`
+	Dir                 string        `json:"Dir"`
+	MaxSizeMB           int           `json:"MaxSizeMB"`           // total bytes on disk; oldest segments are evicted beyond this
+	SegmentSizeMB       int           `json:"SegmentSizeMB"`       // a new segment file is started once the active one reaches this size
+	MaxAge              time.Duration `json:"MaxAge"`              // segments older than this are discarded (0 = keep until evicted by size)
+	ReplayRecordsPerSec int           `json:"ReplayRecordsPerSec"` // replay rate limit once KafkaRest is reachable
 }
 
 // CollectorConfig contains settings for individual collectors.
@@ -76,6 +260,85 @@ type CollectorConfig struct {
 	WatchProcesses     []string      `json:"WatchProcesses,omitempty"`
 	RequiredProcesses  []string      `json:"RequiredProcesses,omitempty"`
 	ForbiddenProcesses []string      `json:"ForbiddenProcesses,omitempty"`
+	GrowthWindow       time.Duration `json:"GrowthWindow,omitempty"`
+	WatchPorts         []int         `json:"WatchPorts,omitempty"`
+	RequiredPorts      []PortRule    `json:"RequiredPorts,omitempty"`
+	ForbiddenPorts     []PortRule    `json:"ForbiddenPorts,omitempty"`
+	Files              []string      `json:"Files,omitempty"`
+	Patterns           []LogPattern  `json:"Patterns,omitempty"`
+	MaxEventLines      int           `json:"MaxEventLines,omitempty"`
+	StateFile          string        `json:"StateFile,omitempty"`
+	Paths              []PathRule    `json:"Paths,omitempty"`
+	MaxFiles           int           `json:"MaxFiles,omitempty"`
+	Probes             []ProbeTarget `json:"Probes,omitempty"`
+	Plugin             *PluginSpec   `json:"Plugin,omitempty"`
+}
+
+// LogPattern is a named regular expression of the LogWatch collector.
+// Name becomes the EARS metric; Regex uses Go RE2 syntax.
+type LogPattern struct {
+	Name  string `json:"Name"`
+	Regex string `json:"Regex"`
+}
+
+// PathRule is a watched directory or glob of the PathWatch collector.
+// MaxAge and QuotaMB are alert thresholds; 0 disables the check.
+type PathRule struct {
+	Path      string        `json:"Path"`
+	Recursive bool          `json:"Recursive,omitempty"`
+	MaxAge    time.Duration `json:"MaxAge,omitempty"`  // newest file older than this raises an alert
+	QuotaMB   int64         `json:"QuotaMB,omitempty"` // total size above this raises an alert
+}
+
+// ProbeTarget is a reachability check of the Probe collector.
+// Type "tcp" connects to Address ("host:port") and "http" sends a GET to
+// Address (an http:// or https:// URL). Types "redis", "servicediscovery"
+// and "kafkarest" connect to the agent's own upstream endpoint resolved at
+// startup; they leave Address empty and always take the agent's route.
+type ProbeTarget struct {
+	Name    string        `json:"Name,omitempty"` // EARS proc name; defaults to Address
+	Type    string        `json:"Type"`
+	Address string        `json:"Address,omitempty"`
+	Timeout time.Duration `json:"Timeout,omitempty"` // 0 = 5s
+	Proxy   bool          `json:"Proxy,omitempty"`   // route through SOCKSProxy when one is configured
+}
+
+// PluginSpec declares an external plugin collector: any Collectors entry
+// with a Plugin block is registered as a collector under its key. Mode
+// "oneshot" runs Command once per interval and reads one JSON response from
+// stdout; "daemon" keeps Command running and writes "collect\n" to its stdin
+// each interval, reading one JSON line back.
+type PluginSpec struct {
+	Command string        `json:"Command"`
+	Args    []string      `json:"Args,omitempty"`
+	Mode    string        `json:"Mode,omitempty"`    // "oneshot" (default) or "daemon"
+	Timeout time.Duration `json:"Timeout,omitempty"` // per request; 0 = 10s
+}
+
+// PortRule is a listening-port rule of the ProcessWatch collector.
+// Port is "tcp:5000" or "udp:161". When Process is set, only a socket owned
+// by that process satisfies a required rule or triggers a forbidden one.
+type PortRule struct {
+	Port    string `json:"Port"`
+	Process string `json:"Process,omitempty"`
+}
+
+// ParsePortSpec splits a "proto:port" spec such as "tcp:5000" into the
+// lower-cased protocol ("tcp" or "udp") and the port number.
+func ParsePortSpec(spec string) (string, int, error) {
+	proto, portStr, ok := strings.Cut(spec, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("port spec %q: want proto:port", spec)
+	}
+	proto = strings.ToLower(proto)
+	if proto != "tcp" && proto != "udp" {
+		return "", 0, fmt.Errorf("port spec %q: protocol must be tcp or udp", spec)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("port spec %q: port must be between 1 and 65535", spec)
+	}
+	return proto, port, nil
 }
 
 // DefaultRedisPassword is used when Password is empty in config.
@@ -128,6 +391,12 @@ func DefaultConfig() *Config {
 			RequiredAcks: 1,
 			Timeout:      10 * time.Second,
 		},
+		KafkaRest: KafkaRestConfig{
+			APIVersion:          "v2",
+			EmbeddedFormat:      "json",
+			Compression:         "none",
+			CompressionMinBytes: 1024,
+		},
 		Batch: BatchConfig{
 			FlushFrequency:     30 * time.Second,
 			FlushMessages:      100,
@@ -135,6 +404,29 @@ func DefaultConfig() *Config {
 			MaxRetries:         2,
 			RetryBackoff:       500 * time.Millisecond,
 			MaxBufferedRecords: 10000,
+			MaxRetryBackoff:    5 * time.Minute,
+			BreakerThreshold:   5,
+			BreakerOpenTimeout: 2 * time.Minute,
+		},
+		Spool: SpoolConfig{
+			Enabled:             false,
+			Dir:                 "log/ResourceAgent/spool",
+			MaxSizeMB:           100,
+			SegmentSizeMB:       4,
+			MaxAge:              72 * time.Hour,
+			ReplayRecordsPerSec: 200,
+		},
+		MQTT: MQTTConfig{
+			TopicTemplate:        "ears/{process}/{model}/{eqpid}/{type}",
+			StatusTopic:          "ears/{process}/{model}/{eqpid}/status",
+			QoS:                  0,
+			Format:               "grok",
+			KeepAlive:            30 * time.Second,
+			MaxReconnectInterval: time.Minute,
+		},
+		Prometheus: PrometheusConfig{
+			Enabled:       false,
+			ListenAddress: "127.0.0.1:9464",
 		},
 		Redis: RedisConfig{
 			Port: 6379,
@@ -157,6 +449,11 @@ func (c *Config) Merge(other *Config) {
 		c.SenderType = other.SenderType
 	}
 
+	// Destinations replace the default list as a whole
+	if len(other.Destinations) > 0 {
+		c.Destinations = other.Destinations
+	}
+
 	// Merge File config
 	if other.File.FilePath != "" {
 		c.File.FilePath = other.File.FilePath
@@ -206,6 +503,7 @@ func (c *Config) Merge(other *Config) {
 	if other.Kafka.SASLPassword != "" {
 		c.Kafka.SASLPassword = other.Kafka.SASLPassword
 	}
+	c.Kafka.SyncDelivery = other.Kafka.SyncDelivery
 
 	// Merge Batch config
 	if other.Batch.FlushFrequency != 0 {
@@ -226,6 +524,126 @@ func (c *Config) Merge(other *Config) {
 	if other.Batch.MaxBufferedRecords != 0 {
 		c.Batch.MaxBufferedRecords = other.Batch.MaxBufferedRecords
 	}
+	if other.Batch.MaxRetryBackoff != 0 {
+		c.Batch.MaxRetryBackoff = other.Batch.MaxRetryBackoff
+	}
+	if other.Batch.BreakerThreshold != 0 {
+		c.Batch.BreakerThreshold = other.Batch.BreakerThreshold
+	}
+	if other.Batch.BreakerOpenTimeout != 0 {
+		c.Batch.BreakerOpenTimeout = other.Batch.BreakerOpenTimeout
+	}
+
+	// Merge Spool config
+	c.Spool.Enabled = other.Spool.Enabled
+	if other.Spool.Dir != "" {
+		c.Spool.Dir = other.Spool.Dir
+	}
+	if other.Spool.MaxSizeMB != 0 {
+		c.Spool.MaxSizeMB = other.Spool.MaxSizeMB
+	}
+	if other.Spool.SegmentSizeMB != 0 {
+		c.Spool.SegmentSizeMB = other.Spool.SegmentSizeMB
+	}
+	if other.Spool.MaxAge != 0 {
+		c.Spool.MaxAge = other.Spool.MaxAge
+	}
+	if other.Spool.ReplayRecordsPerSec != 0 {
+		c.Spool.ReplayRecordsPerSec = other.Spool.ReplayRecordsPerSec
+	}
+
+	// Merge KafkaRest config
+	if other.KafkaRest.APIVersion != "" {
+		c.KafkaRest.APIVersion = other.KafkaRest.APIVersion
+	}
+	if other.KafkaRest.ClusterID != "" {
+		c.KafkaRest.ClusterID = other.KafkaRest.ClusterID
+	}
+	if other.KafkaRest.EmbeddedFormat != "" {
+		c.KafkaRest.EmbeddedFormat = other.KafkaRest.EmbeddedFormat
+	}
+	if other.KafkaRest.ValueSchemaID != 0 {
+		c.KafkaRest.ValueSchemaID = other.KafkaRest.ValueSchemaID
+	}
+	if other.KafkaRest.KeySchemaID != 0 {
+		c.KafkaRest.KeySchemaID = other.KafkaRest.KeySchemaID
+	}
+	if other.KafkaRest.Compression != "" {
+		c.KafkaRest.Compression = other.KafkaRest.Compression
+	}
+	if other.KafkaRest.CompressionMinBytes != 0 {
+		c.KafkaRest.CompressionMinBytes = other.KafkaRest.CompressionMinBytes
+	}
+
+	// Merge Prometheus config
+	c.Prometheus.Enabled = other.Prometheus.Enabled
+	if other.Prometheus.ListenAddress != "" {
+		c.Prometheus.ListenAddress = other.Prometheus.ListenAddress
+	}
+
+	// Merge OTLP config
+	if other.OTLP.Endpoint != "" {
+		c.OTLP.Endpoint = other.OTLP.Endpoint
+	}
+	if len(other.OTLP.Headers) > 0 {
+		c.OTLP.Headers = other.OTLP.Headers
+	}
+
+	// Merge Influx config
+	if other.Influx.URL != "" {
+		c.Influx.URL = other.Influx.URL
+	}
+	if other.Influx.Org != "" {
+		c.Influx.Org = other.Influx.Org
+	}
+	if other.Influx.Bucket != "" {
+		c.Influx.Bucket = other.Influx.Bucket
+	}
+	if other.Influx.Token != "" {
+		c.Influx.Token = other.Influx.Token
+	}
+
+	// Merge MQTT config
+	if other.MQTT.Broker != "" {
+		c.MQTT.Broker = other.MQTT.Broker
+	}
+	if other.MQTT.ClientID != "" {
+		c.MQTT.ClientID = other.MQTT.ClientID
+	}
+	if other.MQTT.Username != "" {
+		c.MQTT.Username = other.MQTT.Username
+	}
+	if other.MQTT.Password != "" {
+		c.MQTT.Password = other.MQTT.Password
+	}
+	if other.MQTT.TopicTemplate != "" {
+		c.MQTT.TopicTemplate = other.MQTT.TopicTemplate
+	}
+	if other.MQTT.StatusTopic != "" {
+		c.MQTT.StatusTopic = other.MQTT.StatusTopic
+	}
+	if other.MQTT.QoS != 0 {
+		c.MQTT.QoS = other.MQTT.QoS
+	}
+	if other.MQTT.Format != "" {
+		c.MQTT.Format = other.MQTT.Format
+	}
+	c.MQTT.EnableTLS = other.MQTT.EnableTLS
+	if other.MQTT.TLSCertFile != "" {
+		c.MQTT.TLSCertFile = other.MQTT.TLSCertFile
+	}
+	if other.MQTT.TLSKeyFile != "" {
+		c.MQTT.TLSKeyFile = other.MQTT.TLSKeyFile
+	}
+	if other.MQTT.TLSCAFile != "" {
+		c.MQTT.TLSCAFile = other.MQTT.TLSCAFile
+	}
+	if other.MQTT.KeepAlive != 0 {
+		c.MQTT.KeepAlive = other.MQTT.KeepAlive
+	}
+	if other.MQTT.MaxReconnectInterval != 0 {
+		c.MQTT.MaxReconnectInterval = other.MQTT.MaxReconnectInterval
+	}
 
 	// Merge VirtualAddressList
 	if other.VirtualAddressList != "" {
@@ -323,6 +741,42 @@ func (mc *MonitorConfig) Merge(other *MonitorConfig) {
 			if len(collectorCfg.ForbiddenProcesses) > 0 {
 				existing.ForbiddenProcesses = collectorCfg.ForbiddenProcesses
 			}
+			if collectorCfg.GrowthWindow != 0 {
+				existing.GrowthWindow = collectorCfg.GrowthWindow
+			}
+			if len(collectorCfg.WatchPorts) > 0 {
+				existing.WatchPorts = collectorCfg.WatchPorts
+			}
+			if len(collectorCfg.RequiredPorts) > 0 {
+				existing.RequiredPorts = collectorCfg.RequiredPorts
+			}
+			if len(collectorCfg.ForbiddenPorts) > 0 {
+				existing.ForbiddenPorts = collectorCfg.ForbiddenPorts
+			}
+			if len(collectorCfg.Files) > 0 {
+				existing.Files = collectorCfg.Files
+			}
+			if len(collectorCfg.Patterns) > 0 {
+				existing.Patterns = collectorCfg.Patterns
+			}
+			if collectorCfg.MaxEventLines != 0 {
+				existing.MaxEventLines = collectorCfg.MaxEventLines
+			}
+			if collectorCfg.StateFile != "" {
+				existing.StateFile = collectorCfg.StateFile
+			}
+			if len(collectorCfg.Paths) > 0 {
+				existing.Paths = collectorCfg.Paths
+			}
+			if collectorCfg.MaxFiles != 0 {
+				existing.MaxFiles = collectorCfg.MaxFiles
+			}
+			if len(collectorCfg.Probes) > 0 {
+				existing.Probes = collectorCfg.Probes
+			}
+			if collectorCfg.Plugin != nil {
+				existing.Plugin = collectorCfg.Plugin
+			}
 			mc.Collectors[name] = existing
 		} else {
 			mc.Collectors[name] = collectorCfg
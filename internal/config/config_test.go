@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -569,6 +570,135 @@ func TestParseMonitor_EmptyJSON(t *testing.T) {
 	}
 }
 
+func TestParseMonitor_GrowthWindow(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"ProcessDetail": {"Enabled": true, "Interval": "60s", "GrowthWindow": "2h"}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	if got := mc.Collectors["ProcessDetail"].GrowthWindow; got != 2*time.Hour {
+		t.Errorf("GrowthWindow = %v, want 2h", got)
+	}
+
+	if _, err := ParseMonitor([]byte(`{"Collectors": {"ProcessDetail": {"GrowthWindow": "soon"}}}`)); err == nil {
+		t.Error("expected error for invalid GrowthWindow")
+	}
+}
+
+func TestParseMonitor_PortRules(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"ProcessWatch": {"Enabled": true, "Interval": "60s",
+		"RequiredPorts": [{"Port": "tcp:5000", "Process": "EqpHost.exe"}],
+		"ForbiddenPorts": [{"Port": "tcp:3389"}]}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	pw := mc.Collectors["ProcessWatch"]
+	if len(pw.RequiredPorts) != 1 || pw.RequiredPorts[0] != (PortRule{Port: "tcp:5000", Process: "EqpHost.exe"}) {
+		t.Errorf("RequiredPorts = %+v", pw.RequiredPorts)
+	}
+	if len(pw.ForbiddenPorts) != 1 || pw.ForbiddenPorts[0].Port != "tcp:3389" || pw.ForbiddenPorts[0].Process != "" {
+		t.Errorf("ForbiddenPorts = %+v", pw.ForbiddenPorts)
+	}
+}
+
+func TestParseMonitor_LogWatch(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"LogWatch": {"Enabled": true, "Interval": "60s",
+		"Files": ["D:/EQP/Log/*.log"],
+		"Patterns": [{"Name": "alarm", "Regex": "ALARM\\s+\\d+"}],
+		"MaxEventLines": 5, "StateFile": "log/lw.json"}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	lw := mc.Collectors["LogWatch"]
+	if len(lw.Files) != 1 || lw.Files[0] != "D:/EQP/Log/*.log" {
+		t.Errorf("Files = %v", lw.Files)
+	}
+	if len(lw.Patterns) != 1 || lw.Patterns[0] != (LogPattern{Name: "alarm", Regex: `ALARM\s+\d+`}) {
+		t.Errorf("Patterns = %+v", lw.Patterns)
+	}
+	if lw.MaxEventLines != 5 || lw.StateFile != "log/lw.json" {
+		t.Errorf("MaxEventLines = %d, StateFile = %q", lw.MaxEventLines, lw.StateFile)
+	}
+}
+
+func TestParseMonitor_PathWatch(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"PathWatch": {"Enabled": true, "Interval": "60s",
+		"Paths": [
+			{"Path": "D:/EQP/Result", "Recursive": true, "MaxAge": "30m", "QuotaMB": 2048},
+			{"Path": "D:/EQP/Image/*.bmp"}
+		],
+		"MaxFiles": 50000}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	pw := mc.Collectors["PathWatch"]
+	want := []PathRule{
+		{Path: "D:/EQP/Result", Recursive: true, MaxAge: 30 * time.Minute, QuotaMB: 2048},
+		{Path: "D:/EQP/Image/*.bmp"},
+	}
+	if !reflect.DeepEqual(pw.Paths, want) || pw.MaxFiles != 50000 {
+		t.Errorf("Paths = %+v, MaxFiles = %d", pw.Paths, pw.MaxFiles)
+	}
+
+	if _, err := ParseMonitor([]byte(`{"Collectors": {"PathWatch": {"Paths": [{"Path": "x", "MaxAge": "30"}]}}}`)); err == nil {
+		t.Error("invalid MaxAge accepted")
+	}
+}
+
+func TestParseMonitor_Probe(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"Probe": {"Enabled": true, "Interval": "60s",
+		"Probes": [
+			{"Name": "PLC", "Type": "tcp", "Address": "192.168.0.10:502", "Timeout": "2s"},
+			{"Name": "MES", "Type": "http", "Address": "http://mes.local/health", "Proxy": true},
+			{"Type": "kafkarest"}
+		]}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	want := []ProbeTarget{
+		{Name: "PLC", Type: "tcp", Address: "192.168.0.10:502", Timeout: 2 * time.Second},
+		{Name: "MES", Type: "http", Address: "http://mes.local/health", Proxy: true},
+		{Type: "kafkarest"},
+	}
+	if got := mc.Collectors["Probe"].Probes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Probes = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseMonitor([]byte(`{"Collectors": {"Probe": {"Probes": [{"Type": "tcp", "Timeout": "2"}]}}}`)); err == nil {
+		t.Error("invalid Timeout accepted")
+	}
+}
+
+func TestParseMonitor_Plugin(t *testing.T) {
+	mc, err := ParseMonitor([]byte(`{"Collectors": {"VisionStats": {"Enabled": true, "Interval": "60s",
+		"Plugin": {"Command": "plugins/vision_stats.exe", "Args": ["--line", "A"], "Mode": "daemon", "Timeout": "15s"}}}}`))
+	if err != nil {
+		t.Fatalf("ParseMonitor failed: %v", err)
+	}
+	want := &PluginSpec{Command: "plugins/vision_stats.exe", Args: []string{"--line", "A"}, Mode: "daemon", Timeout: 15 * time.Second}
+	if got := mc.Collectors["VisionStats"].Plugin; !reflect.DeepEqual(got, want) {
+		t.Errorf("Plugin = %+v, want %+v", got, want)
+	}
+	if mc.Collectors["VisionStats"].Interval != time.Minute {
+		t.Errorf("Interval = %v, want 1m", mc.Collectors["VisionStats"].Interval)
+	}
+
+	if _, err := ParseMonitor([]byte(`{"Collectors": {"X": {"Plugin": {"Command": "x", "Timeout": "10"}}}}`)); err == nil {
+		t.Error("invalid Plugin.Timeout accepted")
+	}
+}
+
+func TestParsePortSpec(t *testing.T) {
+	proto, port, err := ParsePortSpec("TCP:5000")
+	if err != nil || proto != "tcp" || port != 5000 {
+		t.Errorf("ParsePortSpec(TCP:5000) = %q, %d, %v", proto, port, err)
+	}
+	for _, bad := range []string{"", "5000", "tcp:", "tcp:abc", "tcp:65536", "icmp:1"} {
+		if _, _, err := ParsePortSpec(bad); err == nil {
+			t.Errorf("ParsePortSpec(%q) succeeded, want error", bad)
+		}
+	}
+}
+
 func TestMonitorConfig_Merge(t *testing.T) {
 	base := &MonitorConfig{
 		Collectors: map[string]CollectorConfig{
@@ -810,6 +940,56 @@ func TestParse_WithBatchConfig(t *testing.T) {
 	}
 }
 
+func TestParse_WithSpoolConfig(t *testing.T) {
+	input := `{
+		"Spool": {
+			"Enabled": true,
+			"Dir": "spool",
+			"MaxSizeMB": 200,
+			"SegmentSizeMB": 8,
+			"MaxAge": "24h",
+			"ReplayRecordsPerSec": 50
+		}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := SpoolConfig{
+		Enabled:             true,
+		Dir:                 "spool",
+		MaxSizeMB:           200,
+		SegmentSizeMB:       8,
+		MaxAge:              24 * time.Hour,
+		ReplayRecordsPerSec: 50,
+	}
+	if cfg.Spool != want {
+		t.Errorf("Spool = %+v, want %+v", cfg.Spool, want)
+	}
+}
+
+func TestParse_NoSpool_UsesDefaultsDisabled(t *testing.T) {
+	cfg, err := Parse([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Spool.Enabled {
+		t.Error("expected Spool disabled by default")
+	}
+	if cfg.Spool.Dir != "log/ResourceAgent/spool" {
+		t.Errorf("expected default Spool.Dir, got %q", cfg.Spool.Dir)
+	}
+	if cfg.Spool.MaxAge != 72*time.Hour {
+		t.Errorf("expected default Spool.MaxAge=72h, got %v", cfg.Spool.MaxAge)
+	}
+}
+
+func TestParse_InvalidSpoolMaxAge(t *testing.T) {
+	if _, err := Parse([]byte(`{"Spool": {"MaxAge": "forever"}}`)); err == nil {
+		t.Fatal("expected error for invalid Spool.MaxAge")
+	}
+}
+
 func TestParse_BatchFallbackFromKafka(t *testing.T) {
 	input := `{
 		"Kafka": {
@@ -887,6 +1067,264 @@ func TestParse_NoBatchNoKafkaBatch_UsesDefaults(t *testing.T) {
 	}
 }
 
+func TestParse_WithBreakerConfig(t *testing.T) {
+	input := `{
+		"Batch": {
+			"MaxRetryBackoff": "1m",
+			"BreakerThreshold": 3,
+			"BreakerOpenTimeout": "30s"
+		}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Batch.MaxRetryBackoff != time.Minute {
+		t.Errorf("expected MaxRetryBackoff=1m, got %v", cfg.Batch.MaxRetryBackoff)
+	}
+	if cfg.Batch.BreakerThreshold != 3 {
+		t.Errorf("expected BreakerThreshold=3, got %d", cfg.Batch.BreakerThreshold)
+	}
+	if cfg.Batch.BreakerOpenTimeout != 30*time.Second {
+		t.Errorf("expected BreakerOpenTimeout=30s, got %v", cfg.Batch.BreakerOpenTimeout)
+	}
+}
+
+func TestParse_NoBreakerConfig_UsesDefaults(t *testing.T) {
+	cfg, err := Parse([]byte(`{"SenderType": "kafkarest"}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Batch.MaxRetryBackoff != 5*time.Minute {
+		t.Errorf("expected default MaxRetryBackoff=5m, got %v", cfg.Batch.MaxRetryBackoff)
+	}
+	if cfg.Batch.BreakerThreshold != 5 {
+		t.Errorf("expected default BreakerThreshold=5, got %d", cfg.Batch.BreakerThreshold)
+	}
+	if cfg.Batch.BreakerOpenTimeout != 2*time.Minute {
+		t.Errorf("expected default BreakerOpenTimeout=2m, got %v", cfg.Batch.BreakerOpenTimeout)
+	}
+}
+
+func TestParse_InvalidBreakerOpenTimeout(t *testing.T) {
+	if _, err := Parse([]byte(`{"Batch": {"BreakerOpenTimeout": "soon"}}`)); err == nil {
+		t.Fatal("expected error for invalid BreakerOpenTimeout")
+	}
+}
+
+func TestParse_KafkaSyncDelivery(t *testing.T) {
+	cfg, err := Parse([]byte(`{"Kafka": {"SyncDelivery": true}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !cfg.Kafka.SyncDelivery {
+		t.Error("expected Kafka.SyncDelivery=true")
+	}
+
+	cfg, _ = Parse([]byte(`{"SenderType": "kafka"}`))
+	if cfg.Kafka.SyncDelivery {
+		t.Error("expected Kafka.SyncDelivery=false by default")
+	}
+}
+
+func TestParse_CompositeDestinations(t *testing.T) {
+	input := `{
+		"SenderType": "composite",
+		"Destinations": [
+			{"Name": "local", "Type": "file", "Include": ["SelfMetrics"]},
+			{"Type": "kafkarest", "Exclude": ["SelfMetrics"], "QueueSize": 50}
+		]
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(cfg.Destinations) != 2 {
+		t.Fatalf("expected 2 destinations, got %d", len(cfg.Destinations))
+	}
+	if d := cfg.Destinations[0]; d.Name != "local" || d.Type != "file" || len(d.Include) != 1 {
+		t.Errorf("Destinations[0] = %+v", d)
+	}
+	if d := cfg.Destinations[1]; d.Type != "kafkarest" || d.Exclude[0] != "SelfMetrics" || d.QueueSize != 50 {
+		t.Errorf("Destinations[1] = %+v", d)
+	}
+	if got := cfg.NetworkSenderType(); got != "kafkarest" {
+		t.Errorf("NetworkSenderType() = %q, want kafkarest", got)
+	}
+}
+
+func TestNetworkSenderType(t *testing.T) {
+	tests := []struct {
+		senderType string
+		dests      []DestinationConfig
+		want       string
+	}{
+		{"kafkarest", nil, "kafkarest"},
+		{"KAFKA", nil, "kafka"},
+		{"", nil, "kafka"},
+		{"file", nil, ""},
+		{"composite", []DestinationConfig{{Type: "file"}}, ""},
+		{"composite", []DestinationConfig{{Type: "file"}, {Type: "Kafka"}}, "kafka"},
+		{"otlp", nil, "otlp"},
+		{"composite", []DestinationConfig{{Type: "otlp"}, {Type: "kafkarest"}}, "kafkarest"},
+		{"composite", []DestinationConfig{{Type: "file"}, {Type: "OTLP"}}, "otlp"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{SenderType: tt.senderType, Destinations: tt.dests}
+		if got := cfg.NetworkSenderType(); got != tt.want {
+			t.Errorf("NetworkSenderType(%q, %v) = %q, want %q", tt.senderType, tt.dests, got, tt.want)
+		}
+	}
+}
+
+func TestUsesSenderType(t *testing.T) {
+	cfg := &Config{SenderType: "OTLP"}
+	if !cfg.UsesSenderType("otlp") {
+		t.Error("expected otlp in use for SenderType=OTLP")
+	}
+
+	cfg = &Config{SenderType: "composite", Destinations: []DestinationConfig{{Type: "file"}, {Type: "otlp"}}}
+	if !cfg.UsesSenderType("otlp") || cfg.UsesSenderType("kafka") {
+		t.Error("composite must report exactly its destination types")
+	}
+
+	cfg = &Config{SenderType: "file", Destinations: []DestinationConfig{{Type: "otlp"}}}
+	if cfg.UsesSenderType("otlp") {
+		t.Error("Destinations must be ignored unless SenderType=composite")
+	}
+}
+
+func TestParse_OTLPConfig(t *testing.T) {
+	input := `{
+		"SenderType": "otlp",
+		"OTLP": {"Endpoint": "http://otel:4318", "Headers": {"X-Api-Key": "secret"}}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.OTLP.Endpoint != "http://otel:4318" {
+		t.Errorf("OTLP.Endpoint = %q", cfg.OTLP.Endpoint)
+	}
+	if cfg.OTLP.Headers["X-Api-Key"] != "secret" {
+		t.Errorf("OTLP.Headers = %v", cfg.OTLP.Headers)
+	}
+}
+
+func TestParse_KafkaRestConfig(t *testing.T) {
+	cfg, err := Parse([]byte(`{"KafkaRest": {"Compression": "gzip", "CompressionMinBytes": 4096}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := KafkaRestConfig{APIVersion: "v2", EmbeddedFormat: "json", Compression: "gzip", CompressionMinBytes: 4096}
+	if cfg.KafkaRest != want {
+		t.Errorf("KafkaRest = %+v, want %+v", cfg.KafkaRest, want)
+	}
+
+	cfg, err = Parse([]byte(`{"SenderType": "kafkarest"}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want = KafkaRestConfig{APIVersion: "v2", EmbeddedFormat: "json", Compression: "none", CompressionMinBytes: 1024}
+	if cfg.KafkaRest != want {
+		t.Errorf("default KafkaRest = %+v, want %+v", cfg.KafkaRest, want)
+	}
+}
+
+func TestParse_KafkaRestV3Avro(t *testing.T) {
+	input := `{
+		"KafkaRest": {"APIVersion": "v3", "ClusterID": "lkc-1", "EmbeddedFormat": "avro", "ValueSchemaID": 12, "KeySchemaID": 11}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	kr := cfg.KafkaRest
+	if kr.APIVersion != "v3" || kr.ClusterID != "lkc-1" || kr.EmbeddedFormat != "avro" || kr.ValueSchemaID != 12 || kr.KeySchemaID != 11 {
+		t.Errorf("KafkaRest = %+v", kr)
+	}
+	if kr.Compression != "none" {
+		t.Errorf("Compression = %q, want default none", kr.Compression)
+	}
+}
+
+func TestParse_InfluxConfig(t *testing.T) {
+	input := `{
+		"SenderType": "influx",
+		"File": {"Format": "influx"},
+		"Influx": {"URL": "http://influxdb:8086", "Org": "fab", "Bucket": "resource", "Token": "tok"}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := InfluxConfig{URL: "http://influxdb:8086", Org: "fab", Bucket: "resource", Token: "tok"}
+	if cfg.Influx != want {
+		t.Errorf("Influx = %+v, want %+v", cfg.Influx, want)
+	}
+	if cfg.File.Format != "influx" {
+		t.Errorf("File.Format = %q, want influx", cfg.File.Format)
+	}
+	if got := cfg.NetworkSenderType(); got != "influx" {
+		t.Errorf("NetworkSenderType() = %q, want influx", got)
+	}
+}
+
+func TestParse_MQTTConfig(t *testing.T) {
+	input := `{
+		"SenderType": "mqtt",
+		"MQTT": {
+			"Broker": "ssl://broker:8883",
+			"TopicTemplate": "plant/{line}/{eqpid}/{type}",
+			"QoS": 1,
+			"Format": "json",
+			"EnableTLS": true,
+			"TLSCAFile": "ca.pem",
+			"KeepAlive": "15s",
+			"MaxReconnectInterval": "2m"
+		}
+	}`
+	cfg, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	m := cfg.MQTT
+	if m.Broker != "ssl://broker:8883" || m.TopicTemplate != "plant/{line}/{eqpid}/{type}" || m.QoS != 1 || m.Format != "json" {
+		t.Errorf("MQTT = %+v", m)
+	}
+	if !m.EnableTLS || m.TLSCAFile != "ca.pem" {
+		t.Errorf("MQTT TLS = %v/%q", m.EnableTLS, m.TLSCAFile)
+	}
+	if m.KeepAlive != 15*time.Second || m.MaxReconnectInterval != 2*time.Minute {
+		t.Errorf("MQTT durations = %v/%v", m.KeepAlive, m.MaxReconnectInterval)
+	}
+	if m.StatusTopic != "ears/{process}/{model}/{eqpid}/status" {
+		t.Errorf("StatusTopic default not kept: %q", m.StatusTopic)
+	}
+
+	if _, err := Parse([]byte(`{"MQTT": {"KeepAlive": "soon"}}`)); err == nil {
+		t.Error("expected error for invalid MQTT.KeepAlive")
+	}
+}
+
+func TestParse_PrometheusConfig(t *testing.T) {
+	cfg, err := Parse([]byte(`{"Prometheus": {"Enabled": true, "ListenAddress": ":9100"}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !cfg.Prometheus.Enabled || cfg.Prometheus.ListenAddress != ":9100" {
+		t.Errorf("Prometheus = %+v, want {true :9100}", cfg.Prometheus)
+	}
+
+	cfg, _ = Parse([]byte(`{}`))
+	if cfg.Prometheus.Enabled {
+		t.Error("expected Prometheus disabled by default")
+	}
+	if cfg.Prometheus.ListenAddress != "127.0.0.1:9464" {
+		t.Errorf("expected default ListenAddress, got %q", cfg.Prometheus.ListenAddress)
+	}
+}
+
 func TestParse_InvalidBatchDuration(t *testing.T) {
 	input := `{"Batch": {"FlushFrequency": "invalid"}}`
 	_, err := Parse([]byte(input))
@@ -11,18 +11,25 @@ import (
 
 // rawConfig is used for JSON unmarshaling with duration strings.
 type rawConfig struct {
-	SenderType                  string         `json:"SenderType"`
-	File                        FileConfig     `json:"File"`
-	Kafka                       rawKafkaConfig `json:"Kafka"`
-	Batch                       rawBatchConfig `json:"Batch"`
-	VirtualAddressList          string         `json:"VirtualAddressList"`
-	Redis                       RedisConfig    `json:"Redis"`
-	PrivateIPAddressPattern     string         `json:"PrivateIPAddressPattern"`
-	SOCKSProxy                  SOCKSConfig    `json:"SocksProxy"`
-	ServiceDiscoveryPort        int            `json:"ServiceDiscoveryPort"`
-	ResourceMonitorTopic        string         `json:"ResourceMonitorTopic"`
-	TimeDiffSyncInterval        int            `json:"TimeDiffSyncInterval"`
-	UpdateServerAddressInterval string         `json:"UpdateServerAddressInterval"`
+	SenderType                  string              `json:"SenderType"`
+	File                        FileConfig          `json:"File"`
+	Destinations                []DestinationConfig `json:"Destinations"`
+	Kafka                       rawKafkaConfig      `json:"Kafka"`
+	KafkaRest                   KafkaRestConfig     `json:"KafkaRest"`
+	Batch                       rawBatchConfig      `json:"Batch"`
+	Spool                       rawSpoolConfig      `json:"Spool"`
+	Prometheus                  PrometheusConfig    `json:"Prometheus"`
+	OTLP                        OTLPConfig          `json:"OTLP"`
+	Influx                      InfluxConfig        `json:"Influx"`
+	MQTT                        rawMQTTConfig       `json:"MQTT"`
+	VirtualAddressList          string              `json:"VirtualAddressList"`
+	Redis                       RedisConfig         `json:"Redis"`
+	PrivateIPAddressPattern     string              `json:"PrivateIPAddressPattern"`
+	SOCKSProxy                  SOCKSConfig         `json:"SocksProxy"`
+	ServiceDiscoveryPort        int                 `json:"ServiceDiscoveryPort"`
+	ResourceMonitorTopic        string              `json:"ResourceMonitorTopic"`
+	TimeDiffSyncInterval        int                 `json:"TimeDiffSyncInterval"`
+	UpdateServerAddressInterval string              `json:"UpdateServerAddressInterval"`
 }
 
 type rawKafkaConfig struct {
@@ -43,6 +50,7 @@ type rawKafkaConfig struct {
 	SASLMechanism  string `json:"SASLMechanism"`
 	SASLUser       string `json:"SASLUser"`
 	SASLPassword   string `json:"SASLPassword"`
+	SyncDelivery   bool   `json:"SyncDelivery"`
 }
 
 type rawBatchConfig struct {
@@ -52,18 +60,84 @@ type rawBatchConfig struct {
 	MaxRetries         int    `json:"MaxRetries"`
 	RetryBackoff       string `json:"RetryBackoff"`
 	MaxBufferedRecords int    `json:"MaxBufferedRecords"`
+	MaxRetryBackoff    string `json:"MaxRetryBackoff"`
+	BreakerThreshold   int    `json:"BreakerThreshold"`
+	BreakerOpenTimeout string `json:"BreakerOpenTimeout"`
+}
+
+type rawSpoolConfig struct {
+	Enabled             bool   `json:"Enabled"`
+	Dir                 string `json:"Dir"`
+	MaxSizeMB           int    `json:"MaxSizeMB"`
+	SegmentSizeMB       int    `json:"SegmentSizeMB"`
+	MaxAge              string `json:"MaxAge"`
+	ReplayRecordsPerSec int    `json:"ReplayRecordsPerSec"`
+}
+
+type rawMQTTConfig struct {
+	Broker               string `json:"Broker"`
+	ClientID             string `json:"ClientID"`
+	Username             string `json:"Username"`
+	Password             string `json:"Password"`
+	TopicTemplate        string `json:"TopicTemplate"`
+	StatusTopic          string `json:"StatusTopic"`
+	QoS                  int    `json:"QoS"`
+	Format               string `json:"Format"`
+	EnableTLS            bool   `json:"EnableTLS"`
+	TLSCertFile          string `json:"TLSCertFile"`
+	TLSKeyFile           string `json:"TLSKeyFile"`
+	TLSCAFile            string `json:"TLSCAFile"`
+	KeepAlive            string `json:"KeepAlive"`
+	MaxReconnectInterval string `json:"MaxReconnectInterval"`
 }
 
 type rawCollectorConfig struct {
-	Enabled            bool     `json:"Enabled"`
-	Interval           string   `json:"Interval"`
-	TopN               int      `json:"TopN,omitempty"`
-	Disks              []string `json:"Disks,omitempty"`
-	Interfaces         []string `json:"Interfaces,omitempty"`
-	IncludeZones       []string `json:"IncludeZones,omitempty"`
-	WatchProcesses     []string `json:"WatchProcesses,omitempty"`
-	RequiredProcesses  []string `json:"RequiredProcesses,omitempty"`
-	ForbiddenProcesses []string `json:"ForbiddenProcesses,omitempty"`
+	Enabled            bool             `json:"Enabled"`
+	Interval           string           `json:"Interval"`
+	TopN               int              `json:"TopN,omitempty"`
+	Disks              []string         `json:"Disks,omitempty"`
+	Interfaces         []string         `json:"Interfaces,omitempty"`
+	IncludeZones       []string         `json:"IncludeZones,omitempty"`
+	WatchProcesses     []string         `json:"WatchProcesses,omitempty"`
+	RequiredProcesses  []string         `json:"RequiredProcesses,omitempty"`
+	ForbiddenProcesses []string         `json:"ForbiddenProcesses,omitempty"`
+	GrowthWindow       string           `json:"GrowthWindow,omitempty"`
+	WatchPorts         []int            `json:"WatchPorts,omitempty"`
+	RequiredPorts      []PortRule       `json:"RequiredPorts,omitempty"`
+	ForbiddenPorts     []PortRule       `json:"ForbiddenPorts,omitempty"`
+	Files              []string         `json:"Files,omitempty"`
+	Patterns           []LogPattern     `json:"Patterns,omitempty"`
+	MaxEventLines      int              `json:"MaxEventLines,omitempty"`
+	StateFile          string           `json:"StateFile,omitempty"`
+	Paths              []rawPathRule    `json:"Paths,omitempty"`
+	MaxFiles           int              `json:"MaxFiles,omitempty"`
+	Probes             []rawProbeTarget `json:"Probes,omitempty"`
+	Plugin             *rawPluginSpec   `json:"Plugin,omitempty"`
+}
+
+// rawPluginSpec is PluginSpec with Timeout as a duration string.
+type rawPluginSpec struct {
+	Command string   `json:"Command"`
+	Args    []string `json:"Args,omitempty"`
+	Mode    string   `json:"Mode,omitempty"`
+	Timeout string   `json:"Timeout,omitempty"`
+}
+
+// rawProbeTarget is ProbeTarget with Timeout as a duration string.
+type rawProbeTarget struct {
+	Name    string `json:"Name,omitempty"`
+	Type    string `json:"Type"`
+	Address string `json:"Address,omitempty"`
+	Timeout string `json:"Timeout,omitempty"`
+	Proxy   bool   `json:"Proxy,omitempty"`
+}
+
+// rawPathRule is PathRule with MaxAge as a duration string.
+type rawPathRule struct {
+	Path      string `json:"Path"`
+	Recursive bool   `json:"Recursive,omitempty"`
+	MaxAge    string `json:"MaxAge,omitempty"`
+	QuotaMB   int64  `json:"QuotaMB,omitempty"`
 }
 
 type rawLoggingConfig struct {
@@ -105,8 +179,9 @@ func Parse(data []byte) (*Config, error) {
 
 func convertRawConfig(raw *rawConfig) (*Config, error) {
 	cfg := &Config{
-		SenderType: raw.SenderType,
-		File:       raw.File,
+		SenderType:   raw.SenderType,
+		File:         raw.File,
+		Destinations: raw.Destinations,
 	}
 
 	// Convert Kafka config
@@ -123,8 +198,24 @@ func convertRawConfig(raw *rawConfig) (*Config, error) {
 	}
 	cfg.Batch = *batch
 
+	spool, err := convertRawSpool(&raw.Spool)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Spool = *spool
+
+	mqtt, err := convertRawMQTT(&raw.MQTT)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MQTT = *mqtt
+
 	// Direct-mapped fields (no duration conversion needed)
 	cfg.VirtualAddressList = raw.VirtualAddressList
+	cfg.KafkaRest = raw.KafkaRest
+	cfg.Prometheus = raw.Prometheus
+	cfg.OTLP = raw.OTLP
+	cfg.Influx = raw.Influx
 	cfg.Redis = raw.Redis
 	cfg.PrivateIPAddressPattern = raw.PrivateIPAddressPattern
 	cfg.SOCKSProxy = raw.SOCKSProxy
@@ -195,9 +286,84 @@ func convertRawBatch(batch *rawBatchConfig, kafkaFallback *rawKafkaConfig) (*Bat
 		result.MaxBufferedRecords = batch.MaxBufferedRecords
 	}
 
+	if batch.MaxRetryBackoff != "" {
+		d, err := time.ParseDuration(batch.MaxRetryBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MaxRetryBackoff duration: %w", err)
+		}
+		result.MaxRetryBackoff = d
+	}
+
+	if batch.BreakerThreshold != 0 {
+		result.BreakerThreshold = batch.BreakerThreshold
+	}
+
+	if batch.BreakerOpenTimeout != "" {
+		d, err := time.ParseDuration(batch.BreakerOpenTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BreakerOpenTimeout duration: %w", err)
+		}
+		result.BreakerOpenTimeout = d
+	}
+
 	return result, nil
 }
 
+func convertRawSpool(raw *rawSpoolConfig) (*SpoolConfig, error) {
+	spool := &SpoolConfig{
+		Enabled:             raw.Enabled,
+		Dir:                 raw.Dir,
+		MaxSizeMB:           raw.MaxSizeMB,
+		SegmentSizeMB:       raw.SegmentSizeMB,
+		ReplayRecordsPerSec: raw.ReplayRecordsPerSec,
+	}
+
+	if raw.MaxAge != "" {
+		d, err := time.ParseDuration(raw.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Spool.MaxAge duration: %w", err)
+		}
+		spool.MaxAge = d
+	}
+
+	return spool, nil
+}
+
+func convertRawMQTT(raw *rawMQTTConfig) (*MQTTConfig, error) {
+	mqtt := &MQTTConfig{
+		Broker:        raw.Broker,
+		ClientID:      raw.ClientID,
+		Username:      raw.Username,
+		Password:      raw.Password,
+		TopicTemplate: raw.TopicTemplate,
+		StatusTopic:   raw.StatusTopic,
+		QoS:           raw.QoS,
+		Format:        raw.Format,
+		EnableTLS:     raw.EnableTLS,
+		TLSCertFile:   raw.TLSCertFile,
+		TLSKeyFile:    raw.TLSKeyFile,
+		TLSCAFile:     raw.TLSCAFile,
+	}
+
+	if raw.KeepAlive != "" {
+		d, err := time.ParseDuration(raw.KeepAlive)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MQTT.KeepAlive duration: %w", err)
+		}
+		mqtt.KeepAlive = d
+	}
+
+	if raw.MaxReconnectInterval != "" {
+		d, err := time.ParseDuration(raw.MaxReconnectInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MQTT.MaxReconnectInterval duration: %w", err)
+		}
+		mqtt.MaxReconnectInterval = d
+	}
+
+	return mqtt, nil
+}
+
 func convertRawKafka(raw *rawKafkaConfig) (*KafkaConfig, error) {
 	kafka := &KafkaConfig{
 		BrokerPort:    raw.BrokerPort,
@@ -211,6 +377,7 @@ func convertRawKafka(raw *rawKafkaConfig) (*KafkaConfig, error) {
 		SASLMechanism: raw.SASLMechanism,
 		SASLUser:      raw.SASLUser,
 		SASLPassword:  raw.SASLPassword,
+		SyncDelivery:  raw.SyncDelivery,
 	}
 
 	if raw.Timeout != "" {
@@ -234,6 +401,14 @@ func convertRawCollector(name string, raw *rawCollectorConfig) (*CollectorConfig
 		WatchProcesses:     raw.WatchProcesses,
 		RequiredProcesses:  raw.RequiredProcesses,
 		ForbiddenProcesses: raw.ForbiddenProcesses,
+		WatchPorts:         raw.WatchPorts,
+		RequiredPorts:      raw.RequiredPorts,
+		ForbiddenPorts:     raw.ForbiddenPorts,
+		Files:              raw.Files,
+		Patterns:           raw.Patterns,
+		MaxEventLines:      raw.MaxEventLines,
+		StateFile:          raw.StateFile,
+		MaxFiles:           raw.MaxFiles,
 	}
 
 	if raw.Interval != "" {
@@ -244,6 +419,49 @@ func convertRawCollector(name string, raw *rawCollectorConfig) (*CollectorConfig
 		coll.Interval = d
 	}
 
+	if raw.GrowthWindow != "" {
+		d, err := time.ParseDuration(raw.GrowthWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GrowthWindow for collector %s: %w", name, err)
+		}
+		coll.GrowthWindow = d
+	}
+
+	for i, rp := range raw.Paths {
+		rule := PathRule{Path: rp.Path, Recursive: rp.Recursive, QuotaMB: rp.QuotaMB}
+		if rp.MaxAge != "" {
+			d, err := time.ParseDuration(rp.MaxAge)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Paths[%d].MaxAge for collector %s: %w", i, name, err)
+			}
+			rule.MaxAge = d
+		}
+		coll.Paths = append(coll.Paths, rule)
+	}
+
+	for i, rp := range raw.Probes {
+		target := ProbeTarget{Name: rp.Name, Type: rp.Type, Address: rp.Address, Proxy: rp.Proxy}
+		if rp.Timeout != "" {
+			d, err := time.ParseDuration(rp.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Probes[%d].Timeout for collector %s: %w", i, name, err)
+			}
+			target.Timeout = d
+		}
+		coll.Probes = append(coll.Probes, target)
+	}
+
+	if rp := raw.Plugin; rp != nil {
+		coll.Plugin = &PluginSpec{Command: rp.Command, Args: rp.Args, Mode: rp.Mode}
+		if rp.Timeout != "" {
+			d, err := time.ParseDuration(rp.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Plugin.Timeout for collector %s: %w", name, err)
+			}
+			coll.Plugin.Timeout = d
+		}
+	}
+
 	return coll, nil
 }
 
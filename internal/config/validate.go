@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -42,18 +45,20 @@ func ValidateConfig(cfg *Config) error {
 	// SenderType
 	senderType := strings.ToLower(cfg.SenderType)
 	switch senderType {
-	case "kafka", "kafkarest", "file":
+	case "kafka", "kafkarest", "otlp", "influx", "mqtt", "file":
 		// ok
+	case "composite":
+		validateDestinations(&errs, cfg.Destinations)
 	default:
 		errs = append(errs, ValidationError{
 			Field:   "SenderType",
 			Value:   cfg.SenderType,
-			Message: "must be one of: kafka, kafkarest, file",
+			Message: "must be one of: kafka, kafkarest, otlp, influx, mqtt, file, composite",
 		})
 	}
 
 	// VirtualAddressList required for non-file senders
-	if senderType != "file" && cfg.VirtualAddressList == "" {
+	if cfg.NetworkSenderType() != "" && cfg.VirtualAddressList == "" {
 		errs = append(errs, ValidationError{
 			Field:   "VirtualAddressList",
 			Value:   "",
@@ -79,13 +84,13 @@ func ValidateConfig(cfg *Config) error {
 
 	// File.Format
 	switch strings.ToLower(cfg.File.Format) {
-	case "", "json", "grok", "legacy":
+	case "", "json", "grok", "legacy", "influx":
 		// ok
 	default:
 		errs = append(errs, ValidationError{
 			Field:   "File.Format",
 			Value:   cfg.File.Format,
-			Message: `must be one of: "", "json", "grok", "legacy"`,
+			Message: `must be one of: "", "json", "grok", "legacy", "influx"`,
 		})
 	}
 
@@ -103,6 +108,28 @@ func ValidateConfig(cfg *Config) error {
 		})
 	}
 
+	// KafkaRest API version and embedded format
+	validateKafkaRest(&errs, cfg.KafkaRest)
+
+	// KafkaRest request compression
+	switch strings.ToLower(cfg.KafkaRest.Compression) {
+	case "", "none", "gzip", "zstd":
+		// ok
+	default:
+		errs = append(errs, ValidationError{
+			Field:   "KafkaRest.Compression",
+			Value:   cfg.KafkaRest.Compression,
+			Message: `must be one of: "", "none", "gzip", "zstd"`,
+		})
+	}
+	if cfg.KafkaRest.CompressionMinBytes < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "KafkaRest.CompressionMinBytes",
+			Value:   fmt.Sprintf("%d", cfg.KafkaRest.CompressionMinBytes),
+			Message: "must be >= 0",
+		})
+	}
+
 	// Kafka.RequiredAcks
 	switch cfg.Kafka.RequiredAcks {
 	case 0, 1, -1:
@@ -145,6 +172,114 @@ func ValidateConfig(cfg *Config) error {
 		})
 	}
 
+	if cfg.Batch.MaxRetryBackoff < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "Batch.MaxRetryBackoff",
+			Value:   cfg.Batch.MaxRetryBackoff.String(),
+			Message: "must be >= 0",
+		})
+	}
+	if cfg.Batch.BreakerThreshold < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "Batch.BreakerThreshold",
+			Value:   fmt.Sprintf("%d", cfg.Batch.BreakerThreshold),
+			Message: "must be >= 0 (0 disables the circuit breaker)",
+		})
+	}
+	if cfg.Batch.BreakerThreshold > 0 && cfg.Batch.BreakerOpenTimeout <= 0 {
+		errs = append(errs, ValidationError{
+			Field:   "Batch.BreakerOpenTimeout",
+			Value:   cfg.Batch.BreakerOpenTimeout.String(),
+			Message: "must be > 0 when BreakerThreshold > 0",
+		})
+	}
+
+	// OTLP endpoint
+	if cfg.UsesSenderType("otlp") {
+		if u, err := url.Parse(cfg.OTLP.Endpoint); err != nil || u.Host == "" ||
+			(u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, ValidationError{
+				Field:   "OTLP.Endpoint",
+				Value:   cfg.OTLP.Endpoint,
+				Message: `must be an http(s) URL (e.g. "http://otel-collector:4318") when the otlp sender is used`,
+			})
+		}
+	}
+
+	// InfluxDB write target
+	if cfg.UsesSenderType("influx") {
+		if u, err := url.Parse(cfg.Influx.URL); err != nil || u.Host == "" ||
+			(u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, ValidationError{
+				Field:   "Influx.URL",
+				Value:   cfg.Influx.URL,
+				Message: `must be an http(s) URL (e.g. "http://influxdb:8086") when the influx sender is used`,
+			})
+		}
+		if cfg.Influx.Bucket == "" {
+			errs = append(errs, ValidationError{
+				Field:   "Influx.Bucket",
+				Value:   "",
+				Message: "required when the influx sender is used",
+			})
+		}
+	}
+
+	// MQTT broker
+	if cfg.UsesSenderType("mqtt") {
+		validateMQTT(&errs, cfg.MQTT)
+	}
+
+	// Prometheus exporter
+	if cfg.Prometheus.Enabled {
+		if _, port, err := net.SplitHostPort(cfg.Prometheus.ListenAddress); err != nil || port == "" {
+			errs = append(errs, ValidationError{
+				Field:   "Prometheus.ListenAddress",
+				Value:   cfg.Prometheus.ListenAddress,
+				Message: `must be host:port (e.g. "127.0.0.1:9464" or ":9464")`,
+			})
+		}
+	}
+
+	// Spool fields
+	if cfg.Spool.Enabled {
+		if cfg.Spool.Dir == "" {
+			errs = append(errs, ValidationError{
+				Field:   "Spool.Dir",
+				Value:   "",
+				Message: "required when Spool.Enabled=true",
+			})
+		}
+		if cfg.Spool.MaxSizeMB <= 0 {
+			errs = append(errs, ValidationError{
+				Field:   "Spool.MaxSizeMB",
+				Value:   fmt.Sprintf("%d", cfg.Spool.MaxSizeMB),
+				Message: "must be > 0",
+			})
+		}
+		if cfg.Spool.SegmentSizeMB <= 0 || cfg.Spool.SegmentSizeMB > cfg.Spool.MaxSizeMB {
+			errs = append(errs, ValidationError{
+				Field:   "Spool.SegmentSizeMB",
+				Value:   fmt.Sprintf("%d", cfg.Spool.SegmentSizeMB),
+				Message: "must be > 0 and <= Spool.MaxSizeMB",
+			})
+		}
+		if cfg.Spool.MaxAge < 0 {
+			errs = append(errs, ValidationError{
+				Field:   "Spool.MaxAge",
+				Value:   cfg.Spool.MaxAge.String(),
+				Message: "must be >= 0 (0 disables age-based expiry)",
+			})
+		}
+		if cfg.Spool.ReplayRecordsPerSec <= 0 {
+			errs = append(errs, ValidationError{
+				Field:   "Spool.ReplayRecordsPerSec",
+				Value:   fmt.Sprintf("%d", cfg.Spool.ReplayRecordsPerSec),
+				Message: "must be > 0",
+			})
+		}
+	}
+
 	// PrivateIPAddressPattern regex
 	if cfg.PrivateIPAddressPattern != "" {
 		if _, err := regexp.Compile(cfg.PrivateIPAddressPattern); err != nil {
@@ -188,6 +323,121 @@ func ValidateMonitorConfig(mc *MonitorConfig) error {
 				Message: "must be >= 1s for enabled collectors",
 			})
 		}
+		if cc.GrowthWindow < 0 {
+			errs = append(errs, ValidationError{
+				Field:   fmt.Sprintf("Collectors.%s.GrowthWindow", name),
+				Value:   cc.GrowthWindow.String(),
+				Message: "must be >= 0",
+			})
+		}
+		for i, port := range cc.WatchPorts {
+			if port < 1 || port > 65535 {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.WatchPorts[%d]", name, i),
+					Value:   fmt.Sprintf("%d", port),
+					Message: "must be between 1 and 65535",
+				})
+			}
+		}
+		for list, rules := range map[string][]PortRule{"RequiredPorts": cc.RequiredPorts, "ForbiddenPorts": cc.ForbiddenPorts} {
+			for i, rule := range rules {
+				if _, _, err := ParsePortSpec(rule.Port); err != nil {
+					errs = append(errs, ValidationError{
+						Field:   fmt.Sprintf("Collectors.%s.%s[%d].Port", name, list, i),
+						Value:   rule.Port,
+						Message: "must be tcp:<port> or udp:<port> with port between 1 and 65535",
+					})
+				}
+			}
+		}
+		if len(cc.Files) > 0 && len(cc.Patterns) == 0 {
+			errs = append(errs, ValidationError{
+				Field:   fmt.Sprintf("Collectors.%s.Patterns", name),
+				Value:   "[]",
+				Message: "at least one pattern is required when Files is set",
+			})
+		}
+		seen := make(map[string]bool, len(cc.Patterns))
+		for i, p := range cc.Patterns {
+			if p.Name == "" || seen[p.Name] {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Patterns[%d].Name", name, i),
+					Value:   p.Name,
+					Message: "must be non-empty and unique",
+				})
+			}
+			seen[p.Name] = true
+			if _, err := regexp.Compile(p.Regex); err != nil {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Patterns[%d].Regex", name, i),
+					Value:   p.Regex,
+					Message: fmt.Sprintf("invalid regular expression: %v", err),
+				})
+			}
+		}
+		if cc.MaxEventLines < 0 {
+			errs = append(errs, ValidationError{
+				Field:   fmt.Sprintf("Collectors.%s.MaxEventLines", name),
+				Value:   fmt.Sprintf("%d", cc.MaxEventLines),
+				Message: "must be >= 0",
+			})
+		}
+		for i, rule := range cc.Paths {
+			if _, err := filepath.Match(rule.Path, ""); rule.Path == "" || err != nil {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Paths[%d].Path", name, i),
+					Value:   rule.Path,
+					Message: "must be a directory, file or valid glob pattern",
+				})
+			}
+			if rule.MaxAge < 0 {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Paths[%d].MaxAge", name, i),
+					Value:   rule.MaxAge.String(),
+					Message: "must be >= 0",
+				})
+			}
+			if rule.QuotaMB < 0 {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Paths[%d].QuotaMB", name, i),
+					Value:   fmt.Sprintf("%d", rule.QuotaMB),
+					Message: "must be >= 0",
+				})
+			}
+		}
+		if cc.MaxFiles < 0 {
+			errs = append(errs, ValidationError{
+				Field:   fmt.Sprintf("Collectors.%s.MaxFiles", name),
+				Value:   fmt.Sprintf("%d", cc.MaxFiles),
+				Message: "must be >= 0",
+			})
+		}
+		for i, p := range cc.Probes {
+			errs = append(errs, validateProbeTarget(fmt.Sprintf("Collectors.%s.Probes[%d]", name, i), p)...)
+		}
+		if p := cc.Plugin; p != nil {
+			if strings.TrimSpace(p.Command) == "" {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Plugin.Command", name),
+					Value:   p.Command,
+					Message: "must not be empty",
+				})
+			}
+			if m := strings.ToLower(p.Mode); m != "" && m != "oneshot" && m != "daemon" {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Plugin.Mode", name),
+					Value:   p.Mode,
+					Message: "must be oneshot or daemon",
+				})
+			}
+			if p.Timeout < 0 {
+				errs = append(errs, ValidationError{
+					Field:   fmt.Sprintf("Collectors.%s.Plugin.Timeout", name),
+					Value:   p.Timeout.String(),
+					Message: "must be >= 0",
+				})
+			}
+		}
 	}
 
 	if len(errs) > 0 {
@@ -196,6 +446,52 @@ func ValidateMonitorConfig(mc *MonitorConfig) error {
 	return nil
 }
 
+// validateProbeTarget checks the Type, Address and Timeout of one Probe
+// collector target. field is the "Collectors.Probe.Probes[i]" prefix.
+func validateProbeTarget(field string, p ProbeTarget) ValidationErrors {
+	var errs ValidationErrors
+	switch strings.ToLower(p.Type) {
+	case "tcp":
+		if _, port, err := net.SplitHostPort(p.Address); err != nil || port == "" {
+			errs = append(errs, ValidationError{
+				Field:   field + ".Address",
+				Value:   p.Address,
+				Message: "must be host:port for a tcp probe",
+			})
+		}
+	case "http":
+		if u, err := url.Parse(p.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, ValidationError{
+				Field:   field + ".Address",
+				Value:   p.Address,
+				Message: "must be an http:// or https:// URL for an http probe",
+			})
+		}
+	case "redis", "servicediscovery", "kafkarest":
+		if p.Address != "" {
+			errs = append(errs, ValidationError{
+				Field:   field + ".Address",
+				Value:   p.Address,
+				Message: "must be empty, the agent endpoint is resolved at startup",
+			})
+		}
+	default:
+		errs = append(errs, ValidationError{
+			Field:   field + ".Type",
+			Value:   p.Type,
+			Message: "must be one of: tcp, http, redis, servicediscovery, kafkarest",
+		})
+	}
+	if p.Timeout < 0 {
+		errs = append(errs, ValidationError{
+			Field:   field + ".Timeout",
+			Value:   p.Timeout.String(),
+			Message: "must be >= 0",
+		})
+	}
+	return errs
+}
+
 // ValidateLoggingConfig validates Logging.json configuration.
 func ValidateLoggingConfig(lc *logger.Config) error {
 	var errs ValidationErrors
@@ -252,3 +548,161 @@ func validatePort(errs *ValidationErrors, field string, port int) {
 		})
 	}
 }
+
+// validateDestinations checks the composite sender's destination list.
+func validateDestinations(errs *ValidationErrors, dests []DestinationConfig) {
+	if len(dests) == 0 {
+		*errs = append(*errs, ValidationError{
+			Field:   "Destinations",
+			Value:   "[]",
+			Message: `at least one destination is required when SenderType="composite"`,
+		})
+		return
+	}
+
+	seenType := make(map[string]bool)
+	networkTypes := 0
+	for i, d := range dests {
+		field := fmt.Sprintf("Destinations[%d]", i)
+		t := strings.ToLower(d.Type)
+		switch t {
+		case "kafka", "kafkarest":
+			networkTypes++
+		case "otlp", "influx", "mqtt", "file":
+		default:
+			*errs = append(*errs, ValidationError{
+				Field:   field + ".Type",
+				Value:   d.Type,
+				Message: "must be one of: kafka, kafkarest, otlp, influx, mqtt, file",
+			})
+			continue
+		}
+		if seenType[t] {
+			*errs = append(*errs, ValidationError{
+				Field:   field + ".Type",
+				Value:   d.Type,
+				Message: "each destination type may appear only once (it reuses the top-level section of that type)",
+			})
+		}
+		seenType[t] = true
+		if d.QueueSize < 0 {
+			*errs = append(*errs, ValidationError{
+				Field:   field + ".QueueSize",
+				Value:   fmt.Sprintf("%d", d.QueueSize),
+				Message: "must be >= 0 (0 uses the default)",
+			})
+		}
+	}
+	if networkTypes > 1 {
+		*errs = append(*errs, ValidationError{
+			Field:   "Destinations",
+			Value:   fmt.Sprintf("%d", networkTypes),
+			Message: "at most one of kafka/kafkarest is supported (they share the discovered address)",
+		})
+	}
+}
+
+// validateMQTT checks the MQTT sender settings.
+func validateMQTT(errs *ValidationErrors, m MQTTConfig) {
+	u, err := url.Parse(m.Broker)
+	if err != nil || u.Host == "" {
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.Broker",
+			Value:   m.Broker,
+			Message: `must be a broker URL (e.g. "tcp://broker:1883" or "ssl://broker:8883") when the mqtt sender is used`,
+		})
+	} else {
+		switch u.Scheme {
+		case "tcp", "mqtt", "ssl", "tls", "mqtts", "tcps", "ws", "wss":
+		default:
+			*errs = append(*errs, ValidationError{
+				Field:   "MQTT.Broker",
+				Value:   m.Broker,
+				Message: "scheme must be one of: tcp, mqtt, ssl, tls, mqtts, tcps, ws, wss",
+			})
+		}
+	}
+	if m.QoS != 0 && m.QoS != 1 {
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.QoS",
+			Value:   fmt.Sprintf("%d", m.QoS),
+			Message: "must be 0 or 1",
+		})
+	}
+	switch strings.ToLower(m.Format) {
+	case "", "grok", "json":
+	default:
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.Format",
+			Value:   m.Format,
+			Message: `must be "grok" or "json"`,
+		})
+	}
+	if m.TopicTemplate == "" || strings.ContainsAny(m.TopicTemplate, "+#") {
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.TopicTemplate",
+			Value:   m.TopicTemplate,
+			Message: "required and must not contain wildcards (+, #)",
+		})
+	}
+	if m.StatusTopic == "" || strings.ContainsAny(m.StatusTopic, "+#") {
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.StatusTopic",
+			Value:   m.StatusTopic,
+			Message: "required and must not contain wildcards (+, #)",
+		})
+	}
+	if m.EnableTLS && (m.TLSCertFile == "") != (m.TLSKeyFile == "") {
+		*errs = append(*errs, ValidationError{
+			Field:   "MQTT.TLSCertFile",
+			Value:   m.TLSCertFile,
+			Message: "TLSCertFile and TLSKeyFile must be set together",
+		})
+	}
+}
+
+// validateKafkaRest checks the KafkaRest API version and embedded format.
+func validateKafkaRest(errs *ValidationErrors, kr KafkaRestConfig) {
+	switch strings.ToLower(kr.APIVersion) {
+	case "", "v2":
+	case "v3":
+		if kr.ClusterID == "" {
+			*errs = append(*errs, ValidationError{
+				Field:   "KafkaRest.ClusterID",
+				Value:   "",
+				Message: `required when APIVersion is "v3"`,
+			})
+		}
+	default:
+		*errs = append(*errs, ValidationError{
+			Field:   "KafkaRest.APIVersion",
+			Value:   kr.APIVersion,
+			Message: `must be one of: "", "v2", "v3"`,
+		})
+	}
+
+	switch strings.ToLower(kr.EmbeddedFormat) {
+	case "", "json", "binary":
+	case "avro":
+		if kr.ValueSchemaID <= 0 {
+			*errs = append(*errs, ValidationError{
+				Field:   "KafkaRest.ValueSchemaID",
+				Value:   fmt.Sprintf("%d", kr.ValueSchemaID),
+				Message: `must be a Schema Registry ID > 0 when EmbeddedFormat is "avro"`,
+			})
+		}
+	default:
+		*errs = append(*errs, ValidationError{
+			Field:   "KafkaRest.EmbeddedFormat",
+			Value:   kr.EmbeddedFormat,
+			Message: `must be one of: "", "json", "binary", "avro"`,
+		})
+	}
+	if kr.KeySchemaID < 0 {
+		*errs = append(*errs, ValidationError{
+			Field:   "KafkaRest.KeySchemaID",
+			Value:   fmt.Sprintf("%d", kr.KeySchemaID),
+			Message: "must be >= 0",
+		})
+	}
+}
@@ -177,6 +177,214 @@ func TestValidateConfig_InvalidBatchFields(t *testing.T) {
 	assertFieldError(t, errs, "Batch.MaxRetries")
 }
 
+func TestValidateConfig_InvalidBreakerFields(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Batch.MaxRetryBackoff = -time.Second
+	cfg.Batch.BreakerOpenTimeout = 0
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected errors for invalid breaker fields")
+	}
+	assertFieldError(t, err, "Batch.MaxRetryBackoff")
+	assertFieldError(t, err, "Batch.BreakerOpenTimeout")
+
+	cfg = DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Batch.BreakerThreshold = -1
+	assertFieldError(t, ValidateConfig(cfg), "Batch.BreakerThreshold")
+}
+
+func TestValidateConfig_CompositeFileOnly_NoVirtualAddressRequired(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SenderType = "composite"
+	cfg.Destinations = []DestinationConfig{{Type: "file"}}
+
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestValidateConfig_InvalidDestinations(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SenderType = "composite"
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Destinations = []DestinationConfig{
+		{Type: "kafka"},
+		{Type: "kafkarest"},
+		{Type: "file", QueueSize: -1},
+		{Type: "file"},
+		{Type: "syslog"},
+	}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected errors for invalid destinations")
+	}
+	assertFieldError(t, err, "Destinations")
+	assertFieldError(t, err, "Destinations[2].QueueSize")
+	assertFieldError(t, err, "Destinations[3].Type")
+	assertFieldError(t, err, "Destinations[4].Type")
+
+	cfg.Destinations = nil
+	assertFieldError(t, ValidateConfig(cfg), "Destinations")
+}
+
+func TestValidateConfig_OTLPEndpoint(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SenderType = "otlp"
+	cfg.VirtualAddressList = "10.0.0.1"
+	assertFieldError(t, ValidateConfig(cfg), "OTLP.Endpoint")
+
+	cfg.OTLP.Endpoint = "otel:4318"
+	assertFieldError(t, ValidateConfig(cfg), "OTLP.Endpoint")
+
+	cfg.OTLP.Endpoint = "https://otel.example.com/v1/metrics"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	// Also required when otlp is only a composite destination.
+	cfg = DefaultConfig()
+	cfg.SenderType = "composite"
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Destinations = []DestinationConfig{{Type: "kafkarest"}, {Type: "otlp"}}
+	assertFieldError(t, ValidateConfig(cfg), "OTLP.Endpoint")
+}
+
+func TestValidateConfig_KafkaRestCompression(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	for _, enc := range []string{"", "none", "gzip", "zstd", "GZIP"} {
+		cfg.KafkaRest.Compression = enc
+		if err := ValidateConfig(cfg); err != nil {
+			t.Errorf("Compression %q: expected no error, got: %v", enc, err)
+		}
+	}
+
+	cfg.KafkaRest.Compression = "snappy"
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.Compression")
+
+	cfg.KafkaRest.Compression = "gzip"
+	cfg.KafkaRest.CompressionMinBytes = -1
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.CompressionMinBytes")
+}
+
+func TestValidateConfig_KafkaRestAPI(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+
+	cfg.KafkaRest.APIVersion = "v1"
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.APIVersion")
+
+	cfg.KafkaRest.APIVersion = "v3"
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.ClusterID")
+	cfg.KafkaRest.ClusterID = "lkc-1"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("v3 with ClusterID: expected no error, got: %v", err)
+	}
+
+	cfg.KafkaRest.EmbeddedFormat = "protobuf"
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.EmbeddedFormat")
+
+	cfg.KafkaRest.EmbeddedFormat = "avro"
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.ValueSchemaID")
+	cfg.KafkaRest.ValueSchemaID = 5
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("avro with ValueSchemaID: expected no error, got: %v", err)
+	}
+
+	cfg.KafkaRest.EmbeddedFormat = "binary"
+	cfg.KafkaRest.KeySchemaID = -1
+	assertFieldError(t, ValidateConfig(cfg), "KafkaRest.KeySchemaID")
+}
+
+func TestValidateConfig_InfluxTarget(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SenderType = "influx"
+	cfg.VirtualAddressList = "10.0.0.1"
+	err := ValidateConfig(cfg)
+	assertFieldError(t, err, "Influx.URL")
+	assertFieldError(t, err, "Influx.Bucket")
+
+	cfg.Influx = InfluxConfig{URL: "http://influxdb:8086", Bucket: "resource"}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.SenderType = "file"
+	cfg.File.Format = "influx"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("File.Format=influx must be valid, got: %v", err)
+	}
+}
+
+func TestValidateConfig_MQTT(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SenderType = "mqtt"
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.MQTT.Broker = "tcp://broker:1883"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	cfg.MQTT.Broker = "http://broker"
+	cfg.MQTT.QoS = 2
+	cfg.MQTT.Format = "xml"
+	cfg.MQTT.TopicTemplate = "ears/+/{type}"
+	cfg.MQTT.StatusTopic = ""
+	err := ValidateConfig(cfg)
+	assertFieldError(t, err, "MQTT.Broker")
+	assertFieldError(t, err, "MQTT.QoS")
+	assertFieldError(t, err, "MQTT.Format")
+	assertFieldError(t, err, "MQTT.TopicTemplate")
+	assertFieldError(t, err, "MQTT.StatusTopic")
+}
+
+func TestValidateConfig_InvalidPrometheusAddress(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Prometheus.Enabled = true
+	cfg.Prometheus.ListenAddress = "9464"
+
+	assertFieldError(t, ValidateConfig(cfg), "Prometheus.ListenAddress")
+
+	cfg.Prometheus.Enabled = false
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("disabled exporter must not be validated, got: %v", err)
+	}
+}
+
+func TestValidateConfig_InvalidSpoolFields(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Spool.Enabled = true
+	cfg.Spool.Dir = ""
+	cfg.Spool.SegmentSizeMB = cfg.Spool.MaxSizeMB + 1
+	cfg.Spool.ReplayRecordsPerSec = 0
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected errors for invalid Spool fields")
+	}
+	assertFieldError(t, err, "Spool.Dir")
+	assertFieldError(t, err, "Spool.SegmentSizeMB")
+	assertFieldError(t, err, "Spool.ReplayRecordsPerSec")
+}
+
+func TestValidateConfig_DisabledSpool_NotValidated(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VirtualAddressList = "10.0.0.1"
+	cfg.Spool.Enabled = false
+	cfg.Spool.MaxSizeMB = 0
+
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("disabled spool should not be validated, got: %v", err)
+	}
+}
+
 func TestValidateConfig_InvalidRegexPattern(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.VirtualAddressList = "10.0.0.1"
@@ -257,6 +465,175 @@ func TestValidateMonitorConfig_DisabledCollector_ShortIntervalOK(t *testing.T) {
 	}
 }
 
+func TestValidateMonitorConfig_NegativeGrowthWindow(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"ProcessDetail": {Enabled: true, Interval: time.Minute, GrowthWindow: -time.Hour},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for negative GrowthWindow")
+	}
+	assertFieldError(t, err, "Collectors.ProcessDetail.GrowthWindow")
+}
+
+func TestValidateMonitorConfig_WatchPortsRange(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"Connections": {Enabled: true, Interval: time.Minute, WatchPorts: []int{5000, 0, 70000}},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for out-of-range WatchPorts")
+	}
+	assertFieldError(t, err, "Collectors.Connections.WatchPorts[1]")
+	assertFieldError(t, err, "Collectors.Connections.WatchPorts[2]")
+}
+
+func TestValidateMonitorConfig_PortRules(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"ProcessWatch": {
+				Enabled:        true,
+				Interval:       time.Minute,
+				RequiredPorts:  []PortRule{{Port: "tcp:5000", Process: "EqpHost.exe"}, {Port: "5000"}},
+				ForbiddenPorts: []PortRule{{Port: "UDP:161"}, {Port: "sctp:3389"}, {Port: "tcp:0"}},
+			},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for invalid port rules")
+	}
+	assertFieldError(t, err, "Collectors.ProcessWatch.RequiredPorts[1].Port")
+	assertFieldError(t, err, "Collectors.ProcessWatch.ForbiddenPorts[1].Port")
+	assertFieldError(t, err, "Collectors.ProcessWatch.ForbiddenPorts[2].Port")
+	if errs := err.(ValidationErrors); len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+}
+
+func TestValidateMonitorConfig_LogWatch(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"LogWatch": {
+				Enabled:  true,
+				Interval: time.Minute,
+				Files:    []string{"eqp.log"},
+				Patterns: []LogPattern{
+					{Name: "alarm", Regex: "ALARM"},
+					{Name: "alarm", Regex: "Exception"},
+					{Name: "", Regex: "Timeout"},
+					{Name: "bad", Regex: "(unclosed"},
+				},
+				MaxEventLines: -1,
+			},
+			"LogWatch2": {Enabled: true, Interval: time.Minute, Files: []string{"eqp.log"}},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for invalid log watch config")
+	}
+	assertFieldError(t, err, "Collectors.LogWatch.Patterns[1].Name")
+	assertFieldError(t, err, "Collectors.LogWatch.Patterns[2].Name")
+	assertFieldError(t, err, "Collectors.LogWatch.Patterns[3].Regex")
+	assertFieldError(t, err, "Collectors.LogWatch.MaxEventLines")
+	assertFieldError(t, err, "Collectors.LogWatch2.Patterns")
+	if errs := err.(ValidationErrors); len(errs) != 5 {
+		t.Errorf("got %d errors, want 5: %v", len(errs), errs)
+	}
+}
+
+func TestValidateMonitorConfig_PathWatch(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"PathWatch": {
+				Enabled:  true,
+				Interval: time.Minute,
+				Paths: []PathRule{
+					{Path: "D:/EQP/Result", MaxAge: 30 * time.Minute, QuotaMB: 1024},
+					{Path: ""},
+					{Path: "D:/EQP/[Image", MaxAge: -time.Minute, QuotaMB: -1},
+				},
+				MaxFiles: -1,
+			},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for invalid path watch config")
+	}
+	assertFieldError(t, err, "Collectors.PathWatch.Paths[1].Path")
+	assertFieldError(t, err, "Collectors.PathWatch.Paths[2].Path")
+	assertFieldError(t, err, "Collectors.PathWatch.Paths[2].MaxAge")
+	assertFieldError(t, err, "Collectors.PathWatch.Paths[2].QuotaMB")
+	assertFieldError(t, err, "Collectors.PathWatch.MaxFiles")
+	if errs := err.(ValidationErrors); len(errs) != 5 {
+		t.Errorf("got %d errors, want 5: %v", len(errs), errs)
+	}
+}
+
+func TestValidateMonitorConfig_Probe(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"Probe": {
+				Enabled:  true,
+				Interval: time.Minute,
+				Probes: []ProbeTarget{
+					{Name: "PLC", Type: "tcp", Address: "192.168.0.10:502"},
+					{Name: "MES", Type: "HTTP", Address: "https://mes.local/health", Timeout: 3 * time.Second},
+					{Type: "redis"},
+					{Type: "tcp", Address: "192.168.0.10"},
+					{Type: "http", Address: "mes.local/health"},
+					{Type: "kafkarest", Address: "10.0.0.1:8082"},
+					{Type: "icmp", Address: "10.0.0.1", Timeout: -time.Second},
+				},
+			},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for invalid probe config")
+	}
+	assertFieldError(t, err, "Collectors.Probe.Probes[3].Address")
+	assertFieldError(t, err, "Collectors.Probe.Probes[4].Address")
+	assertFieldError(t, err, "Collectors.Probe.Probes[5].Address")
+	assertFieldError(t, err, "Collectors.Probe.Probes[6].Type")
+	assertFieldError(t, err, "Collectors.Probe.Probes[6].Timeout")
+	if errs := err.(ValidationErrors); len(errs) != 5 {
+		t.Errorf("got %d errors, want 5: %v", len(errs), errs)
+	}
+}
+
+func TestValidateMonitorConfig_Plugin(t *testing.T) {
+	mc := &MonitorConfig{
+		Collectors: map[string]CollectorConfig{
+			"VisionStats": {Enabled: true, Interval: time.Minute, Plugin: &PluginSpec{Command: "plugins/vision_stats.exe", Mode: "Daemon"}},
+			"LineCounter": {Enabled: true, Interval: time.Minute, Plugin: &PluginSpec{Command: " ", Mode: "cron", Timeout: -time.Second}},
+		},
+	}
+
+	err := ValidateMonitorConfig(mc)
+	if err == nil {
+		t.Fatal("expected error for invalid plugin config")
+	}
+	assertFieldError(t, err, "Collectors.LineCounter.Plugin.Command")
+	assertFieldError(t, err, "Collectors.LineCounter.Plugin.Mode")
+	assertFieldError(t, err, "Collectors.LineCounter.Plugin.Timeout")
+	if errs := err.(ValidationErrors); len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+}
+
 // --- Step 5: ValidateLoggingConfig ---
 
 func TestValidateLoggingConfig_ValidDefault(t *testing.T) {
@@ -148,6 +148,7 @@ func (s *Scheduler) collect(ctx context.Context, c collector.Collector) {
 	//   - LhmProvider (Phase 1-1): timeout 3회 연속 → Process.Kill + 응답 drain → leak 0
 	//   - StorageHealth WMI (Phase 1-2): in-flight flag로 쿼리 중복 방지, stale-cache fallback
 	//     → worst-case in-flight goroutine 1개로 bounded
+	//   - Plugin collector: Plugin.Timeout(기본 10s) 초과 시 Process.Kill + drain, ctx 취소도 동일
 	// 그 외 collector는 ctx 존중 가정. 새 collector 추가 시 동일 보호장치 검토 필요.
 	//
 	// 이 값을 줄일 때 주의: 위 두 보호장치의 trigger 조건도 함께 검토할 것.
@@ -167,10 +168,13 @@ func (s *Scheduler) collect(ctx context.Context, c collector.Collector) {
 		return
 	}
 
+	// nil data means nothing to report this cycle (no targets configured,
+	// inventory unchanged), which is normal and not worth a warning.
 	if data == nil {
-		log.Warn().
+		log.Debug().
 			Str("collector", name).
-			Msg("Collector returned nil data")
+			Dur("duration", duration).
+			Msg("Collector returned no data")
 		return
 	}
 
@@ -199,6 +203,10 @@ func (s *Scheduler) collect(ctx context.Context, c collector.Collector) {
 		return
 	}
 
+	if n, ok := c.(collector.SentNotifier); ok {
+		n.Sent(data)
+	}
+
 	s.lastActivityMs.Store(time.Now().UnixMilli())
 
 	log.Debug().
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -93,17 +94,18 @@ func (s *mockCollectorSource) EnabledCollectors() []collector.Collector {
 	return result
 }
 
-// mockSender implements sender.Sender for testing.
+// mockSender implements sender.Sender for testing. Send fails with err when set.
 type mockSender struct {
 	mu    sync.Mutex
 	sends int
+	err   error
 }
 
 func (s *mockSender) Send(_ context.Context, _ *collector.MetricData) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.sends++
-	return nil
+	return s.err
 }
 
 func (s *mockSender) SendBatch(_ context.Context, _ []*collector.MetricData) error {
@@ -362,3 +364,30 @@ func TestReconfigure_PreservesParentContext(t *testing.T) {
 		t.Fatal("Stop() timed out - parent context not propagated after Reconfigure")
 	}
 }
+
+// notifyingCollector records the data the scheduler reports as Sent.
+type notifyingCollector struct {
+	*mockCollector
+	sent []*collector.MetricData
+}
+
+func (n *notifyingCollector) Sent(data *collector.MetricData) {
+	n.sent = append(n.sent, data)
+}
+
+func TestCollect_SentOnlyAfterSuccessfulSend(t *testing.T) {
+	nc := &notifyingCollector{mockCollector: newMockCollector("Inventory", time.Minute, true)}
+	snd := &mockSender{err: errors.New("buffer full")}
+	sched := New(&mockCollectorSource{}, snd, "agent1", "host1")
+
+	sched.collect(context.Background(), nc)
+	if len(nc.sent) != 0 {
+		t.Fatalf("Sent called %d times after a failed send, want 0", len(nc.sent))
+	}
+
+	snd.err = nil
+	sched.collect(context.Background(), nc)
+	if len(nc.sent) != 1 || nc.sent[0].AgentID != "agent1" {
+		t.Errorf("Sent = %+v after a successful send, want the sent data once", nc.sent)
+	}
+}
@@ -0,0 +1,195 @@
+package sender
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"resourceagent/internal/logger"
+)
+
+// breakerState is the circuit breaker state. The numeric values are what
+// SelfMetrics emits as breaker_state.
+type breakerState int32
+
+const (
+	breakerClosed   breakerState = 0
+	breakerHalfOpen breakerState = 1
+	breakerOpen     breakerState = 2
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half-open"
+	case breakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// breakerJitter is the fraction added on top of the open timeout so that a
+// fleet of agents that lost KafkaRest at the same moment does not probe it
+// again in lockstep.
+const breakerJitter = 0.2
+
+// circuitBreaker gates flush attempts of BufferedHTTPTransport.
+//
+//   - closed: every attempt is allowed, but after a failure the next one is
+//     delayed by an exponential backoff with jitter (RetryBackoff doubling up
+//     to MaxRetryBackoff).
+//   - open: entered after `threshold` consecutive failures. No attempt is
+//     allowed until the open timeout (plus jitter) elapses.
+//   - half-open: a single probe flush is allowed; success closes the breaker,
+//     failure re-opens it.
+//
+// A threshold of 0 disables the open state (backoff only). All methods are
+// called from the transport's flush goroutine; the mutex only protects
+// against concurrent readers of State.
+type circuitBreaker struct {
+	clock       clock.Clock
+	threshold   int
+	openTimeout time.Duration
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	jitter      func() float64 // [0,1)
+
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	openUntil   time.Time
+	nextAttempt time.Time
+
+	// Lock-free observability (see BreakerStats).
+	stateObs    atomic.Int32
+	failuresObs atomic.Int64
+	opensTotal  atomic.Int64
+}
+
+func newCircuitBreaker(clk clock.Clock, threshold int, openTimeout, baseBackoff, maxBackoff time.Duration) *circuitBreaker {
+	if maxBackoff < baseBackoff {
+		maxBackoff = baseBackoff
+	}
+	return &circuitBreaker{
+		clock:       clk,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		baseBackoff: baseBackoff,
+		maxBackoff:  maxBackoff,
+		jitter:      rand.Float64,
+	}
+}
+
+// Allow reports whether a send may be attempted now, moving an open breaker
+// to half-open once its timeout has elapsed.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	switch b.state {
+	case breakerOpen:
+		if now.Before(b.openUntil) {
+			return false
+		}
+		b.transitionLocked(breakerHalfOpen)
+		return true
+	case breakerHalfOpen:
+		return true
+	default:
+		return !now.Before(b.nextAttempt)
+	}
+}
+
+// Healthy reports whether the most recent attempt succeeded. Spool replay
+// only runs while healthy so it never doubles as an outage probe.
+func (b *circuitBreaker) Healthy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerClosed && b.failures == 0
+}
+
+// Success records a delivered batch and closes the breaker.
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.failuresObs.Store(0)
+	b.nextAttempt = time.Time{}
+	if b.state != breakerClosed {
+		b.transitionLocked(breakerClosed)
+	}
+}
+
+// Failure records a failed attempt and schedules the next one.
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.failuresObs.Store(int64(b.failures))
+	now := b.clock.Now()
+
+	if b.state == breakerHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.openUntil = now.Add(b.openTimeout + time.Duration(float64(b.openTimeout)*breakerJitter*b.jitter()))
+		if b.state != breakerOpen {
+			b.transitionLocked(breakerOpen)
+		}
+		return
+	}
+	b.nextAttempt = now.Add(b.backoffLocked())
+}
+
+// backoffLocked returns base*2^(failures-1) capped at maxBackoff, with
+// "equal jitter": half fixed, half random.
+func (b *circuitBreaker) backoffLocked() time.Duration {
+	d := b.baseBackoff
+	for i := 1; i < b.failures && d < b.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > b.maxBackoff {
+		d = b.maxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(float64(d-half)*b.jitter())
+}
+
+func (b *circuitBreaker) transitionLocked(to breakerState) {
+	from := b.state
+	b.state = to
+	b.stateObs.Store(int32(to))
+	if to == breakerOpen {
+		b.opensTotal.Add(1)
+	}
+
+	log := logger.WithComponent("kafkarest-breaker")
+	ev := log.Info()
+	if to == breakerOpen {
+		ev = log.Warn()
+	}
+	ev.Str("from", from.String()).
+		Str("to", to.String()).
+		Int("consecutive_failures", b.failures).
+		Int64("open_total", b.opensTotal.Load())
+	if to == breakerOpen {
+		ev.Time("retry_at", b.openUntil)
+	}
+	ev.Msg("CIRCUIT_STATE_CHANGE KafkaRest circuit breaker transition")
+}
+
+// State returns the current state without side effects.
+func (b *circuitBreaker) State() breakerState {
+	return breakerState(b.stateObs.Load())
+}
+
+// Stats returns lock-free observability snapshots.
+func (b *circuitBreaker) Stats() (state, opens, failures int64) {
+	return int64(b.stateObs.Load()), b.opensTotal.Load(), b.failuresObs.Load()
+}
@@ -0,0 +1,101 @@
+package sender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+func newTestBreaker(threshold int) (*circuitBreaker, *clock.Mock) {
+	clk := clock.NewMock()
+	b := newCircuitBreaker(clk, threshold, time.Minute, time.Second, 8*time.Second)
+	b.jitter = func() float64 { return 0 }
+	return b, clk
+}
+
+func TestCircuitBreaker_BackoffDoublesUpToMax(t *testing.T) {
+	b, clk := newTestBreaker(0)
+	b.jitter = func() float64 { return 1 } // full backoff, deterministic
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second}
+	for i, d := range want {
+		b.Failure()
+		clk.Add(d - time.Millisecond)
+		if b.Allow() {
+			t.Fatalf("failure %d: Allow before %v backoff elapsed", i+1, d)
+		}
+		clk.Add(time.Millisecond)
+		if !b.Allow() {
+			t.Fatalf("failure %d: Allow = false after %v", i+1, d)
+		}
+	}
+	if b.State() != breakerClosed {
+		t.Errorf("state = %v, want closed with threshold 0", b.State())
+	}
+}
+
+func TestCircuitBreaker_OpensAfterThresholdAndRecovers(t *testing.T) {
+	b, clk := newTestBreaker(3)
+
+	for i := 0; i < 3; i++ {
+		clk.Add(10 * time.Second)
+		b.Failure()
+	}
+	if b.State() != breakerOpen {
+		t.Fatalf("state = %v, want open after 3 failures", b.State())
+	}
+	clk.Add(59 * time.Second)
+	if b.Allow() {
+		t.Fatal("open breaker allowed an attempt before its timeout")
+	}
+
+	clk.Add(time.Second)
+	if !b.Allow() || b.State() != breakerHalfOpen {
+		t.Fatalf("expected half-open probe after timeout, state = %v", b.State())
+	}
+	b.Success()
+	if b.State() != breakerClosed || !b.Healthy() {
+		t.Errorf("state = %v healthy = %v, want closed/healthy after success", b.State(), b.Healthy())
+	}
+
+	state, opens, failures := b.Stats()
+	if state != 0 || opens != 1 || failures != 0 {
+		t.Errorf("Stats = (%d, %d, %d), want (0, 1, 0)", state, opens, failures)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b, clk := newTestBreaker(1)
+
+	b.Failure()
+	clk.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("expected half-open probe")
+	}
+	b.Failure()
+	if b.State() != breakerOpen {
+		t.Fatalf("state = %v, want open after failed probe", b.State())
+	}
+	if b.Allow() {
+		t.Error("re-opened breaker must wait a full timeout")
+	}
+	if _, opens, _ := b.Stats(); opens != 2 {
+		t.Errorf("opens = %d, want 2", opens)
+	}
+}
+
+func TestCircuitBreaker_OpenTimeoutJitter(t *testing.T) {
+	b, clk := newTestBreaker(1)
+	b.jitter = func() float64 { return 0.5 }
+
+	b.Failure()
+	clk.Add(time.Minute + 5*time.Second) // 60s + 60s*0.2*0.5 = 66s
+	if b.Allow() {
+		t.Fatal("jittered open timeout elapsed too early")
+	}
+	clk.Add(time.Second)
+	if !b.Allow() {
+		t.Error("expected probe after jittered open timeout")
+	}
+}
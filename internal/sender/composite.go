@@ -0,0 +1,418 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	// defaultDestinationQueueSize is the per-destination queue length used
+	// when DestinationConfig.QueueSize is 0. Each element is one Send or
+	// SendBatch call, so this is ~minutes of data at typical intervals.
+	defaultDestinationQueueSize = 1000
+
+	// destinationSendTimeout bounds a single Send on a destination worker.
+	// Matches the scheduler's own send timeout.
+	destinationSendTimeout = 10 * time.Second
+)
+
+// compositeDrainTimeout bounds how long Close waits for one destination to
+// drain its queue before abandoning what is left. The batch in flight at
+// that point still completes (bounded by destinationSendTimeout) before the
+// sender is closed. Variable so tests can shorten it.
+var compositeDrainTimeout = 10 * time.Second
+
+// Retry delays for a destination that could not be created at startup:
+// doubling from destinationCreateRetryMin up to destinationCreateRetryMax.
+// Variables so tests can shorten them.
+var (
+	destinationCreateRetryMin = 5 * time.Second
+	destinationCreateRetryMax = 5 * time.Minute
+)
+
+// CompositeSender fans metric data out to several destinations
+// (SenderType "composite").
+//
+// Each destination has its own bounded queue and worker goroutine, so a
+// slow or dead destination never blocks or fails the others: Send only
+// enqueues, and a full queue drops data for that destination alone. Errors
+// are logged (sampled) and counted per destination instead of being
+// returned to the scheduler.
+//
+// A destination whose sender could not be created at startup (e.g. Kafka
+// broker down) keeps its queue and retries the creation in its worker with
+// backoff, logging DESTINATION_CREATE_FAILED on every failed attempt. Data
+// queues meanwhile and is delivered once the sender exists.
+type CompositeSender struct {
+	dests []*destination
+
+	mu     sync.RWMutex
+	closed bool
+
+	readyMu sync.Mutex
+	onReady []func(Sender) // see OnDestinationReady
+}
+
+// destination is one routed output of CompositeSender.
+type destination struct {
+	name    string
+	include map[string]bool // lower-cased MetricData.Type; empty = all
+	exclude map[string]bool
+
+	senderMu sync.Mutex
+	sender   Sender                 // nil until create succeeds
+	create   func() (Sender, error) // retries a sender that failed at startup
+
+	ready func(Sender) // stores a sender created by a retry (see destinationReady)
+
+	queue chan []*collector.MetricData
+	done  chan struct{}
+	stop  chan struct{} // closed by Close, ends the create retries
+	abort chan struct{} // closed by Close after the drain timeout
+
+	dropLogger zerolog.Logger
+
+	// Lock-free observability (see DestinationStats).
+	sentTotal    atomic.Int64
+	failedTotal  atomic.Int64
+	droppedTotal atomic.Int64
+}
+
+// NewCompositeSender starts a worker per destination. senders[i] is routed
+// according to dests[i].
+func NewCompositeSender(dests []config.DestinationConfig, senders []Sender) *CompositeSender {
+	return newCompositeSender(dests, senders, nil)
+}
+
+// newCompositeSender is NewCompositeSender where senders[i] may be nil: that
+// destination's worker then calls create(dests[i]) with backoff until it
+// succeeds or the sender is closed.
+func newCompositeSender(dests []config.DestinationConfig, senders []Sender, create func(config.DestinationConfig) (Sender, error)) *CompositeSender {
+	base := logger.WithComponent("composite-sender")
+	sampled := base.Sample(&zerolog.BasicSampler{N: 10})
+
+	c := &CompositeSender{}
+	for i, dc := range dests {
+		name := dc.Name
+		if name == "" {
+			name = strings.ToLower(dc.Type)
+		}
+		size := dc.QueueSize
+		if size <= 0 {
+			size = defaultDestinationQueueSize
+		}
+		d := &destination{
+			name:       name,
+			sender:     senders[i],
+			include:    typeSet(dc.Include),
+			exclude:    typeSet(dc.Exclude),
+			queue:      make(chan []*collector.MetricData, size),
+			done:       make(chan struct{}),
+			stop:       make(chan struct{}),
+			abort:      make(chan struct{}),
+			dropLogger: sampled.With().Str("destination", name).Logger(),
+		}
+		if d.sender == nil {
+			dc := dc
+			d.create = func() (Sender, error) { return create(dc) }
+			d.ready = func(snd Sender) { c.destinationReady(d, snd) }
+		}
+		c.dests = append(c.dests, d)
+		go d.run()
+
+		base.Info().
+			Str("destination", name).
+			Str("type", dc.Type).
+			Strs("include", dc.Include).
+			Strs("exclude", dc.Exclude).
+			Int("queue_size", size).
+			Bool("ready", senders[i] != nil).
+			Msg("Composite destination started")
+	}
+	return c
+}
+
+func typeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[strings.ToLower(t)] = true
+	}
+	return set
+}
+
+// accepts reports whether metric type t is routed to this destination.
+func (d *destination) accepts(t string) bool {
+	t = strings.ToLower(t)
+	if len(d.include) > 0 && !d.include[t] {
+		return false
+	}
+	return !d.exclude[t]
+}
+
+// Send routes data to every destination that accepts its type.
+func (c *CompositeSender) Send(_ context.Context, data *collector.MetricData) error {
+	if data == nil {
+		return nil
+	}
+	return c.dispatch([]*collector.MetricData{data})
+}
+
+// SendBatch routes each item to the destinations that accept its type,
+// preserving one SendBatch call per destination.
+func (c *CompositeSender) SendBatch(_ context.Context, data []*collector.MetricData) error {
+	return c.dispatch(data)
+}
+
+func (c *CompositeSender) dispatch(data []*collector.MetricData) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return errors.New("sender is closed")
+	}
+
+	for _, d := range c.dests {
+		var routed []*collector.MetricData
+		for _, md := range data {
+			if md != nil && d.accepts(md.Type) {
+				routed = append(routed, md)
+			}
+		}
+		if len(routed) == 0 {
+			continue
+		}
+		select {
+		case d.queue <- routed:
+		default:
+			newTotal := d.droppedTotal.Add(int64(len(routed)))
+			d.dropLogger.Error().
+				Int("dropped", len(routed)).
+				Int64("dropped_total", newTotal).
+				Int("queue_size", cap(d.queue)).
+				Msg("DESTINATION_QUEUE_FULL destination is not keeping up, data dropped (sampled 1/10)")
+		}
+	}
+	return nil
+}
+
+// run creates the sender if needed, then delivers queued data until the
+// queue is closed or Close gives up draining it.
+func (d *destination) run() {
+	defer close(d.done)
+	snd := d.currentSender()
+	if snd == nil {
+		if snd = d.awaitSender(); snd == nil {
+			return // closed before the sender could be created
+		}
+	}
+	for batch := range d.queue {
+		select {
+		case <-d.abort:
+			return
+		default:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), destinationSendTimeout)
+		var err error
+		if len(batch) == 1 {
+			err = snd.Send(ctx, batch[0])
+		} else {
+			err = snd.SendBatch(ctx, batch)
+		}
+		cancel()
+
+		if err != nil {
+			newTotal := d.failedTotal.Add(int64(len(batch)))
+			d.dropLogger.Warn().
+				Err(err).
+				Int("items", len(batch)).
+				Int64("failed_total", newTotal).
+				Msg("DESTINATION_SEND_FAILED (sampled 1/10)")
+			continue
+		}
+		d.sentTotal.Add(int64(len(batch)))
+	}
+}
+
+// awaitSender retries d.create with backoff until it succeeds, returning
+// the new sender, or until Close, returning nil.
+func (d *destination) awaitSender() Sender {
+	log := logger.WithComponent("composite-sender")
+	backoff := destinationCreateRetryMin
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-d.stop:
+			return nil
+		case <-time.After(backoff):
+		}
+		snd, err := d.create()
+		if err == nil {
+			d.ready(snd)
+			log.Info().Str("destination", d.name).Int("attempt", attempt).
+				Msg("Composite destination created after retry")
+			return snd
+		}
+		if backoff *= 2; backoff > destinationCreateRetryMax {
+			backoff = destinationCreateRetryMax
+		}
+		log.Warn().Err(err).Str("destination", d.name).Int("attempt", attempt).
+			Int("queued", len(d.queue)).Dur("next_retry", backoff).
+			Msg("DESTINATION_CREATE_FAILED destination still unavailable, data is queued")
+	}
+}
+
+func (d *destination) currentSender() Sender {
+	d.senderMu.Lock()
+	defer d.senderMu.Unlock()
+	return d.sender
+}
+
+// Senders returns the destination senders in configuration order, so callers
+// can reach type-specific features (KafkaSender transport swap, FileSender
+// console toggle) behind the composite. A destination still waiting to be
+// created is left out.
+func (c *CompositeSender) Senders() []Sender {
+	out := make([]Sender, 0, len(c.dests))
+	for _, d := range c.dests {
+		if snd := d.currentSender(); snd != nil {
+			out = append(out, snd)
+		}
+	}
+	return out
+}
+
+// OnDestinationReady calls fn with every destination sender that exists now
+// and, later, with each one created by a background retry (on that
+// destination's worker goroutine). Callers use it to wire type-specific
+// features, like the address refresher, that Senders alone would miss for
+// a destination that was down at startup. fn must not block.
+func (c *CompositeSender) OnDestinationReady(fn func(Sender)) {
+	c.readyMu.Lock()
+	c.onReady = append(c.onReady, fn)
+	var ready []Sender
+	for _, d := range c.dests {
+		if snd := d.currentSender(); snd != nil {
+			ready = append(ready, snd)
+		}
+	}
+	c.readyMu.Unlock()
+	for _, snd := range ready {
+		fn(snd)
+	}
+}
+
+// destinationReady stores a sender created by a retry and runs the
+// OnDestinationReady callbacks. Storing and snapshotting the callbacks under
+// readyMu means each callback sees each sender exactly once.
+func (c *CompositeSender) destinationReady(d *destination, snd Sender) {
+	c.readyMu.Lock()
+	d.senderMu.Lock()
+	d.sender = snd
+	d.senderMu.Unlock()
+	hooks := append(([]func(Sender))(nil), c.onReady...)
+	c.readyMu.Unlock()
+	for _, fn := range hooks {
+		fn(snd)
+	}
+}
+
+// DestinationReady reports whether the named destination's sender exists,
+// i.e. it was created at startup or a later retry succeeded. ok is false if
+// no destination has that name.
+func (c *CompositeSender) DestinationReady(name string) (ready, ok bool) {
+	for _, d := range c.dests {
+		if d.name == name {
+			return d.currentSender() != nil, true
+		}
+	}
+	return false, false
+}
+
+// DestinationStats returns cumulative sent, failed and dropped (queue full)
+// MetricData counts for the named destination. ok is false if no
+// destination has that name.
+func (c *CompositeSender) DestinationStats(name string) (sent, failed, dropped int64, ok bool) {
+	for _, d := range c.dests {
+		if d.name == name {
+			return d.sentTotal.Load(), d.failedTotal.Load(), d.droppedTotal.Load(), true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// Close stops accepting data, lets every destination drain its queue (up to
+// compositeDrainTimeout each, in parallel) and closes the destination senders.
+// A sender is closed only after its worker has returned, never during a Send.
+func (c *CompositeSender) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	for _, d := range c.dests {
+		close(d.stop)
+		close(d.queue)
+	}
+	c.mu.Unlock()
+
+	log := logger.WithComponent("composite-sender")
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, d := range c.dests {
+		wg.Add(1)
+		go func(d *destination) {
+			defer wg.Done()
+			select {
+			case <-d.done:
+			case <-time.After(compositeDrainTimeout):
+				log.Warn().
+					Str("destination", d.name).
+					Int("pending", len(d.queue)).
+					Msg("Destination did not drain before close timeout")
+				close(d.abort)
+				<-d.done // bounded by destinationSendTimeout
+			}
+			snd := d.currentSender()
+			if snd == nil {
+				if n := len(d.queue); n > 0 {
+					log.Warn().Str("destination", d.name).Int("pending", n).
+						Msg("Destination was never created, queued data discarded")
+				}
+				return
+			}
+			if err := snd.Close(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(d)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Unwrap returns the concrete senders behind snd, recursing through
+// wrappers (CompositeSender, TeeSender), or snd itself.
+func Unwrap(snd Sender) []Sender {
+	w, ok := snd.(interface{ Senders() []Sender })
+	if !ok {
+		return []Sender{snd}
+	}
+	var out []Sender
+	for _, s := range w.Senders() {
+		out = append(out, Unwrap(s)...)
+	}
+	return out
+}
@@ -0,0 +1,320 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/config"
+)
+
+// recordingSender collects the MetricData types it receives. If block is
+// set, every send waits on it first; err is returned from every send.
+type recordingSender struct {
+	mu     sync.Mutex
+	types  []string
+	block  chan struct{}
+	err    error
+	closed bool
+}
+
+func (r *recordingSender) Send(ctx context.Context, data *collector.MetricData) error {
+	return r.SendBatch(ctx, []*collector.MetricData{data})
+}
+
+func (r *recordingSender) SendBatch(_ context.Context, data []*collector.MetricData) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range data {
+		r.types = append(r.types, d.Type)
+	}
+	return r.err
+}
+
+func (r *recordingSender) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *recordingSender) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.types...)
+}
+
+func metric(typ string) *collector.MetricData {
+	return &collector.MetricData{Type: typ, Timestamp: time.Now()}
+}
+
+func TestCompositeSender_RoutesByIncludeExclude(t *testing.T) {
+	file := &recordingSender{}
+	kafka := &recordingSender{}
+	c := NewCompositeSender([]config.DestinationConfig{
+		{Name: "file", Type: "file"},
+		{Name: "kafka", Type: "kafkarest", Exclude: []string{"selfmetrics"}},
+	}, []Sender{file, kafka})
+
+	c.Send(context.Background(), metric("CPU"))
+	c.SendBatch(context.Background(), []*collector.MetricData{metric("SelfMetrics"), metric("Memory")})
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := file.received(); len(got) != 3 {
+		t.Errorf("file received %v, want all 3 types", got)
+	}
+	got := kafka.received()
+	if len(got) != 2 || got[0] != "CPU" || got[1] != "Memory" {
+		t.Errorf("kafka received %v, want [CPU Memory] (SelfMetrics excluded)", got)
+	}
+	if !file.closed || !kafka.closed {
+		t.Error("Close must close every destination sender")
+	}
+}
+
+func TestCompositeSender_IncludeOnly(t *testing.T) {
+	only := &recordingSender{}
+	c := NewCompositeSender([]config.DestinationConfig{
+		{Type: "file", Include: []string{"SelfMetrics"}},
+	}, []Sender{only})
+
+	c.Send(context.Background(), metric("CPU"))
+	c.Send(context.Background(), metric("SelfMetrics"))
+	c.Close()
+
+	if got := only.received(); len(got) != 1 || got[0] != "SelfMetrics" {
+		t.Errorf("received %v, want [SelfMetrics]", got)
+	}
+	if sent, _, _, ok := c.DestinationStats("file"); !ok || sent != 1 {
+		t.Errorf("DestinationStats(file) sent=%d ok=%v, want 1/true (name defaults to type)", sent, ok)
+	}
+}
+
+func TestCompositeSender_BlockedDestinationIsolated(t *testing.T) {
+	stuck := &recordingSender{block: make(chan struct{})}
+	healthy := &recordingSender{}
+	c := NewCompositeSender([]config.DestinationConfig{
+		{Name: "stuck", Type: "kafkarest", QueueSize: 2},
+		{Name: "healthy", Type: "file"},
+	}, []Sender{stuck, healthy})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := c.Send(context.Background(), metric("CPU")); err != nil {
+				t.Errorf("Send returned error: %v", err)
+			}
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked on a stuck destination")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(healthy.received()) < 10 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(healthy.received()); got != 10 {
+		t.Errorf("healthy destination received %d, want 10", got)
+	}
+	// QueueSize 2 queued (+1 in the worker if it already picked one up);
+	// the rest were dropped.
+	if _, _, dropped, _ := c.DestinationStats("stuck"); dropped < 7 || dropped > 8 {
+		t.Errorf("stuck dropped = %d, want 7 or 8", dropped)
+	}
+
+	close(stuck.block)
+	c.Close()
+}
+
+func TestCompositeSender_FailureCountedNotReturned(t *testing.T) {
+	failing := &recordingSender{err: errors.New("kafkarest down")}
+	c := NewCompositeSender([]config.DestinationConfig{{Name: "k", Type: "kafkarest"}}, []Sender{failing})
+
+	if err := c.Send(context.Background(), metric("CPU")); err != nil {
+		t.Fatalf("Send returned %v, want nil (failures are isolated)", err)
+	}
+	c.Close()
+
+	if _, failed, _, _ := c.DestinationStats("k"); failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+	if err := c.Send(context.Background(), metric("CPU")); err == nil {
+		t.Error("Send after Close must return an error")
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	a, b := &recordingSender{}, &recordingSender{}
+	c := NewCompositeSender([]config.DestinationConfig{{Type: "file"}, {Type: "kafka"}}, []Sender{a, b})
+	defer c.Close()
+
+	got := Unwrap(c)
+	if len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("Unwrap(composite) = %v, want destination senders", got)
+	}
+	if got := Unwrap(a); len(got) != 1 || got[0] != a {
+		t.Errorf("Unwrap(single) = %v, want itself", got)
+	}
+}
+
+func TestNewSender_CompositeRetriesBrokenDestination(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SenderType = "composite"
+	cfg.File.FilePath = filepath.Join(t.TempDir(), "metrics.log")
+	cfg.KafkaRestAddress = "" // kafka destination cannot resolve a broker
+	cfg.EqpInfo = &config.EqpInfoConfig{Process: "PROC", EqpModel: "MODEL", EqpID: "EQP"}
+	cfg.Destinations = []config.DestinationConfig{
+		{Name: "kafka", Type: "kafka"},
+		{Name: "file", Type: "file"},
+	}
+
+	snd, err := NewSender(cfg, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewSender: %v", err)
+	}
+	defer snd.Close()
+
+	members := Unwrap(snd)
+	if len(members) != 1 {
+		t.Fatalf("expected only the file destination, got %d", len(members))
+	}
+	if _, ok := members[0].(*FileSender); !ok {
+		t.Errorf("destination = %T, want *FileSender", members[0])
+	}
+	if ready, ok := snd.(*CompositeSender).DestinationReady("kafka"); !ok || ready {
+		t.Errorf("kafka DestinationReady = %v, %v; want false, true (retrying)", ready, ok)
+	}
+
+	cfg.Destinations = cfg.Destinations[:1]
+	if _, err := NewSender(cfg, func() int64 { return 0 }); err == nil {
+		t.Error("expected error when no destination can be created")
+	}
+}
+
+func TestCompositeSender_RetriesDestinationCreation(t *testing.T) {
+	oldMin, oldMax := destinationCreateRetryMin, destinationCreateRetryMax
+	destinationCreateRetryMin, destinationCreateRetryMax = time.Millisecond, 5*time.Millisecond
+	defer func() { destinationCreateRetryMin, destinationCreateRetryMax = oldMin, oldMax }()
+
+	kafka := &recordingSender{}
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+	create := func(config.DestinationConfig) (Sender, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts++; attempts < 3 {
+			return nil, errors.New("broker unavailable")
+		}
+		return kafka, nil
+	}
+	c := newCompositeSender([]config.DestinationConfig{{Name: "kafka", Type: "kafka"}}, []Sender{nil}, create)
+	readyCh := make(chan Sender, 2)
+	c.OnDestinationReady(func(snd Sender) { readyCh <- snd })
+
+	// Queued while the destination does not exist yet.
+	if err := c.Send(context.Background(), metric("cpu")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(kafka.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := kafka.received(); len(got) != 1 || got[0] != "cpu" {
+		t.Fatalf("kafka received %v after retry, want [cpu]", got)
+	}
+	if ready, _ := c.DestinationReady("kafka"); !ready {
+		t.Error("DestinationReady = false after successful retry")
+	}
+	if members := c.Senders(); len(members) != 1 || members[0] != kafka {
+		t.Errorf("Senders = %v, want the created sender", members)
+	}
+	select {
+	case snd := <-readyCh:
+		if snd != kafka {
+			t.Errorf("OnDestinationReady got %v, want the created sender", snd)
+		}
+	default:
+		t.Error("OnDestinationReady was not called for the created sender")
+	}
+	if len(readyCh) != 0 {
+		t.Error("OnDestinationReady called more than once for one sender")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !kafka.closed {
+		t.Error("created sender was not closed")
+	}
+}
+
+func TestCompositeSender_CloseStopsCreationRetries(t *testing.T) {
+	create := func(config.DestinationConfig) (Sender, error) {
+		return nil, errors.New("broker unavailable")
+	}
+	c := newCompositeSender([]config.DestinationConfig{{Name: "kafka", Type: "kafka"}}, []Sender{nil}, create)
+	if err := c.Send(context.Background(), metric("cpu")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- c.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a destination that was never created")
+	}
+}
+
+func TestCompositeSender_CloseWaitsForInFlightSend(t *testing.T) {
+	old := compositeDrainTimeout
+	compositeDrainTimeout = 10 * time.Millisecond
+	defer func() { compositeDrainTimeout = old }()
+
+	slow := &recordingSender{block: make(chan struct{})}
+	c := NewCompositeSender([]config.DestinationConfig{{Name: "slow", Type: "kafka"}}, []Sender{slow})
+	c.Send(context.Background(), metric("cpu"))    // in flight, blocked
+	c.Send(context.Background(), metric("memory")) // still queued at timeout
+
+	done := make(chan error, 1)
+	go func() { done <- c.Close() }()
+	time.Sleep(50 * time.Millisecond)
+	slow.mu.Lock()
+	closed := slow.closed
+	slow.mu.Unlock()
+	if closed {
+		t.Fatal("Close closed the sender while a Send was in flight")
+	}
+
+	close(slow.block)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return after the in-flight Send finished")
+	}
+	if !slow.closed {
+		t.Error("sender was not closed")
+	}
+	if got := slow.received(); len(got) != 1 || got[0] != "cpu" {
+		t.Errorf("received %v, want only the in-flight [cpu]", got)
+	}
+}
@@ -0,0 +1,130 @@
+package sender
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+// requestCompressor applies Content-Encoding to KafkaRest request bodies.
+//
+// Bodies smaller than minBytes are sent as-is. A proxy that answers HTTP 415
+// (Unsupported Media Type) turns compression off for the lifetime of the
+// transport; the caller retries that request uncompressed. The address
+// refresher recreates transports, so a proxy upgrade is picked up on the
+// next address change or restart.
+//
+// All methods are safe on a nil receiver (compression disabled).
+type requestCompressor struct {
+	encoding string // "gzip" or "zstd"
+	minBytes int
+
+	disabled atomic.Bool
+
+	// Byte counters for every body that was successfully POSTed: rawBytes is
+	// the encoded size before compression, wireBytes the size actually sent.
+	rawBytes  atomic.Int64
+	wireBytes atomic.Int64
+
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdErr  error
+}
+
+// newRequestCompressor returns nil when cfg.Compression is "" or "none".
+func newRequestCompressor(cfg config.KafkaRestConfig) *requestCompressor {
+	switch enc := strings.ToLower(cfg.Compression); enc {
+	case "gzip", "zstd":
+		return &requestCompressor{encoding: enc, minBytes: cfg.CompressionMinBytes}
+	default:
+		return nil
+	}
+}
+
+// compress returns the body to send and its Content-Encoding ("" when the
+// body is sent uncompressed). Compression errors fall back to the raw body.
+func (c *requestCompressor) compress(body []byte) ([]byte, string) {
+	if c == nil || c.disabled.Load() || len(body) < c.minBytes {
+		return body, ""
+	}
+
+	var (
+		out []byte
+		err error
+	)
+	switch c.encoding {
+	case "gzip":
+		out, err = gzipBytes(body)
+	case "zstd":
+		c.zstdOnce.Do(func() {
+			c.zstdEnc, c.zstdErr = zstd.NewWriter(nil)
+		})
+		if err = c.zstdErr; err == nil {
+			out = c.zstdEnc.EncodeAll(body, make([]byte, 0, len(body)/2))
+		}
+	}
+	if err != nil {
+		log := logger.WithComponent("kafkarest-compress")
+		log.Warn().Err(err).Str("encoding", c.encoding).Msg("COMPRESSION_FAILED, sending uncompressed")
+		return body, ""
+	}
+	return out, c.encoding
+}
+
+// setHeader sets Content-Encoding for a body returned by compress.
+func (c *requestCompressor) setHeader(h http.Header, encoding string) {
+	if encoding != "" {
+		h.Set("Content-Encoding", encoding)
+	}
+}
+
+// disable turns compression off after the server rejected it with HTTP 415.
+func (c *requestCompressor) disable(backend string) {
+	if c == nil || !c.disabled.CompareAndSwap(false, true) {
+		return
+	}
+	log := logger.WithComponent("kafkarest-compress")
+	log.Warn().
+		Str("backend", backend).
+		Str("encoding", c.encoding).
+		Msg("COMPRESSION_UNSUPPORTED: server returned HTTP 415, falling back to uncompressed requests")
+}
+
+// record accounts one delivered request body.
+func (c *requestCompressor) record(rawLen, wireLen int) {
+	if c == nil {
+		return
+	}
+	c.rawBytes.Add(int64(rawLen))
+	c.wireBytes.Add(int64(wireLen))
+}
+
+// stats returns the byte counters. ok is false when compression is not
+// configured.
+func (c *requestCompressor) stats() (rawBytes, wireBytes int64, ok bool) {
+	if c == nil {
+		return 0, 0, false
+	}
+	return c.rawBytes.Load(), c.wireBytes.Load(), true
+}
+
+func gzipBytes(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(body) / 2)
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,224 @@
+package sender
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"resourceagent/internal/config"
+)
+
+// compressRecorder is a KafkaRest stub that decodes Content-Encoding and
+// records what each request carried.
+type compressRecorder struct {
+	mu        sync.Mutex
+	encodings []string
+	bodies    [][]byte
+	reject    bool // answer 415 to compressed requests
+}
+
+func (c *compressRecorder) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enc := r.Header.Get("Content-Encoding")
+		if c.reject && enc != "" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			c.mu.Lock()
+			c.encodings = append(c.encodings, enc)
+			c.bodies = append(c.bodies, nil)
+			c.mu.Unlock()
+			return
+		}
+		var rd io.Reader = r.Body
+		switch enc {
+		case "gzip":
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("invalid gzip body: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			rd = zr
+		case "zstd":
+			zr, err := zstd.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("invalid zstd body: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer zr.Close()
+			rd = zr
+		}
+		body, err := io.ReadAll(rd)
+		if err != nil {
+			t.Errorf("reading %q body: %v", enc, err)
+		}
+		c.mu.Lock()
+		c.encodings = append(c.encodings, enc)
+		c.bodies = append(c.bodies, body)
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (c *compressRecorder) snapshot() ([]string, [][]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.encodings...), append([][]byte(nil), c.bodies...)
+}
+
+func newTestCompressedTransport(t *testing.T, rec *compressRecorder, restCfg config.KafkaRestConfig) *BufferedHTTPTransport {
+	t.Helper()
+	server := httptest.NewServer(rec.handler(t))
+	t.Cleanup(server.Close)
+	transport, err := NewSpooledHTTPTransport(server.URL, config.SOCKSConfig{}, newTestBatchConfig(), restCfg, nil)
+	if err != nil {
+		t.Fatalf("NewSpooledHTTPTransport: %v", err)
+	}
+	t.Cleanup(func() { transport.Close() })
+	return transport
+}
+
+func TestNewRequestCompressor_Disabled(t *testing.T) {
+	for _, enc := range []string{"", "none", "NONE"} {
+		if c := newRequestCompressor(config.KafkaRestConfig{Compression: enc}); c != nil {
+			t.Errorf("Compression %q: compressor = %+v, want nil", enc, c)
+		}
+	}
+	var c *requestCompressor
+	if body, enc := c.compress([]byte("x")); string(body) != "x" || enc != "" {
+		t.Errorf("nil compressor changed the body: %q %q", body, enc)
+	}
+	if _, _, ok := c.stats(); ok {
+		t.Error("nil compressor stats ok = true")
+	}
+}
+
+func TestBufferedHTTPTransport_GzipBody(t *testing.T) {
+	rec := &compressRecorder{}
+	transport := newTestCompressedTransport(t, rec, config.KafkaRestConfig{Compression: "gzip", CompressionMinBytes: 0})
+
+	if err := transport.sendBatch("tp", makeTestRecords(50)); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+
+	encodings, bodies := rec.snapshot()
+	if len(encodings) != 1 || encodings[0] != "gzip" {
+		t.Fatalf("encodings = %v, want [gzip]", encodings)
+	}
+	var wrapper KafkaMessageWrapper2
+	if err := json.Unmarshal(bodies[0], &wrapper); err != nil {
+		t.Fatalf("decompressed body is not KafkaRest JSON: %v", err)
+	}
+	if len(wrapper.Records) != 50 {
+		t.Errorf("records = %d, want 50", len(wrapper.Records))
+	}
+
+	raw, wire, ok := transport.CompressionStats()
+	if !ok || raw != int64(len(bodies[0])) || wire <= 0 || wire >= raw {
+		t.Errorf("CompressionStats = (%d, %d, %v), want wire < raw = %d", raw, wire, ok, len(bodies[0]))
+	}
+}
+
+func TestBufferedHTTPTransport_ZstdBody(t *testing.T) {
+	rec := &compressRecorder{}
+	transport := newTestCompressedTransport(t, rec, config.KafkaRestConfig{Compression: "zstd"})
+
+	if err := transport.sendBatch("tp", makeTestRecords(20)); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+	encodings, bodies := rec.snapshot()
+	if len(encodings) != 1 || encodings[0] != "zstd" {
+		t.Fatalf("encodings = %v, want [zstd]", encodings)
+	}
+	if !bytes.Contains(bodies[0], []byte(`"raw-19"`)) {
+		t.Errorf("decompressed body = %s, want record raw-19", bodies[0])
+	}
+}
+
+func TestBufferedHTTPTransport_CompressionThreshold(t *testing.T) {
+	rec := &compressRecorder{}
+	transport := newTestCompressedTransport(t, rec, config.KafkaRestConfig{Compression: "gzip", CompressionMinBytes: 1 << 20})
+
+	if err := transport.sendBatch("tp", makeTestRecords(2)); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+	encodings, bodies := rec.snapshot()
+	if len(encodings) != 1 || encodings[0] != "" {
+		t.Fatalf("encodings = %q, want one uncompressed request below the threshold", encodings)
+	}
+	raw, wire, _ := transport.CompressionStats()
+	if raw != int64(len(bodies[0])) || wire != raw {
+		t.Errorf("CompressionStats = (%d, %d), want raw == wire == %d", raw, wire, len(bodies[0]))
+	}
+}
+
+func TestBufferedHTTPTransport_Compression415FallsBack(t *testing.T) {
+	rec := &compressRecorder{reject: true}
+	transport := newTestCompressedTransport(t, rec, config.KafkaRestConfig{Compression: "gzip"})
+
+	if err := transport.sendBatch("tp", makeTestRecords(5)); err != nil {
+		t.Fatalf("sendBatch must succeed via uncompressed retry, got: %v", err)
+	}
+	if err := transport.sendBatch("tp", makeTestRecords(5)); err != nil {
+		t.Fatalf("second sendBatch: %v", err)
+	}
+
+	encodings, bodies := rec.snapshot()
+	want := []string{"gzip", "", ""}
+	if len(encodings) != len(want) {
+		t.Fatalf("encodings = %q, want %q", encodings, want)
+	}
+	for i := range want {
+		if encodings[i] != want[i] {
+			t.Fatalf("encodings = %q, want %q (compression stays off after 415)", encodings, want)
+		}
+	}
+	if !bytes.Contains(bodies[1], []byte(`"raw-4"`)) {
+		t.Errorf("fallback body = %s, want the same batch uncompressed", bodies[1])
+	}
+	raw, wire, _ := transport.CompressionStats()
+	if raw == 0 || wire != raw {
+		t.Errorf("CompressionStats = (%d, %d), want raw == wire after fallback", raw, wire)
+	}
+}
+
+func TestHTTPTransport_Compression415FallsBack(t *testing.T) {
+	rec := &compressRecorder{reject: true}
+	server := httptest.NewServer(rec.handler(t))
+	defer server.Close()
+
+	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{}, config.KafkaRestConfig{Compression: "gzip"})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+
+	if err := transport.Deliver(context.Background(), "tp", makeTestRecords(3)); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	encodings, _ := rec.snapshot()
+	if len(encodings) != 2 || encodings[0] != "gzip" || encodings[1] != "" {
+		t.Errorf("encodings = %q, want [gzip \"\"]", encodings)
+	}
+}
+
+func TestKafkaSender_CompressionStats_Delegates(t *testing.T) {
+	rec := &compressRecorder{}
+	transport := newTestCompressedTransport(t, rec, config.KafkaRestConfig{Compression: "gzip"})
+	s := newTestKafkaSender(transport)
+
+	if _, _, ok := s.CompressionStats(); !ok {
+		t.Error("CompressionStats ok = false for a compressing transport")
+	}
+	if _, _, ok := newTestKafkaSender(&spyTransport{}).CompressionStats(); ok {
+		t.Error("CompressionStats ok = true for a transport without compression")
+	}
+}
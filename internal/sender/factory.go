@@ -3,6 +3,7 @@ package sender
 import (
 	"fmt"
 	"net"
+	"path/filepath"
 	"strings"
 
 	"resourceagent/internal/config"
@@ -47,6 +48,47 @@ func NewSender(cfg *config.Config, timeDiffFunc func() int64) (Sender, error) {
 		Str("sender_type", senderType).
 		Msg("Creating sender")
 
+	if senderType == "composite" {
+		return newCompositeFromConfig(cfg, timeDiffFunc)
+	}
+	return newSingleSender(senderType, cfg, timeDiffFunc)
+}
+
+// newCompositeFromConfig builds every configured destination. A destination
+// that cannot be created (e.g. Kafka broker down at startup) is retried in
+// the background by its composite worker while the others run. It is an
+// error only if none can be created, like a single sender failing.
+func newCompositeFromConfig(cfg *config.Config, timeDiffFunc func() int64) (Sender, error) {
+	log := logger.WithComponent("sender-factory")
+
+	create := func(dc config.DestinationConfig) (Sender, error) {
+		return newSingleSender(strings.ToLower(dc.Type), cfg, timeDiffFunc)
+	}
+	senders := make([]Sender, len(cfg.Destinations))
+	created := 0
+	for i, dc := range cfg.Destinations {
+		snd, err := create(dc)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("destination", dc.Name).
+				Str("type", dc.Type).
+				Msg("Failed to create composite destination, retrying in background")
+			continue
+		}
+		senders[i] = snd
+		created++
+	}
+	if created == 0 {
+		return nil, fmt.Errorf("composite sender: none of %d destinations could be created", len(cfg.Destinations))
+	}
+	return newCompositeSender(cfg.Destinations, senders, create), nil
+}
+
+// newSingleSender creates one kafkarest, kafka, otlp, influx, mqtt or file sender.
+func newSingleSender(senderType string, cfg *config.Config, timeDiffFunc func() int64) (Sender, error) {
+	log := logger.WithComponent("sender-factory")
+
 	switch senderType {
 	case "kafkarest":
 		topic := config.ResolveTopic(cfg.ResourceMonitorTopic, cfg.EqpInfo)
@@ -54,7 +96,11 @@ func NewSender(cfg *config.Config, timeDiffFunc func() int64) (Sender, error) {
 			Str("kafkarest_addr", cfg.KafkaRestAddress).
 			Str("topic", topic).
 			Msg("Creating KafkaRest sender")
-		transport, err := NewBufferedHTTPTransport(cfg.KafkaRestAddress, cfg.SOCKSProxy, cfg.Batch)
+		spool, err := openConfiguredSpool(cfg.Spool, "")
+		if err != nil {
+			return nil, err
+		}
+		transport, err := NewSpooledHTTPTransport(cfg.KafkaRestAddress, cfg.SOCKSProxy, cfg.Batch, cfg.KafkaRest, spool)
 		if err != nil {
 			return nil, err
 		}
@@ -74,9 +120,52 @@ func NewSender(cfg *config.Config, timeDiffFunc func() int64) (Sender, error) {
 			return nil, err
 		}
 		return NewKafkaSender(transport, topic, cfg.EqpInfo, timeDiffFunc, JSONRawFormatter{}), nil
+	case "otlp":
+		log.Info().
+			Str("endpoint", cfg.OTLP.Endpoint).
+			Msg("Creating OTLP sender")
+		spool, err := openConfiguredSpool(cfg.Spool, "otlp")
+		if err != nil {
+			return nil, err
+		}
+		return NewOTLPSender(cfg, spool, timeDiffFunc)
+	case "influx":
+		log.Info().
+			Str("url", cfg.Influx.URL).
+			Str("bucket", cfg.Influx.Bucket).
+			Msg("Creating InfluxDB sender")
+		spool, err := openConfiguredSpool(cfg.Spool, "influx")
+		if err != nil {
+			return nil, err
+		}
+		return NewInfluxSender(cfg, spool, timeDiffFunc)
+	case "mqtt":
+		log.Info().
+			Str("broker", cfg.MQTT.Broker).
+			Msg("Creating MQTT sender")
+		return NewMQTTSender(cfg, timeDiffFunc)
 	case "file":
-		return NewFileSender(cfg.File)
+		fs, err := NewFileSender(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		fs.SetEqpInfo(cfg.EqpInfo)
+		return fs, nil
 	default:
-		return nil, fmt.Errorf("unknown sender type: %s (supported: kafkarest, kafka, file)", senderType)
+		return nil, fmt.Errorf("unknown sender type: %s (supported: kafkarest, kafka, otlp, influx, mqtt, file, composite)", senderType)
+	}
+}
+
+// openConfiguredSpool opens the spool when enabled; nil otherwise. Senders
+// other than KafkaRest spool into a subdirectory of Spool.Dir, since their
+// records cannot be replayed to a different backend (the Spool ignores
+// subdirectories, and a composite may run several senders at once).
+func openConfiguredSpool(cfg config.SpoolConfig, subdir string) (*Spool, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if subdir != "" {
+		cfg.Dir = filepath.Join(cfg.Dir, subdir)
 	}
+	return OpenSpool(cfg)
 }
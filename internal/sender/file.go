@@ -25,6 +25,7 @@ type FileSender struct {
 	consoleCh   chan string // Async console output channel
 	consoleDone chan struct{}
 	format      string
+	influxTags  string    // InfluxEqpTags for the "influx" format
 	out         io.Writer // console output target (captured at construction; tests inject directly)
 	mu          sync.Mutex
 	closed      bool
@@ -39,8 +40,8 @@ func NewFileSender(cfg config.FileConfig) (*FileSender, error) {
 	if format == "" || format == "legacy" {
 		format = "grok"
 	}
-	if format != "json" && format != "grok" {
-		return nil, fmt.Errorf("unsupported file format %q: must be \"json\" or \"grok\"", format)
+	if format != "json" && format != "grok" && format != "influx" {
+		return nil, fmt.Errorf("unsupported file format %q: must be \"json\", \"grok\" or \"influx\"", format)
 	}
 
 	// Ensure the directory exists
@@ -92,10 +93,14 @@ func (s *FileSender) Send(ctx context.Context, data *collector.MetricData) error
 		return fmt.Errorf("sender is closed")
 	}
 
-	if s.format == "grok" {
+	switch s.format {
+	case "grok":
 		return s.sendGrok(data)
+	case "influx":
+		return s.sendInflux(data)
+	default:
+		return s.sendJSON(data)
 	}
-	return s.sendJSON(data)
 }
 
 // sendJSON writes metric data as ParsedDataList JSON (one line per EARSRow).
@@ -147,6 +152,28 @@ func (s *FileSender) sendGrok(data *collector.MetricData) error {
 	return nil
 }
 
+// sendInflux writes metric data as InfluxDB line protocol. Rows with
+// NaN/Inf values are skipped.
+func (s *FileSender) sendInflux(data *collector.MetricData) error {
+	rows := ConvertToEARSRows(data)
+	for _, row := range rows {
+		line, ok := row.ToInfluxLine(s.influxTags)
+		if !ok {
+			continue
+		}
+		if _, err := s.writer.Write(append([]byte(line), '\n')); err != nil {
+			return fmt.Errorf("failed to write to file: %w", err)
+		}
+		if s.console {
+			select {
+			case s.consoleCh <- line:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
 // SendBatch writes multiple metric data items.
 func (s *FileSender) SendBatch(ctx context.Context, data []*collector.MetricData) error {
 	for _, d := range data {
@@ -164,6 +191,14 @@ func (s *FileSender) SetConsole(enabled bool) {
 	s.console = enabled
 }
 
+// SetEqpInfo sets the equipment tags of the "influx" format. Without it
+// (file-only mode has no Redis EqpInfo) lines carry only row tags.
+func (s *FileSender) SetEqpInfo(info *config.EqpInfoConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.influxTags = InfluxEqpTags(info)
+}
+
 // drainConsole reads from consoleCh and prints to stdout in a separate goroutine.
 // This prevents stdout blocking (e.g., Windows cmd Quick Edit mode) from blocking file writes.
 func (s *FileSender) drainConsole() {
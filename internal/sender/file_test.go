@@ -91,6 +91,34 @@ func TestNewFileSender_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestFileSender_Influx_CPU(t *testing.T) {
+	cfg := tempFileConfig(t, "influx")
+	s, err := NewFileSender(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+	s.SetEqpInfo(&config.EqpInfoConfig{EqpID: "EQP001", Process: "PROCESS1"})
+
+	data := &collector.MetricData{
+		Type:      "CPU",
+		Timestamp: fileTestTimestamp,
+		Data:      collector.CPUData{UsagePercent: 45.5, CoreCount: 4},
+	}
+	if err := s.Send(context.Background(), data); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	lines := readGrokOutput(t, cfg.FilePath)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(lines))
+	}
+	expected := "cpu,eqpid=EQP001,process=PROCESS1,pid=0,proc=@system total_used_pct=45.5 1771929045123000000"
+	if lines[0] != expected {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", expected, lines[0])
+	}
+}
+
 // --- JSON format test ---
 
 func TestFileSender_Send_JSONFormat(t *testing.T) {
@@ -314,9 +342,10 @@ func TestFileSender_Grok_Network(t *testing.T) {
 	}
 
 	lines := readGrokOutput(t, cfg.FilePath)
-	// 2 (all_inbound/all_outbound) + 2 interfaces * 2 (recv_rate/sent_rate) = 6 lines
-	if len(lines) != 6 {
-		t.Fatalf("expected 6 lines, got %d", len(lines))
+	// 2 (all_inbound/all_outbound) + 2 interfaces * 7 (recv/sent rate,
+	// error/drop rates, oper_state) = 16 lines
+	if len(lines) != 16 {
+		t.Fatalf("expected 16 lines, got %d", len(lines))
 	}
 	if !strings.Contains(lines[0], "proc:@system,metric:all_inbound,value:42") {
 		t.Errorf("unexpected inbound line: %s", lines[0])
@@ -330,11 +359,11 @@ func TestFileSender_Grok_Network(t *testing.T) {
 	if !strings.Contains(lines[3], "proc:Ethernet,metric:sent_rate,value:250.3") {
 		t.Errorf("unexpected Ethernet sent_rate line: %s", lines[3])
 	}
-	if !strings.Contains(lines[4], "proc:Wi-Fi,metric:recv_rate,value:100") {
-		t.Errorf("unexpected Wi-Fi recv_rate line: %s", lines[4])
+	if !strings.Contains(lines[9], "proc:Wi-Fi,metric:recv_rate,value:100") {
+		t.Errorf("unexpected Wi-Fi recv_rate line: %s", lines[9])
 	}
-	if !strings.Contains(lines[5], "proc:Wi-Fi,metric:sent_rate,value:50") {
-		t.Errorf("unexpected Wi-Fi sent_rate line: %s", lines[5])
+	if !strings.Contains(lines[10], "proc:Wi-Fi,metric:sent_rate,value:50") {
+		t.Errorf("unexpected Wi-Fi sent_rate line: %s", lines[10])
 	}
 }
 
@@ -120,10 +120,16 @@ func ConvertToEARSRows(data *collector.MetricData) []EARSRow {
 		return convertDisk(data)
 	case "Network":
 		return convertNetwork(data)
+	case "Connections":
+		return convertConnections(data)
 	case "CPUProcess":
 		return convertCPUProcess(data)
 	case "MemoryProcess":
 		return convertMemoryProcess(data)
+	case "ProcessIO":
+		return convertProcessIO(data)
+	case "ProcessDetail":
+		return convertProcessDetail(data)
 	case "Temperature":
 		return convertTemperature(data)
 	case "GPU":
@@ -142,10 +148,22 @@ func ConvertToEARSRows(data *collector.MetricData) []EARSRow {
 		return convertUptime(data)
 	case "ProcessWatch":
 		return convertProcessWatch(data)
+	case "LogWatch":
+		return convertLogWatch(data)
+	case "PathWatch":
+		return convertPathWatch(data)
+	case "Probe":
+		return convertProbe(data)
+	case "Inventory":
+		return convertInventory(data)
+	case "Software":
+		return convertSoftware(data)
 	case "SelfMetrics":
 		return convertSelfMetrics(data)
 	default:
-		return nil
+		// Plugin collectors are named in Monitor.json, so they are
+		// recognised by their data rather than by type.
+		return convertPlugin(data)
 	}
 }
 
@@ -208,9 +226,23 @@ func convertDisk(data *collector.MetricData) []EARSRow {
 	if !ok {
 		return nil
 	}
-	rows := make([]EARSRow, 0, len(d.Partitions))
+	rows := make([]EARSRow, 0, len(d.Partitions)*2+len(d.Devices)*7)
 	for _, p := range d.Partitions {
 		rows = append(rows, systemRow(data.Timestamp, "disk", p.Mountpoint, p.UsagePercent))
+		if p.InodesTotal > 0 { // Windows (NTFS) has no inode counts
+			rows = append(rows, systemRow(data.Timestamp, "disk", p.Mountpoint+"_inode_pct", p.InodesPercent))
+		}
+	}
+	for _, dev := range d.Devices {
+		rows = append(rows,
+			systemRow(data.Timestamp, "disk", dev.Name+"_read_rate", dev.ReadBytesPerSec),
+			systemRow(data.Timestamp, "disk", dev.Name+"_write_rate", dev.WriteBytesPerSec),
+			systemRow(data.Timestamp, "disk", dev.Name+"_read_iops", dev.ReadOpsPerSec),
+			systemRow(data.Timestamp, "disk", dev.Name+"_write_iops", dev.WriteOpsPerSec),
+			systemRow(data.Timestamp, "disk", dev.Name+"_await", dev.AwaitMs),
+			systemRow(data.Timestamp, "disk", dev.Name+"_util_pct", dev.UtilPercent),
+			systemRow(data.Timestamp, "disk", dev.Name+"_queue_depth", float64(dev.QueueDepth)),
+		)
 	}
 	return rows
 }
@@ -241,6 +273,98 @@ func convertNetwork(data *collector.MetricData) []EARSRow {
 			Metric:    "sent_rate",
 			Value:     iface.BytesSentRate,
 		})
+		rows = append(rows,
+			networkRow(data.Timestamp, iface.Name, "errors_in_rate", iface.ErrorsInRate),
+			networkRow(data.Timestamp, iface.Name, "errors_out_rate", iface.ErrorsOutRate),
+			networkRow(data.Timestamp, iface.Name, "drops_in_rate", iface.DropsInRate),
+			networkRow(data.Timestamp, iface.Name, "drops_out_rate", iface.DropsOutRate),
+			networkRow(data.Timestamp, iface.Name, "oper_state", boolValue(iface.OperUp)),
+		)
+		if iface.SpeedMbps > 0 {
+			rows = append(rows, networkRow(data.Timestamp, iface.Name, "link_speed", iface.SpeedMbps))
+		}
+		if iface.Duplex != "" {
+			rows = append(rows, networkRow(data.Timestamp, iface.Name, "full_duplex", boolValue(iface.Duplex == "full")))
+		}
+		if iface.UtilPercent != nil {
+			rows = append(rows, networkRow(data.Timestamp, iface.Name, "util_pct", *iface.UtilPercent))
+		}
+		// MAC and addresses as value-1 rows with "<interface>@<string>" in
+		// EARS_PROCNAME, one row per address.
+		if iface.MAC != "" {
+			rows = append(rows, networkRow(data.Timestamp, iface.Name+"@"+iface.MAC, "mac", 1))
+		}
+		for _, addr := range iface.Addresses {
+			rows = append(rows, networkRow(data.Timestamp, iface.Name+"@"+addr, "address", 1))
+		}
+	}
+	// State changes: one row per event, value always 1. address_changed
+	// carries the new addresses like the address rows; the old ones are in
+	// the collector's NIC_ADDRESS_CHANGED log line.
+	for _, e := range d.Events {
+		proc := e.Interface
+		if e.Type == "address_changed" && e.New != "" {
+			proc += "@" + e.New
+		}
+		rows = append(rows, networkRow(data.Timestamp, proc, "event_"+e.Type, 1))
+	}
+	return rows
+}
+
+func networkRow(ts time.Time, iface, metric string, value float64) EARSRow {
+	return EARSRow{
+		Timestamp: ts,
+		Category:  "network",
+		PID:       0,
+		ProcName:  iface,
+		Metric:    metric,
+		Value:     value,
+	}
+}
+
+// convertConnections emits per-state counts and watched-port summaries as
+// @system rows, and one listen_{port} row per listening socket carrying the
+// owning process. Listener addresses are in the collector data only.
+func convertConnections(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.ConnectionsData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.States)+len(d.Listeners)+len(d.Ports)+len(d.Endpoints))
+	for _, s := range d.States {
+		rows = append(rows, systemRow(data.Timestamp, "connections", strings.ToLower(s.State), float64(s.Count)))
+	}
+	// Listeners are per address in the JSON; EARS gets one listen_<port>
+	// row per (port, pid) so a dual-stack bind (0.0.0.0 and ::) is one row.
+	type listenKey struct {
+		port uint32
+		pid  int32
+	}
+	seen := make(map[listenKey]bool, len(d.Listeners))
+	for _, l := range d.Listeners {
+		key := listenKey{port: l.Port, pid: l.PID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		name := l.ProcessName
+		if name == "" {
+			name = "@system"
+		}
+		rows = append(rows, EARSRow{
+			Timestamp: data.Timestamp,
+			Category:  "connections",
+			PID:       int(l.PID),
+			ProcName:  name,
+			Metric:    fmt.Sprintf("listen_%d", l.Port),
+			Value:     1,
+		})
+	}
+	for _, p := range d.Ports {
+		rows = append(rows, systemRow(data.Timestamp, "connections", fmt.Sprintf("port_%d_established", p.Port), float64(p.Established)))
+	}
+	for _, e := range d.Endpoints {
+		rows = append(rows, systemRow(data.Timestamp, "connections", fmt.Sprintf("port_%d_remote_%s", e.Port, e.RemoteIP), float64(e.Count)))
 	}
 	return rows
 }
@@ -291,6 +415,82 @@ func convertMemoryProcess(data *collector.MetricData) []EARSRow {
 	return rows
 }
 
+func convertProcessIO(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.ProcessIOData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.Processes)*4)
+	for _, p := range d.Processes {
+		for _, m := range []struct {
+			metric string
+			value  float64
+		}{
+			{"read_rate", p.ReadBytesPerSec},
+			{"write_rate", p.WriteBytesPerSec},
+			{"read_ops_rate", p.ReadOpsPerSec},
+			{"write_ops_rate", p.WriteOpsPerSec},
+		} {
+			rows = append(rows, EARSRow{
+				Timestamp: data.Timestamp,
+				Category:  "disk_io",
+				PID:       int(p.PID),
+				ProcName:  p.Name,
+				Metric:    m.metric,
+				Value:     m.value,
+			})
+		}
+	}
+	return rows
+}
+
+// convertProcessDetail emits the numeric fields per instance, plus Exe as a
+// value-1 row carrying the path in EARS_PROCNAME (like the inventory identity
+// rows) when it could be read. Cmdline is not emitted: it may hold
+// credentials and is useless as a sanitized series key.
+func convertProcessDetail(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.ProcessDetailData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.Processes)*8)
+	for _, p := range d.Processes {
+		row := func(metric string, value float64) EARSRow {
+			return EARSRow{
+				Timestamp: data.Timestamp,
+				Category:  "process_detail",
+				PID:       int(p.PID),
+				ProcName:  p.Name,
+				Metric:    metric,
+				Value:     value,
+			}
+		}
+		rows = append(rows, row("thread_count", float64(p.NumThreads)))
+		if p.NumHandles != nil {
+			rows = append(rows, row("handle_count", float64(*p.NumHandles)))
+		}
+		rows = append(rows,
+			row("child_count", float64(p.NumChildren)),
+			row("uptime", p.UptimeSeconds),
+			row("handle_growing", boolValue(p.HandleGrowing)),
+			row("thread_growing", boolValue(p.ThreadGrowing)),
+		)
+		if p.Exe != "" {
+			r := row("exe", 1)
+			r.ProcName = p.Exe
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func convertTemperature(data *collector.MetricData) []EARSRow {
 	d, ok := unmarshalData[collector.TemperatureData](data.Data)
 	if !ok {
@@ -408,7 +608,7 @@ func convertProcessWatch(data *collector.MetricData) []EARSRow {
 	if !ok {
 		return nil
 	}
-	rows := make([]EARSRow, 0, len(d.Statuses))
+	rows := make([]EARSRow, 0, len(d.Statuses)+len(d.Ports))
 	for _, s := range d.Statuses {
 		value := 0.0
 		if s.Running {
@@ -424,6 +624,18 @@ func convertProcessWatch(data *collector.MetricData) []EARSRow {
 			Value:     value,
 		})
 	}
+	// Port rules use the port spec ("tcp:5000") as proc; a listening port
+	// counts as "running".
+	for _, p := range d.Ports {
+		rows = append(rows, EARSRow{
+			Timestamp: data.Timestamp,
+			Category:  "process_watch",
+			PID:       int(p.PID),
+			ProcName:  p.Port,
+			Metric:    processWatchMetric(p.Type, p.Listening),
+			Value:     boolValue(p.Listening),
+		})
+	}
 	return rows
 }
 
@@ -438,6 +650,270 @@ func processWatchMetric(typ string, running bool) string {
 	return typ
 }
 
+// convertPathWatch emits the size and freshness rows of each watched path
+// (proc = configured path). As with processWatchMetric, a metric whose
+// threshold is crossed gets the _alert suffix: newest_age_sec past MaxAge,
+// total_size_bytes past QuotaMB. newest_age_sec is -1 when no file matched.
+func convertPathWatch(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.PathWatchData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.Paths)*4)
+	for _, p := range d.Paths {
+		age := -1.0
+		if p.NewestAgeSeconds != nil {
+			age = *p.NewestAgeSeconds
+		}
+		rows = append(rows,
+			pathWatchRow(data.Timestamp, p.Path, "file_count", float64(p.FileCount)),
+			pathWatchRow(data.Timestamp, p.Path, pathWatchMetric("total_size_bytes", p.OverQuota), float64(p.TotalBytes)),
+			pathWatchRow(data.Timestamp, p.Path, pathWatchMetric("newest_age_sec", p.Stale), age),
+			pathWatchRow(data.Timestamp, p.Path, "largest_file_bytes", float64(p.LargestBytes)),
+		)
+		if p.Truncated {
+			rows = append(rows, pathWatchRow(data.Timestamp, p.Path, "truncated", 1))
+		}
+	}
+	return rows
+}
+
+// pathWatchMetric returns metric with the _alert suffix when alert is set.
+func pathWatchMetric(metric string, alert bool) string {
+	if alert {
+		return metric + "_alert"
+	}
+	return metric
+}
+
+func pathWatchRow(ts time.Time, path, metric string, value float64) EARSRow {
+	return EARSRow{
+		Timestamp: ts,
+		Category:  "path_watch",
+		PID:       0,
+		ProcName:  path,
+		Metric:    metric,
+		Value:     value,
+	}
+}
+
+// convertProbe emits a success row per probe target (proc = target name),
+// renamed success_alert with value 0 when the target was unreachable, the
+// latency_ms of the attempt, and the status_code of HTTP probes that got a
+// response. The failure reason stays in the JSON error field.
+func convertProbe(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.ProbeData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.Probes)*3)
+	for _, p := range d.Probes {
+		metric, success := "success_alert", 0.0
+		if p.Success {
+			metric, success = "success", 1
+		}
+		rows = append(rows,
+			probeRow(data.Timestamp, p.Name, metric, success),
+			probeRow(data.Timestamp, p.Name, "latency_ms", p.LatencyMs),
+		)
+		if p.StatusCode != 0 {
+			rows = append(rows, probeRow(data.Timestamp, p.Name, "status_code", float64(p.StatusCode)))
+		}
+	}
+	return rows
+}
+
+func probeRow(ts time.Time, name, metric string, value float64) EARSRow {
+	return EARSRow{
+		Timestamp: ts,
+		Category:  "probe",
+		PID:       0,
+		ProcName:  name,
+		Metric:    metric,
+		Value:     value,
+	}
+}
+
+// convertPlugin emits one row per metric of an external plugin collector
+// (category "plugin"; proc = the metric's proc, or the plugin name). Data
+// that is not PluginData yields no rows, as for any unknown type.
+func convertPlugin(data *collector.MetricData) []EARSRow {
+	var d collector.PluginData
+	switch v := data.Data.(type) {
+	case collector.PluginData:
+		d = v
+	case *collector.PluginData:
+		d = *v
+	default:
+		return nil
+	}
+	rows := make([]EARSRow, 0, len(d.Metrics))
+	for _, m := range d.Metrics {
+		proc := m.Proc
+		if proc == "" {
+			proc = d.Plugin
+		}
+		rows = append(rows, EARSRow{
+			Timestamp: data.Timestamp,
+			Category:  "plugin",
+			PID:       m.PID,
+			ProcName:  proc,
+			Metric:    m.Name,
+			Value:     m.Value,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows
+}
+
+// convertLogWatch emits one row per watched file and pattern (proc = file
+// path, metric = pattern name, value = matching lines this interval), plus a
+// "rotated" row for files rotated or truncated since the last cycle, plus an
+// event_<pattern> row per file with the number of lines forwarded in
+// Events. The line text stays in the collector JSON: as a proc value it
+// would be mangled by SanitizeName and add a series per distinct line.
+func convertLogWatch(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.LogWatchData](data.Data)
+	if !ok {
+		return nil
+	}
+	var rows []EARSRow
+	for _, f := range d.Files {
+		for _, m := range f.Matches {
+			rows = append(rows, logWatchRow(data.Timestamp, f.Path, m.Pattern, float64(m.Count)))
+		}
+		if f.Rotated {
+			rows = append(rows, logWatchRow(data.Timestamp, f.Path, "rotated", 1))
+		}
+	}
+	type eventKey struct{ path, pattern string }
+	var keys []eventKey
+	forwarded := make(map[eventKey]int)
+	for _, e := range d.Events {
+		k := eventKey{e.Path, e.Pattern}
+		if forwarded[k] == 0 {
+			keys = append(keys, k)
+		}
+		forwarded[k]++
+	}
+	for _, k := range keys {
+		rows = append(rows, logWatchRow(data.Timestamp, k.path, "event_"+k.pattern, float64(forwarded[k])))
+	}
+	return rows
+}
+
+func logWatchRow(ts time.Time, path, metric string, value float64) EARSRow {
+	return EARSRow{
+		Timestamp: ts,
+		Category:  "logwatch",
+		PID:       0,
+		ProcName:  path,
+		Metric:    metric,
+		Value:     value,
+	}
+}
+
+// convertInventory emits the snapshot as numeric @system rows, identity
+// strings as value-1 rows carrying the string in EARS_PROCNAME, one row per
+// disk (size) and NIC, and one event_<component>_<type> row per change.
+func convertInventory(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.InventoryData](data.Data)
+	if !ok {
+		return nil
+	}
+	ts := data.Timestamp
+	var hash float64
+	if len(d.Hash) >= 8 {
+		if v, err := strconv.ParseUint(d.Hash[:8], 16, 32); err == nil {
+			hash = float64(v)
+		}
+	}
+	rows := []EARSRow{
+		systemRow(ts, "inventory", "cpu_cores", float64(d.CPU.Cores)),
+		systemRow(ts, "inventory", "cpu_logical_cores", float64(d.CPU.LogicalCores)),
+		systemRow(ts, "inventory", "memory_total_bytes", float64(d.MemoryTotalBytes)),
+		systemRow(ts, "inventory", "disk_count", float64(len(d.Disks))),
+		systemRow(ts, "inventory", "nic_count", float64(len(d.NICs))),
+		systemRow(ts, "inventory", "content_hash", hash),
+	}
+	info := []struct{ metric, value string }{
+		{"cpu_model", d.CPU.Model},
+		{"os", strings.TrimSpace(d.OS.Name + " " + d.OS.Version)},
+		{"kernel", d.OS.Kernel},
+		{"arch", d.OS.Arch},
+		{"bios", strings.TrimSpace(d.Board.BIOSVendor + " " + d.Board.BIOSVersion)},
+		{"board", strings.TrimSpace(d.Board.BoardVendor + " " + d.Board.BoardProduct)},
+		{"system", strings.TrimSpace(d.Board.SystemVendor + " " + d.Board.SystemProduct)},
+		{"agent_version", d.AgentVersion},
+	}
+	for _, i := range info {
+		if i.value != "" {
+			rows = append(rows, inventoryRow(ts, i.value, i.metric, 1))
+		}
+	}
+	for _, disk := range d.Disks {
+		proc := disk.Model
+		if disk.Serial != "" {
+			proc += "@" + disk.Serial
+		}
+		if proc == "" {
+			proc = disk.Name
+		}
+		rows = append(rows, inventoryRow(ts, proc, "disk_"+disk.Name, float64(disk.SizeBytes)))
+	}
+	for _, nic := range d.NICs {
+		rows = append(rows, inventoryRow(ts, nic.MAC, "nic_"+nic.Name, 1))
+	}
+	for _, ch := range d.Changes {
+		item := ch.New
+		if ch.Type == "removed" {
+			item = ch.Old
+		}
+		rows = append(rows, inventoryRow(ts, item, "event_"+ch.Component+"_"+ch.Type, 1))
+	}
+	return rows
+}
+
+func inventoryRow(ts time.Time, procName, metric string, value float64) EARSRow {
+	return EARSRow{
+		Timestamp: ts,
+		Category:  "inventory",
+		PID:       0,
+		ProcName:  procName,
+		Metric:    metric,
+		Value:     value,
+	}
+}
+
+// convertSoftware emits the package count and one event_<type> row per
+// changed package, with "name@version" in EARS_PROCNAME (the old version
+// for removals, the new one otherwise).
+func convertSoftware(data *collector.MetricData) []EARSRow {
+	d, ok := unmarshalData[collector.SoftwareData](data.Data)
+	if !ok {
+		return nil
+	}
+	rows := make([]EARSRow, 0, 1+len(d.Changes))
+	rows = append(rows, systemRow(data.Timestamp, "software", "package_count", float64(d.PackageCount)))
+	for _, ch := range d.Changes {
+		version := ch.NewVersion
+		if ch.Type == "removed" {
+			version = ch.OldVersion
+		}
+		rows = append(rows, EARSRow{
+			Timestamp: data.Timestamp,
+			Category:  "software",
+			PID:       0,
+			ProcName:  ch.Name + "@" + version,
+			Metric:    "event_" + ch.Type,
+			Value:     1,
+		})
+	}
+	return rows
+}
+
 func convertUptime(data *collector.MetricData) []EARSRow {
 	d, ok := unmarshalData[collector.UptimeData](data.Data)
 	if !ok {
@@ -471,7 +947,7 @@ func convertSelfMetrics(data *collector.MetricData) []EARSRow {
 	if !ok {
 		return nil
 	}
-	return []EARSRow{
+	rows := []EARSRow{
 		systemRow(data.Timestamp, "agent", "goroutine_count", float64(d.GoroutineCount)),
 		systemRow(data.Timestamp, "agent", "rss_bytes", float64(d.RSSBytes)),
 		systemRow(data.Timestamp, "agent", "heap_alloc_bytes", float64(d.HeapAllocBytes)),
@@ -480,4 +956,34 @@ func convertSelfMetrics(data *collector.MetricData) []EARSRow {
 		systemRow(data.Timestamp, "agent", "buffer_count", float64(d.BufferCount)),
 		systemRow(data.Timestamp, "agent", "buffer_dropped_total", float64(d.BufferDroppedTotal)),
 	}
+	if d.Spool != nil {
+		rows = append(rows,
+			systemRow(data.Timestamp, "agent", "spool_depth", float64(d.Spool.Depth)),
+			systemRow(data.Timestamp, "agent", "spool_bytes", float64(d.Spool.Bytes)),
+			systemRow(data.Timestamp, "agent", "spool_replayed_total", float64(d.Spool.ReplayedTotal)),
+			systemRow(data.Timestamp, "agent", "spool_expired_total", float64(d.Spool.ExpiredTotal)),
+		)
+	}
+	if d.Breaker != nil {
+		rows = append(rows,
+			systemRow(data.Timestamp, "agent", "breaker_state", float64(d.Breaker.State)),
+			systemRow(data.Timestamp, "agent", "breaker_open_total", float64(d.Breaker.OpenTotal)),
+			systemRow(data.Timestamp, "agent", "breaker_consecutive_failures", float64(d.Breaker.ConsecutiveFailures)),
+		)
+	}
+	if d.Delivery != nil {
+		rows = append(rows,
+			systemRow(data.Timestamp, "agent", "delivery_acked_total", float64(d.Delivery.AckedTotal)),
+			systemRow(data.Timestamp, "agent", "delivery_failed_total", float64(d.Delivery.FailedTotal)),
+			systemRow(data.Timestamp, "agent", "delivery_in_flight", float64(d.Delivery.InFlight)),
+		)
+	}
+	if d.Compression != nil {
+		rows = append(rows,
+			systemRow(data.Timestamp, "agent", "compress_raw_bytes_total", float64(d.Compression.RawBytesTotal)),
+			systemRow(data.Timestamp, "agent", "compress_wire_bytes_total", float64(d.Compression.WireBytesTotal)),
+			systemRow(data.Timestamp, "agent", "compress_ratio", d.Compression.Ratio),
+		)
+	}
+	return rows
 }
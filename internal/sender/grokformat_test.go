@@ -110,6 +110,37 @@ func TestConvertToEARSRows_Disk(t *testing.T) {
 	assertRow(t, rows[1], "disk", 0, "@system", "D:", 30.0)
 }
 
+func TestConvertToEARSRows_Disk_InodesAndDevices(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "Disk",
+		Timestamp: testTimestamp,
+		Data: collector.DiskData{
+			Partitions: []collector.DiskPartition{
+				{Mountpoint: "/", UsagePercent: 40.0, InodesTotal: 1000, InodesPercent: 12.5},
+				{Mountpoint: "C:", UsagePercent: 60.0},
+			},
+			Devices: []collector.DiskDevice{
+				{Name: "sda", ReadBytesPerSec: 4096, WriteBytesPerSec: 8192, ReadOpsPerSec: 1, WriteOpsPerSec: 2,
+					AwaitMs: 3.5, UtilPercent: 20, QueueDepth: 4},
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 10 {
+		t.Fatalf("expected 10 rows (2 usage + 1 inode + 7 device), got %d", len(rows))
+	}
+	assertRow(t, rows[0], "disk", 0, "@system", "/", 40.0)
+	assertRow(t, rows[1], "disk", 0, "@system", "/_inode_pct", 12.5)
+	assertRow(t, rows[2], "disk", 0, "@system", "C:", 60.0)
+	assertRow(t, rows[3], "disk", 0, "@system", "sda_read_rate", 4096)
+	assertRow(t, rows[4], "disk", 0, "@system", "sda_write_rate", 8192)
+	assertRow(t, rows[5], "disk", 0, "@system", "sda_read_iops", 1)
+	assertRow(t, rows[6], "disk", 0, "@system", "sda_write_iops", 2)
+	assertRow(t, rows[7], "disk", 0, "@system", "sda_await", 3.5)
+	assertRow(t, rows[8], "disk", 0, "@system", "sda_util_pct", 20)
+	assertRow(t, rows[9], "disk", 0, "@system", "sda_queue_depth", 4)
+}
+
 func TestConvertToEARSRows_Network(t *testing.T) {
 	data := &collector.MetricData{
 		Type:      "Network",
@@ -124,17 +155,61 @@ func TestConvertToEARSRows_Network(t *testing.T) {
 		},
 	}
 	rows := ConvertToEARSRows(data)
-	// 2 (all_inbound/all_outbound) + 2 interfaces * 2 (recv_rate/sent_rate) = 6 rows
-	if len(rows) != 6 {
-		t.Fatalf("expected 6 rows, got %d", len(rows))
+	// 2 (all_inbound/all_outbound) + 2 interfaces * 7 (recv/sent rate,
+	// error/drop rates, oper_state) = 16 rows
+	if len(rows) != 16 {
+		t.Fatalf("expected 16 rows, got %d", len(rows))
 	}
 	assertRow(t, rows[0], "network", 0, "@system", "all_inbound", 42)
 	assertRow(t, rows[1], "network", 0, "@system", "all_outbound", 38)
 	// Interface-level rate metrics with proc=NIC name
 	assertRow(t, rows[2], "network", 0, "Ethernet", "recv_rate", 500.5)
 	assertRow(t, rows[3], "network", 0, "Ethernet", "sent_rate", 250.3)
-	assertRow(t, rows[4], "network", 0, "Wi-Fi", "recv_rate", 100.0)
-	assertRow(t, rows[5], "network", 0, "Wi-Fi", "sent_rate", 50.0)
+	assertRow(t, rows[8], "network", 0, "Ethernet", "oper_state", 0)
+	assertRow(t, rows[9], "network", 0, "Wi-Fi", "recv_rate", 100.0)
+	assertRow(t, rows[10], "network", 0, "Wi-Fi", "sent_rate", 50.0)
+}
+
+func TestConvertToEARSRows_Network_LinkHealthAndEvents(t *testing.T) {
+	util := 12.5
+	data := &collector.MetricData{
+		Type:      "Network",
+		Timestamp: testTimestamp,
+		Data: collector.NetworkData{
+			Interfaces: []collector.NetworkInterface{
+				{Name: "eth0", ErrorsInRate: 0.5, ErrorsOutRate: 0, DropsInRate: 2, DropsOutRate: 0.1,
+					OperUp: true, SpeedMbps: 1000, Duplex: "half", UtilPercent: &util,
+					MAC: "00:1a:2b:3c:4d:5e", Addresses: []string{"10.0.0.6/24", "fe80::1/64"}},
+			},
+			Events: []collector.NetworkEvent{
+				{Interface: "eth1", Type: "link_down"},
+				{Interface: "eth0", Type: "address_changed", Old: "10.0.0.5/24", New: "10.0.0.6/24"},
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	// 2 system + 10 eth0 rows + mac + 2 addresses + 2 events
+	if len(rows) != 17 {
+		t.Fatalf("expected 17 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[4], "network", 0, "eth0", "errors_in_rate", 0.5)
+	assertRow(t, rows[5], "network", 0, "eth0", "errors_out_rate", 0)
+	assertRow(t, rows[6], "network", 0, "eth0", "drops_in_rate", 2)
+	assertRow(t, rows[7], "network", 0, "eth0", "drops_out_rate", 0.1)
+	assertRow(t, rows[8], "network", 0, "eth0", "oper_state", 1)
+	assertRow(t, rows[9], "network", 0, "eth0", "link_speed", 1000)
+	assertRow(t, rows[10], "network", 0, "eth0", "full_duplex", 0)
+	assertRow(t, rows[11], "network", 0, "eth0", "util_pct", 12.5)
+	assertRow(t, rows[12], "network", 0, "eth0@00:1a:2b:3c:4d:5e", "mac", 1)
+	assertRow(t, rows[13], "network", 0, "eth0@10.0.0.6/24", "address", 1)
+	assertRow(t, rows[14], "network", 0, "eth0@fe80::1/64", "address", 1)
+	assertRow(t, rows[15], "network", 0, "eth1", "event_link_down", 1)
+	assertRow(t, rows[16], "network", 0, "eth0@10.0.0.6/24", "event_address_changed", 1)
+
+	expected := "2026-02-24 10:30:45,123 category:network,pid:0,proc:eth0@10.0.0.6_24,metric:address,value:1"
+	if got := rows[13].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString() = %q, want %q", got, expected)
+	}
 }
 
 func TestConvertToEARSRows_CPUProcess(t *testing.T) {
@@ -174,6 +249,84 @@ func TestConvertToEARSRows_MemoryProcess(t *testing.T) {
 	assertRow(t, rows[1], "memory", 1234, "python.exe", "used_pct", 12.5)
 }
 
+func TestConvertToEARSRows_ProcessIO(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "ProcessIO",
+		Timestamp: testTimestamp,
+		Data: collector.ProcessIOData{
+			Processes: []collector.ProcessIO{
+				{PID: 4321, Name: "sqlservr.exe", ReadBytesPerSec: 2048, WriteBytesPerSec: 1024, ReadOpsPerSec: 12.5, WriteOpsPerSec: 3},
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "disk_io", 4321, "sqlservr.exe", "read_rate", 2048)
+	assertRow(t, rows[1], "disk_io", 4321, "sqlservr.exe", "write_rate", 1024)
+	assertRow(t, rows[2], "disk_io", 4321, "sqlservr.exe", "read_ops_rate", 12.5)
+	assertRow(t, rows[3], "disk_io", 4321, "sqlservr.exe", "write_ops_rate", 3)
+}
+
+func TestConvertToEARSRows_Connections(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "Connections",
+		Timestamp: testTimestamp,
+		Data: collector.ConnectionsData{
+			States: []collector.TCPStateCount{
+				{State: "ESTABLISHED", Count: 12},
+				{State: "CLOSE_WAIT", Count: 3},
+			},
+			Listeners: []collector.ListeningPort{
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 5000, PID: 812, ProcessName: "EqpHost.exe"},
+				{Protocol: "tcp", Address: "::", Port: 5000, PID: 812, ProcessName: "EqpHost.exe"},
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 135},
+			},
+			Ports:     []collector.PortSummary{{Port: 9092, Established: 4}},
+			Endpoints: []collector.EndpointCount{{Port: 9092, RemoteIP: "10.0.1.1", Count: 4}},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 6 {
+		t.Fatalf("expected 6 rows (dual-stack listener once), got %d", len(rows))
+	}
+	assertRow(t, rows[0], "connections", 0, "@system", "established", 12)
+	assertRow(t, rows[1], "connections", 0, "@system", "close_wait", 3)
+	assertRow(t, rows[2], "connections", 812, "EqpHost.exe", "listen_5000", 1)
+	assertRow(t, rows[3], "connections", 0, "@system", "listen_135", 1)
+	assertRow(t, rows[4], "connections", 0, "@system", "port_9092_established", 4)
+	assertRow(t, rows[5], "connections", 0, "@system", "port_9092_remote_10.0.1.1", 4)
+}
+
+func TestConvertToEARSRows_ProcessDetail(t *testing.T) {
+	handles := int32(5120)
+	data := &collector.MetricData{
+		Type:      "ProcessDetail",
+		Timestamp: testTimestamp,
+		Data: collector.ProcessDetailData{
+			Processes: []collector.ProcessDetail{
+				{PID: 812, Name: "EqpCtrl.exe", Exe: `C:\Eqp\EqpCtrl.exe`, Cmdline: `"C:\Eqp\EqpCtrl.exe" /line 3`,
+					NumThreads: 42, NumHandles: &handles,
+					NumChildren: 2, UptimeSeconds: 86400, HandleGrowing: true},
+				{PID: 900, Name: "EqpCtrl.exe", NumThreads: 8},
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 12 {
+		t.Fatalf("expected 12 rows (6 + exe, 5 without handle_count or exe; never cmdline), got %d", len(rows))
+	}
+	assertRow(t, rows[0], "process_detail", 812, "EqpCtrl.exe", "thread_count", 42)
+	assertRow(t, rows[1], "process_detail", 812, "EqpCtrl.exe", "handle_count", 5120)
+	assertRow(t, rows[2], "process_detail", 812, "EqpCtrl.exe", "child_count", 2)
+	assertRow(t, rows[3], "process_detail", 812, "EqpCtrl.exe", "uptime", 86400)
+	assertRow(t, rows[4], "process_detail", 812, "EqpCtrl.exe", "handle_growing", 1)
+	assertRow(t, rows[5], "process_detail", 812, "EqpCtrl.exe", "thread_growing", 0)
+	assertRow(t, rows[6], "process_detail", 812, `C:\Eqp\EqpCtrl.exe`, "exe", 1)
+	assertRow(t, rows[8], "process_detail", 900, "EqpCtrl.exe", "child_count", 0)
+}
+
 func TestConvertToEARSRows_Temperature(t *testing.T) {
 	data := &collector.MetricData{
 		Type:      "Temperature",
@@ -678,6 +831,31 @@ func TestConvertToEARSRows_ProcessWatch(t *testing.T) {
 	assertRow(t, rows[3], "process_watch", 0, "teamviewer.exe", "forbidden", 0)
 }
 
+func TestConvertToEARSRows_ProcessWatch_Ports(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "ProcessWatch",
+		Timestamp: testTimestamp,
+		Data: collector.ProcessWatchData{
+			Ports: []collector.PortWatchStatus{
+				{Port: "tcp:5000", Process: "EqpHost.exe", Listening: true, PID: 812, Owner: "EqpHost.exe", Type: "required"},
+				{Port: "tcp:5001", Process: "EqpHost.exe", Listening: false, PID: 900, Owner: "other.exe", Type: "required"},
+				{Port: "tcp:3389", Listening: true, PID: 1100, Owner: "svchost.exe", Type: "forbidden"},
+				{Port: "udp:161", Listening: false, Type: "forbidden"},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+
+	assertRow(t, rows[0], "process_watch", 812, "tcp:5000", "required", 1)
+	assertRow(t, rows[1], "process_watch", 900, "tcp:5001", "required_alert", 0)
+	assertRow(t, rows[2], "process_watch", 1100, "tcp:3389", "forbidden_alert", 1)
+	assertRow(t, rows[3], "process_watch", 0, "udp:161", "forbidden", 0)
+}
+
 func TestConvertToEARSRows_ProcessWatch_Empty(t *testing.T) {
 	data := &collector.MetricData{
 		Type:      "ProcessWatch",
@@ -806,6 +984,212 @@ func TestConvertToEARSRows_StorageHealth_Empty(t *testing.T) {
 	}
 }
 
+func TestConvertToEARSRows_LogWatch(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "LogWatch",
+		Timestamp: testTimestamp,
+		Data: collector.LogWatchData{
+			Files: []collector.LogWatchFile{
+				{Path: `D:\EQP\Log\eqp.log`, Matches: []collector.LogWatchMatch{{Pattern: "alarm", Count: 3}, {Pattern: "timeout", Count: 0}}},
+				{Path: "/var/log/eqp/host.log", Rotated: true, Matches: []collector.LogWatchMatch{{Pattern: "alarm", Count: 1}, {Pattern: "timeout", Count: 2}}},
+			},
+			Events: []collector.LogWatchEvent{
+				{Path: "/var/log/eqp/host.log", Pattern: "alarm", Line: "ALARM 101"},
+				{Path: "/var/log/eqp/host.log", Pattern: "timeout", Line: "timeout 설비 응답 없음"},
+				{Path: "/var/log/eqp/host.log", Pattern: "alarm", Line: "ALARM 102"},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 7 {
+		t.Fatalf("expected 7 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "logwatch", 0, `D:\EQP\Log\eqp.log`, "alarm", 3)
+	assertRow(t, rows[1], "logwatch", 0, `D:\EQP\Log\eqp.log`, "timeout", 0)
+	assertRow(t, rows[2], "logwatch", 0, "/var/log/eqp/host.log", "alarm", 1)
+	assertRow(t, rows[3], "logwatch", 0, "/var/log/eqp/host.log", "timeout", 2)
+	assertRow(t, rows[4], "logwatch", 0, "/var/log/eqp/host.log", "rotated", 1)
+	// Forwarded lines are counted per file and pattern; their text is not
+	// part of the row.
+	assertRow(t, rows[5], "logwatch", 0, "/var/log/eqp/host.log", "event_alarm", 2)
+	assertRow(t, rows[6], "logwatch", 0, "/var/log/eqp/host.log", "event_timeout", 1)
+
+	expected := "2026-02-24 10:30:45,123 category:logwatch,pid:0,proc:D:_EQP_Log_eqp.log,metric:alarm,value:3"
+	if got := rows[0].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+	expected = "2026-02-24 10:30:45,123 category:logwatch,pid:0,proc:_var_log_eqp_host.log,metric:event_alarm,value:2"
+	if got := rows[5].ToGrokString(); got != expected {
+		t.Errorf("event ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+}
+
+func TestConvertToEARSRows_PathWatch(t *testing.T) {
+	age := 2700.5
+	data := &collector.MetricData{
+		Type:      "PathWatch",
+		Timestamp: testTimestamp,
+		Data: collector.PathWatchData{
+			Paths: []collector.PathWatchStatus{
+				{Path: `D:\EQP\Result`, FileCount: 120, TotalBytes: 3 << 30, NewestAgeSeconds: &age, LargestBytes: 1048576, Stale: true, OverQuota: true},
+				{Path: "/data/images/*.bmp", Truncated: true},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 9 {
+		t.Fatalf("expected 9 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "path_watch", 0, `D:\EQP\Result`, "file_count", 120)
+	assertRow(t, rows[1], "path_watch", 0, `D:\EQP\Result`, "total_size_bytes_alert", 3<<30)
+	assertRow(t, rows[2], "path_watch", 0, `D:\EQP\Result`, "newest_age_sec_alert", 2700.5)
+	assertRow(t, rows[3], "path_watch", 0, `D:\EQP\Result`, "largest_file_bytes", 1048576)
+	assertRow(t, rows[4], "path_watch", 0, "/data/images/*.bmp", "file_count", 0)
+	assertRow(t, rows[5], "path_watch", 0, "/data/images/*.bmp", "total_size_bytes", 0)
+	assertRow(t, rows[6], "path_watch", 0, "/data/images/*.bmp", "newest_age_sec", -1)
+	assertRow(t, rows[8], "path_watch", 0, "/data/images/*.bmp", "truncated", 1)
+
+	expected := "2026-02-24 10:30:45,123 category:path_watch,pid:0,proc:D:_EQP_Result,metric:newest_age_sec_alert,value:2700.5"
+	if got := rows[2].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+}
+
+func TestConvertToEARSRows_Probe(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "Probe",
+		Timestamp: testTimestamp,
+		Data: collector.ProbeData{
+			Probes: []collector.ProbeResult{
+				{Name: "PLC", Type: "tcp", Address: "192.168.0.10:502", Success: true, LatencyMs: 1.25},
+				{Name: "MES", Type: "http", Address: "http://mes.local/health", Success: false, LatencyMs: 12.5, StatusCode: 503, Error: "HTTP 503"},
+				{Name: "kafkarest", Type: "kafkarest", Address: "10.0.0.1:8082", Proxy: true, Success: false, LatencyMs: 5000, Error: "timeout after 5s"},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 7 {
+		t.Fatalf("expected 7 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "probe", 0, "PLC", "success", 1)
+	assertRow(t, rows[1], "probe", 0, "PLC", "latency_ms", 1.25)
+	assertRow(t, rows[2], "probe", 0, "MES", "success_alert", 0)
+	assertRow(t, rows[3], "probe", 0, "MES", "latency_ms", 12.5)
+	assertRow(t, rows[4], "probe", 0, "MES", "status_code", 503)
+	assertRow(t, rows[5], "probe", 0, "kafkarest", "success_alert", 0)
+	assertRow(t, rows[6], "probe", 0, "kafkarest", "latency_ms", 5000)
+
+	expected := "2026-02-24 10:30:45,123 category:probe,pid:0,proc:MES,metric:status_code,value:503"
+	if got := rows[4].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+}
+
+func TestConvertToEARSRows_Plugin(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "VisionStats",
+		Timestamp: testTimestamp,
+		Data: collector.PluginData{
+			Plugin: "VisionStats",
+			Metrics: []collector.PluginMetric{
+				{Name: "wafer_count", Value: 1520},
+				{Name: "queue depth", Value: 3, Proc: "LoaderA", PID: 42},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "plugin", 0, "VisionStats", "wafer_count", 1520)
+	assertRow(t, rows[1], "plugin", 42, "LoaderA", "queue depth", 3)
+
+	expected := "2026-02-24 10:30:45,123 category:plugin,pid:42,proc:LoaderA,metric:queue_depth,value:3"
+	if got := rows[1].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+
+	empty := &collector.MetricData{Type: "VisionStats", Timestamp: testTimestamp, Data: collector.PluginData{Plugin: "VisionStats"}}
+	if rows := ConvertToEARSRows(empty); rows != nil {
+		t.Errorf("empty plugin response: got %d rows, want nil", len(rows))
+	}
+}
+
+func TestConvertToEARSRows_Inventory(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "Inventory",
+		Timestamp: testTimestamp,
+		Data: collector.InventoryData{
+			Hash:             "0000002aff",
+			AgentVersion:     "1.4.0",
+			CPU:              collector.InventoryCPU{Model: "Intel(R) Core(TM) i5-8500", Cores: 6, LogicalCores: 6},
+			MemoryTotalBytes: 17179869184,
+			Disks:            []collector.InventoryDisk{{Name: "PhysicalDrive0", Model: "Samsung SSD 860", Serial: "S3Z1NB0K", SizeBytes: 500107862016}},
+			NICs:             []collector.InventoryNIC{{Name: "Ethernet", MAC: "00:1a:2b:3c:4d:5e"}},
+			OS:               collector.InventoryOS{Name: "Microsoft Windows 10 Enterprise LTSC", Version: "10.0.17763", Kernel: "10.0.17763", Arch: "x86_64"},
+			Changes: []collector.InventoryChange{
+				{Component: "disk", Type: "removed", Old: "WDC WD5000@WX11"},
+				{Component: "disk", Type: "added", New: "Samsung SSD 860@S3Z1NB0K"},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 15 {
+		t.Fatalf("expected 15 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "inventory", 0, "@system", "cpu_cores", 6)
+	assertRow(t, rows[2], "inventory", 0, "@system", "memory_total_bytes", 17179869184)
+	assertRow(t, rows[3], "inventory", 0, "@system", "disk_count", 1)
+	assertRow(t, rows[5], "inventory", 0, "@system", "content_hash", 42)
+	assertRow(t, rows[6], "inventory", 0, "Intel(R) Core(TM) i5-8500", "cpu_model", 1)
+	assertRow(t, rows[7], "inventory", 0, "Microsoft Windows 10 Enterprise LTSC 10.0.17763", "os", 1)
+	assertRow(t, rows[10], "inventory", 0, "1.4.0", "agent_version", 1)
+	assertRow(t, rows[11], "inventory", 0, "Samsung SSD 860@S3Z1NB0K", "disk_PhysicalDrive0", 500107862016)
+	assertRow(t, rows[12], "inventory", 0, "00:1a:2b:3c:4d:5e", "nic_Ethernet", 1)
+	assertRow(t, rows[13], "inventory", 0, "WDC WD5000@WX11", "event_disk_removed", 1)
+	assertRow(t, rows[14], "inventory", 0, "Samsung SSD 860@S3Z1NB0K", "event_disk_added", 1)
+
+	expected := "2026-02-24 10:30:45,123 category:inventory,pid:0,proc:Samsung_SSD_860@S3Z1NB0K,metric:event_disk_added,value:1"
+	if got := rows[14].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+}
+
+func TestConvertToEARSRows_Software(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "Software",
+		Timestamp: testTimestamp,
+		Data: collector.SoftwareData{
+			Source:       "registry",
+			PackageCount: 142,
+			Changes: []collector.SoftwareChange{
+				{Name: "EQP Vendor Tool", Type: "upgraded", OldVersion: "2.1.0", NewVersion: "2.2.0"},
+				{Name: "TeamViewer", Type: "installed", NewVersion: "15.48"},
+				{Name: "WinPcap 4.1.3", Type: "removed", OldVersion: "4.1.0.2980"},
+			},
+		},
+	}
+
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[0], "software", 0, "@system", "package_count", 142)
+	assertRow(t, rows[1], "software", 0, "EQP Vendor Tool@2.2.0", "event_upgraded", 1)
+	assertRow(t, rows[2], "software", 0, "TeamViewer@15.48", "event_installed", 1)
+	assertRow(t, rows[3], "software", 0, "WinPcap 4.1.3@4.1.0.2980", "event_removed", 1)
+
+	expected := "2026-02-24 10:30:45,123 category:software,pid:0,proc:EQP_Vendor_Tool@2.2.0,metric:event_upgraded,value:1"
+	if got := rows[1].ToGrokString(); got != expected {
+		t.Errorf("ToGrokString:\n  got:  %q\n  want: %q", got, expected)
+	}
+}
+
 func TestConvertToEARSRows_SelfMetrics(t *testing.T) {
 	data := &collector.MetricData{
 		Type:      "SelfMetrics",
@@ -833,6 +1217,93 @@ func TestConvertToEARSRows_SelfMetrics(t *testing.T) {
 	assertRow(t, rows[6], "agent", 0, "@system", "buffer_dropped_total", 5)
 }
 
+func TestConvertToEARSRows_SelfMetrics_WithSpool(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "SelfMetrics",
+		Timestamp: testTimestamp,
+		Data: collector.SelfMetricsData{
+			GoroutineCount: 42,
+			Spool: &collector.SpoolMetrics{
+				Depth:         120,
+				Bytes:         65536,
+				ReplayedTotal: 300,
+				ExpiredTotal:  7,
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 11 {
+		t.Fatalf("expected 11 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[7], "agent", 0, "@system", "spool_depth", 120)
+	assertRow(t, rows[8], "agent", 0, "@system", "spool_bytes", 65536)
+	assertRow(t, rows[9], "agent", 0, "@system", "spool_replayed_total", 300)
+	assertRow(t, rows[10], "agent", 0, "@system", "spool_expired_total", 7)
+}
+
+func TestConvertToEARSRows_SelfMetrics_WithBreaker(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "SelfMetrics",
+		Timestamp: testTimestamp,
+		Data: collector.SelfMetricsData{
+			Breaker: &collector.BreakerMetrics{
+				State:               2,
+				OpenTotal:           3,
+				ConsecutiveFailures: 6,
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 10 {
+		t.Fatalf("expected 10 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[7], "agent", 0, "@system", "breaker_state", 2)
+	assertRow(t, rows[8], "agent", 0, "@system", "breaker_open_total", 3)
+	assertRow(t, rows[9], "agent", 0, "@system", "breaker_consecutive_failures", 6)
+}
+
+func TestConvertToEARSRows_SelfMetrics_WithDelivery(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "SelfMetrics",
+		Timestamp: testTimestamp,
+		Data: collector.SelfMetricsData{
+			Delivery: &collector.DeliveryMetrics{
+				AckedTotal:  900,
+				FailedTotal: 12,
+				InFlight:    40,
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 10 {
+		t.Fatalf("expected 10 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[7], "agent", 0, "@system", "delivery_acked_total", 900)
+	assertRow(t, rows[8], "agent", 0, "@system", "delivery_failed_total", 12)
+	assertRow(t, rows[9], "agent", 0, "@system", "delivery_in_flight", 40)
+}
+
+func TestConvertToEARSRows_SelfMetrics_WithCompression(t *testing.T) {
+	data := &collector.MetricData{
+		Type:      "SelfMetrics",
+		Timestamp: testTimestamp,
+		Data: collector.SelfMetricsData{
+			Compression: &collector.CompressionMetrics{
+				RawBytesTotal:  8000,
+				WireBytesTotal: 2000,
+				Ratio:          0.25,
+			},
+		},
+	}
+	rows := ConvertToEARSRows(data)
+	if len(rows) != 10 {
+		t.Fatalf("expected 10 rows, got %d", len(rows))
+	}
+	assertRow(t, rows[7], "agent", 0, "@system", "compress_raw_bytes_total", 8000)
+	assertRow(t, rows[8], "agent", 0, "@system", "compress_wire_bytes_total", 2000)
+	assertRow(t, rows[9], "agent", 0, "@system", "compress_ratio", 0.25)
+}
+
 // --- Benchmarks ---
 
 func BenchmarkToGrokString(b *testing.B) {
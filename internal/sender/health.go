@@ -0,0 +1,65 @@
+package sender
+
+import "sync"
+
+// Heartbeat reasons reported by DeliveryHealth.Check.
+const (
+	HealthReasonCircuitOpen     = "kafkarest_circuit_open"
+	HealthReasonDeliveryFailing = "kafka_delivery_failing"
+)
+
+// DeliveryHealth derives a heartbeat (status, reason) pair from a sender's
+// delivery observability: an open KafkaRest circuit breaker, or kafka acks
+// that have only been failing since the previous check. It is fed to
+// heartbeat.Sender.SetHealthCheck next to the scheduler staleness check.
+//
+// The failing state is sticky: a check interval with no delivery outcomes at
+// all (sarama still retrying) keeps the previous verdict instead of flapping
+// back to OK.
+type DeliveryHealth struct {
+	src any // sender; probed for BreakerStatsProvider / DeliveryStatsProvider
+
+	mu         sync.Mutex
+	prevAcked  int64
+	prevFailed int64
+	failing    bool
+}
+
+// NewDeliveryHealth creates a health check over snd. Senders that expose
+// neither breaker nor delivery stats always report OK.
+func NewDeliveryHealth(snd Sender) *DeliveryHealth {
+	return &DeliveryHealth{src: snd}
+}
+
+// Check returns ("OK", "") or ("WARN", reason).
+func (h *DeliveryHealth) Check() (status, reason string) {
+	if bp, ok := h.src.(BreakerStatsProvider); ok {
+		if state, _, _, attached := bp.BreakerStats(); attached && state == int64(breakerOpen) {
+			return "WARN", HealthReasonCircuitOpen
+		}
+	}
+
+	dp, ok := h.src.(DeliveryStatsProvider)
+	if !ok {
+		return "OK", ""
+	}
+	acked, failed, _, _, attached := dp.DeliveryStats()
+	if !attached {
+		return "OK", ""
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	dAcked, dFailed := acked-h.prevAcked, failed-h.prevFailed
+	h.prevAcked, h.prevFailed = acked, failed
+	switch {
+	case dAcked > 0:
+		h.failing = false
+	case dFailed > 0:
+		h.failing = true
+	}
+	if h.failing {
+		return "WARN", HealthReasonDeliveryFailing
+	}
+	return "OK", ""
+}
@@ -0,0 +1,68 @@
+package sender
+
+import (
+	"context"
+	"testing"
+
+	"resourceagent/internal/collector"
+)
+
+// fakeHealthSender exposes settable breaker / delivery stats.
+type fakeHealthSender struct {
+	breakerState  int64
+	acked, failed int64
+	deliveryStats bool
+}
+
+func (f *fakeHealthSender) Send(context.Context, *collector.MetricData) error        { return nil }
+func (f *fakeHealthSender) SendBatch(context.Context, []*collector.MetricData) error { return nil }
+func (f *fakeHealthSender) Close() error                                             { return nil }
+
+func (f *fakeHealthSender) BreakerStats() (int64, int64, int64, bool) {
+	return f.breakerState, 0, 0, true
+}
+
+func (f *fakeHealthSender) DeliveryStats() (int64, int64, int64, string, bool) {
+	return f.acked, f.failed, 0, "", f.deliveryStats
+}
+
+func assertHealth(t *testing.T, h *DeliveryHealth, wantStatus, wantReason string) {
+	t.Helper()
+	status, reason := h.Check()
+	if status != wantStatus || reason != wantReason {
+		t.Errorf("Check() = (%q, %q), want (%q, %q)", status, reason, wantStatus, wantReason)
+	}
+}
+
+func TestDeliveryHealth_CircuitOpen(t *testing.T) {
+	f := &fakeHealthSender{}
+	h := NewDeliveryHealth(f)
+	assertHealth(t, h, "OK", "")
+
+	f.breakerState = int64(breakerOpen)
+	assertHealth(t, h, "WARN", HealthReasonCircuitOpen)
+
+	f.breakerState = int64(breakerHalfOpen)
+	assertHealth(t, h, "OK", "")
+}
+
+func TestDeliveryHealth_FailingIsSticky(t *testing.T) {
+	f := &fakeHealthSender{deliveryStats: true}
+	h := NewDeliveryHealth(f)
+
+	f.acked = 10
+	assertHealth(t, h, "OK", "")
+
+	f.failed = 3
+	assertHealth(t, h, "WARN", HealthReasonDeliveryFailing)
+	// No outcomes at all since the last check: keep the verdict.
+	assertHealth(t, h, "WARN", HealthReasonDeliveryFailing)
+
+	f.acked = 11
+	assertHealth(t, h, "OK", "")
+}
+
+func TestDeliveryHealth_NoStats(t *testing.T) {
+	h := NewDeliveryHealth(&FileSender{})
+	assertHealth(t, h, "OK", "")
+}
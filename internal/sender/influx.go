@@ -0,0 +1,179 @@
+package sender
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+const (
+	influxWritePath   = "/api/v2/write"
+	influxContentType = "text/plain; charset=utf-8"
+)
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	influxTagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+)
+
+// InfluxEqpTags renders the equipment tags shared by every line of an agent,
+// as a ",key=value..." suffix for ToInfluxLine. Empty values are omitted
+// (line protocol does not allow them); nil info yields "".
+func InfluxEqpTags(info *config.EqpInfoConfig) string {
+	if info == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, kv := range [][2]string{
+		{"eqpid", info.EqpID},
+		{"line", info.Line},
+		{"model", info.EqpModel},
+		{"process", info.Process},
+	} {
+		if kv[1] == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(kv[0])
+		b.WriteByte('=')
+		b.WriteString(influxTagEscaper.Replace(kv[1]))
+	}
+	return b.String()
+}
+
+// ToInfluxLine returns the row as one InfluxDB line-protocol line:
+//
+//	cpu,eqpid=EQP001,line=L1,model=M1,process=P1,pid=0,proc=@system total_used_pct=12.5 1767225600000000000
+//
+// The category is the measurement, the metric the field key, and the
+// timestamp is in nanoseconds. eqpTags comes from InfluxEqpTags. ok is false
+// for NaN/Inf values, which line protocol cannot represent.
+func (r EARSRow) ToInfluxLine(eqpTags string) (line string, ok bool) {
+	if math.IsNaN(r.Value) || math.IsInf(r.Value, 0) {
+		return "", false
+	}
+	var b strings.Builder
+	b.Grow(160)
+	b.WriteString(influxMeasurementEscaper.Replace(r.Category))
+	b.WriteString(eqpTags)
+	b.WriteString(",pid=")
+	b.WriteString(strconv.Itoa(r.PID))
+	if proc := sanitizeName(r.ProcName); proc != "" {
+		b.WriteString(",proc=")
+		b.WriteString(influxTagEscaper.Replace(proc))
+	}
+	b.WriteByte(' ')
+	b.WriteString(influxTagEscaper.Replace(sanitizeName(r.Metric)))
+	b.WriteByte('=')
+	b.WriteString(formatValue(r.Value))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(r.Timestamp.UnixNano(), 10))
+	return b.String(), true
+}
+
+// InfluxRawFormatter produces InfluxDB line protocol. Tags is the
+// InfluxEqpTags suffix added to every line.
+type InfluxRawFormatter struct {
+	Tags string
+}
+
+func (f InfluxRawFormatter) FormatRaw(row EARSRow, _ string) (string, error) {
+	line, ok := row.ToInfluxLine(f.Tags)
+	if !ok {
+		return "", ErrSkipRow
+	}
+	return line, nil
+}
+
+// InfluxSender writes line protocol to an InfluxDB v2 /api/v2/write endpoint
+// (SenderType "influx"). Like OTLPSender it is a KafkaSender with its own
+// formatter and BufferedHTTPTransport encoder, so batching, requeue,
+// circuit breaker and spool are shared with KafkaRest.
+type InfluxSender struct {
+	*KafkaSender
+}
+
+// NewInfluxSender creates an InfluxDB sender for cfg.Influx, reusing
+// cfg.Batch and cfg.SOCKSProxy. spool may be nil.
+func NewInfluxSender(cfg *config.Config, spool *Spool, timeDiffFunc func() int64) (*InfluxSender, error) {
+	writeURL, err := influxWriteURL(cfg.Influx)
+	if err != nil {
+		return nil, err
+	}
+	enc := influxEncoder{token: cfg.Influx.Token}
+	transport, err := newEncodedHTTPTransport(writeURL, cfg.SOCKSProxy, cfg.Batch, spool, enc, nil)
+	if err != nil {
+		return nil, err
+	}
+	formatter := InfluxRawFormatter{Tags: InfluxEqpTags(cfg.EqpInfo)}
+	return &InfluxSender{
+		KafkaSender: NewKafkaSender(transport, "", cfg.EqpInfo, timeDiffFunc, formatter),
+	}, nil
+}
+
+// influxWriteURL builds the v2 write URL with org, bucket and ns precision.
+func influxWriteURL(cfg config.InfluxConfig) (string, error) {
+	u, err := url.Parse(ensureHTTPScheme(cfg.URL))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid InfluxDB URL %q", cfg.URL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + influxWritePath
+	q := u.Query()
+	if cfg.Org != "" {
+		q.Set("org", cfg.Org)
+	}
+	q.Set("bucket", cfg.Bucket)
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// influxEncoder joins buffered lines into one write body. Timestamps are
+// shifted by KafkaValue.Diff (local - server ms) onto server time, matching
+// OTLPSender.
+type influxEncoder struct {
+	token string
+}
+
+func (influxEncoder) Name() string { return "InfluxDB" }
+
+func (influxEncoder) URL(baseURL, _ string) string { return baseURL }
+
+func (e influxEncoder) SetHeaders(h http.Header) {
+	h.Set("Content-Type", influxContentType)
+	if e.token != "" {
+		h.Set("Authorization", "Token "+e.token)
+	}
+}
+
+func (influxEncoder) Encode(records []KafkaRecord) ([]byte, error) {
+	var b strings.Builder
+	for _, rec := range records {
+		b.WriteString(shiftInfluxTimestamp(rec.Value.Raw, rec.Value.Diff))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// shiftInfluxTimestamp subtracts diffMs from the trailing timestamp of a
+// line. Lines without a parsable timestamp are returned unchanged.
+func shiftInfluxTimestamp(line string, diffMs int64) string {
+	if diffMs == 0 {
+		return line
+	}
+	i := strings.LastIndexByte(line, ' ')
+	if i < 0 {
+		return line
+	}
+	ts, err := strconv.ParseInt(line[i+1:], 10, 64)
+	if err != nil {
+		return line
+	}
+	return line[:i+1] + strconv.FormatInt(ts-diffMs*int64(time.Millisecond), 10)
+}
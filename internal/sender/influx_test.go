@@ -0,0 +1,141 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+var influxTestTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestInfluxEqpTags(t *testing.T) {
+	got := InfluxEqpTags(newTestEqpInfo())
+	want := ",eqpid=EQP001,line=LINE1,model=MODEL1,process=PROCESS1"
+	if got != want {
+		t.Errorf("InfluxEqpTags = %q, want %q", got, want)
+	}
+
+	info := &config.EqpInfoConfig{EqpID: "EQP 1,A=B"}
+	if got := InfluxEqpTags(info); got != `,eqpid=EQP\ 1\,A\=B` {
+		t.Errorf("escaped/empty-omitted tags = %q", got)
+	}
+	if got := InfluxEqpTags(nil); got != "" {
+		t.Errorf("nil info must yield no tags, got %q", got)
+	}
+}
+
+func TestEARSRow_ToInfluxLine(t *testing.T) {
+	row := EARSRow{Timestamp: influxTestTime, Category: "cpu", PID: 1234, ProcName: "python.exe", Metric: "used_pct", Value: 3.5}
+	line, ok := row.ToInfluxLine(",eqpid=EQP001")
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := "cpu,eqpid=EQP001,pid=1234,proc=python.exe used_pct=3.5 1767225600000000000"
+	if line != want {
+		t.Errorf("line:\n  got  %s\n  want %s", line, want)
+	}
+
+	row.Value = math.NaN()
+	if _, ok := row.ToInfluxLine(""); ok {
+		t.Error("NaN must not be representable")
+	}
+	row.Value = math.Inf(1)
+	if _, ok := row.ToInfluxLine(""); ok {
+		t.Error("Inf must not be representable")
+	}
+}
+
+func TestInfluxRawFormatter_SkipsNaN(t *testing.T) {
+	_, err := InfluxRawFormatter{}.FormatRaw(EARSRow{Category: "cpu", Metric: "x", Value: math.NaN()}, "")
+	if !errors.Is(err, ErrSkipRow) {
+		t.Errorf("expected ErrSkipRow, got %v", err)
+	}
+}
+
+func TestInfluxWriteURL(t *testing.T) {
+	got, err := influxWriteURL(config.InfluxConfig{URL: "http://influx:8086/", Org: "fab", Bucket: "resource"})
+	if err != nil {
+		t.Fatalf("influxWriteURL: %v", err)
+	}
+	want := "http://influx:8086/api/v2/write?bucket=resource&org=fab&precision=ns"
+	if got != want {
+		t.Errorf("influxWriteURL = %q, want %q", got, want)
+	}
+}
+
+func TestShiftInfluxTimestamp(t *testing.T) {
+	line := "cpu,pid=0 v=1 1767225600000000000"
+	if got := shiftInfluxTimestamp(line, 1500); got != "cpu,pid=0 v=1 1767225598500000000" {
+		t.Errorf("shift = %q", got)
+	}
+	if got := shiftInfluxTimestamp(line, 0); got != line {
+		t.Errorf("zero diff must not change the line, got %q", got)
+	}
+	if got := shiftInfluxTimestamp("garbage", 10); got != "garbage" {
+		t.Errorf("unparsable line must be unchanged, got %q", got)
+	}
+}
+
+func TestInfluxSender_WritesLineProtocol(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		path  string
+		auth  string
+		ctype string
+		body  string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		path = r.URL.Path + "?" + r.URL.RawQuery
+		auth = r.Header.Get("Authorization")
+		ctype = r.Header.Get("Content-Type")
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Batch = newTestBatchConfig()
+	cfg.Influx = config.InfluxConfig{URL: server.URL, Org: "fab", Bucket: "resource", Token: "tok"}
+	cfg.EqpInfo = newTestEqpInfo()
+
+	s, err := NewInfluxSender(cfg, nil, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewInfluxSender: %v", err)
+	}
+	data := newCPUData()
+	data.Timestamp = influxTestTime
+	if err := s.Send(context.Background(), data); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/api/v2/write?bucket=resource&org=fab&precision=ns" {
+		t.Errorf("request = %q", path)
+	}
+	if auth != "Token tok" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if !strings.HasPrefix(ctype, "text/plain") {
+		t.Errorf("Content-Type = %q", ctype)
+	}
+	want := "cpu,eqpid=EQP001,line=LINE1,model=MODEL1,process=PROCESS1,pid=0,proc=@system total_used_pct=50 1767225600000000000\n"
+	if !strings.HasPrefix(body, want) {
+		t.Errorf("body:\n  got  %q\n  want prefix %q", body, want)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/IBM/sarama"
 	"github.com/xdg-go/scram"
@@ -63,8 +64,54 @@ type KafkaTransport interface {
 }
 
 // SaramaTransport implements KafkaTransport using the sarama async producer.
+//
+// Every message is tracked until the producer acks or fails it, so the
+// transport can report delivery health (DeliveryStats). In the default
+// async mode Deliver returns once records are enqueued; with
+// Kafka.SyncDelivery it waits for all of them to be acked (or for ctx).
 type SaramaTransport struct {
-	producer sarama.AsyncProducer
+	producer     sarama.AsyncProducer
+	syncDelivery bool
+
+	handlers  sync.WaitGroup // handleSuccesses + handleErrors
+	closeOnce sync.Once
+	closing   atomic.Bool
+	closeErr  error
+
+	// Lock-free observability (see DeliveryStats).
+	ackedTotal  atomic.Int64
+	failedTotal atomic.Int64
+	inFlight    atomic.Int64
+
+	errMu     sync.Mutex
+	lastErr   string
+	closeErrs sarama.ProducerErrors // failures reported while Close flushes
+}
+
+// deliveryWaiter collects the acks of one synchronous Deliver call. It rides
+// along in ProducerMessage.Metadata.
+type deliveryWaiter struct {
+	remaining atomic.Int64
+	failed    atomic.Int64
+	done      chan struct{}
+	errOnce   sync.Once
+	err       error
+}
+
+func newDeliveryWaiter(n int) *deliveryWaiter {
+	w := &deliveryWaiter{done: make(chan struct{})}
+	w.remaining.Store(int64(n))
+	return w
+}
+
+func (w *deliveryWaiter) ack(err error) {
+	if err != nil {
+		w.failed.Add(1)
+		w.errOnce.Do(func() { w.err = err })
+	}
+	if w.remaining.Add(-1) == 0 {
+		close(w.done)
+	}
 }
 
 // NewSaramaTransport creates a new sarama-based Kafka transport.
@@ -72,7 +119,7 @@ func NewSaramaTransport(brokers []string, cfg config.KafkaConfig, batchCfg confi
 	saramaConfig := sarama.NewConfig()
 
 	// Producer settings
-	saramaConfig.Producer.Return.Successes = false
+	saramaConfig.Producer.Return.Successes = true // ack tracking
 	saramaConfig.Producer.Return.Errors = true
 	saramaConfig.Producer.Retry.Max = batchCfg.MaxRetries
 	saramaConfig.Producer.Retry.Backoff = batchCfg.RetryBackoff
@@ -162,14 +209,28 @@ func NewSaramaTransport(brokers []string, cfg config.KafkaConfig, batchCfg confi
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	t := &SaramaTransport{producer: producer}
-	go t.handleErrors()
+	return newSaramaTransport(producer, cfg.SyncDelivery), nil
+}
 
-	return t, nil
+// newSaramaTransport wraps an existing producer (a mock in tests). The
+// producer must be configured with Return.Successes and Return.Errors.
+func newSaramaTransport(producer sarama.AsyncProducer, syncDelivery bool) *SaramaTransport {
+	t := &SaramaTransport{producer: producer, syncDelivery: syncDelivery}
+	t.handlers.Add(2)
+	go t.handleSuccesses()
+	go t.handleErrors()
+	return t
 }
 
-// Deliver sends records to Kafka via the sarama async producer.
+// Deliver sends records to Kafka via the sarama async producer. In sync mode
+// it blocks until every record is acked and returns an error if any failed;
+// ctx cancellation stops the wait but not the delivery already in progress.
 func (t *SaramaTransport) Deliver(ctx context.Context, topic string, records []KafkaRecord) error {
+	var waiter *deliveryWaiter
+	if t.syncDelivery && len(records) > 0 {
+		waiter = newDeliveryWaiter(len(records))
+	}
+
 	for _, rec := range records {
 		valueBytes, err := json.Marshal(rec.Value)
 		if err != nil {
@@ -181,26 +242,95 @@ func (t *SaramaTransport) Deliver(ctx context.Context, topic string, records []K
 			Value:     sarama.ByteEncoder(valueBytes),
 			Timestamp: rec.Timestamp,
 		}
+		if waiter != nil {
+			msg.Metadata = waiter
+		}
+		t.inFlight.Add(1)
 		select {
 		case t.producer.Input() <- msg:
 		case <-ctx.Done():
+			t.inFlight.Add(-1)
 			return ctx.Err()
 		}
 	}
+
+	if waiter == nil {
+		return nil
+	}
+	select {
+	case <-waiter.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if waiter.err != nil {
+		return fmt.Errorf("kafka delivery failed for %d/%d records: %w",
+			waiter.failed.Load(), len(records), waiter.err)
+	}
 	return nil
 }
 
-// Close shuts down the sarama producer.
+// Close flushes buffered messages and waits until every one of them has been
+// acked or failed, so the counters are final once Close returns. Like
+// sarama's producer.Close, it returns the failures seen while flushing as
+// sarama.ProducerErrors.
 func (t *SaramaTransport) Close() error {
-	return t.producer.Close()
+	t.closeOnce.Do(func() {
+		t.closing.Store(true)
+		t.producer.AsyncClose()
+		t.handlers.Wait()
+
+		t.errMu.Lock()
+		if len(t.closeErrs) > 0 {
+			t.closeErr = t.closeErrs
+		}
+		t.errMu.Unlock()
+	})
+	return t.closeErr
+}
+
+// DeliveryStats returns cumulative acked/failed record counts, the number of
+// records enqueued but not yet acked, and the most recent producer error
+// ("" if none). ok is always true for this transport.
+// Implements sender.DeliveryStatsProvider.
+func (t *SaramaTransport) DeliveryStats() (acked, failed, inFlight int64, lastErr string, ok bool) {
+	t.errMu.Lock()
+	lastErr = t.lastErr
+	t.errMu.Unlock()
+	return t.ackedTotal.Load(), t.failedTotal.Load(), t.inFlight.Load(), lastErr, true
+}
+
+func (t *SaramaTransport) handleSuccesses() {
+	defer t.handlers.Done()
+	for msg := range t.producer.Successes() {
+		t.inFlight.Add(-1)
+		t.ackedTotal.Add(1)
+		if w, ok := msg.Metadata.(*deliveryWaiter); ok {
+			w.ack(nil)
+		}
+	}
 }
 
 func (t *SaramaTransport) handleErrors() {
+	defer t.handlers.Done()
 	log := logger.WithComponent("kafka-transport")
 	for err := range t.producer.Errors() {
+		t.inFlight.Add(-1)
+		newTotal := t.failedTotal.Add(1)
+
+		t.errMu.Lock()
+		t.lastErr = err.Err.Error()
+		if t.closing.Load() {
+			t.closeErrs = append(t.closeErrs, err)
+		}
+		t.errMu.Unlock()
+
+		if w, ok := err.Msg.Metadata.(*deliveryWaiter); ok {
+			w.ack(err.Err)
+		}
 		log.Error().Err(err.Err).
 			Str("topic", err.Msg.Topic).
 			Interface("key", err.Msg.Key).
+			Int64("failed_total", newTotal).
 			Msg("Failed to send message to Kafka")
 	}
 }
@@ -304,6 +434,68 @@ func (s *KafkaSender) BufferStats() (count, dropped, hwm int64) {
 	return 0, 0, 0
 }
 
+// SpoolStats returns the transport's spool observability. ok is false when
+// the transport has no spool attached. Implements sender.SpoolStatsProvider.
+func (s *KafkaSender) SpoolStats() (depth, bytes, replayed, expired int64, ok bool) {
+	s.mu.Lock()
+	t := s.transport
+	s.mu.Unlock()
+	if ssp, isProvider := t.(SpoolStatsProvider); isProvider {
+		return ssp.SpoolStats()
+	}
+	return 0, 0, 0, 0, false
+}
+
+// BreakerStats returns the transport's circuit breaker observability. ok is
+// false when the transport has no breaker. Implements sender.BreakerStatsProvider.
+func (s *KafkaSender) BreakerStats() (state, opens, failures int64, ok bool) {
+	s.mu.Lock()
+	t := s.transport
+	s.mu.Unlock()
+	if bsp, isProvider := t.(BreakerStatsProvider); isProvider {
+		return bsp.BreakerStats()
+	}
+	return 0, 0, 0, false
+}
+
+// CompressionStats returns the transport's request compression counters.
+// ok is false when the transport does not compress. Implements
+// sender.CompressionStatsProvider.
+func (s *KafkaSender) CompressionStats() (rawBytes, wireBytes int64, ok bool) {
+	s.mu.Lock()
+	t := s.transport
+	s.mu.Unlock()
+	if csp, isProvider := t.(CompressionStatsProvider); isProvider {
+		return csp.CompressionStats()
+	}
+	return 0, 0, false
+}
+
+// DeliveryStats returns the transport's ack tracking counters. ok is false
+// when the transport does not track acks. Implements sender.DeliveryStatsProvider.
+func (s *KafkaSender) DeliveryStats() (acked, failed, inFlight int64, lastErr string, ok bool) {
+	s.mu.Lock()
+	t := s.transport
+	s.mu.Unlock()
+	if dsp, isProvider := t.(DeliveryStatsProvider); isProvider {
+		return dsp.DeliveryStats()
+	}
+	return 0, 0, 0, "", false
+}
+
+// Spool returns the on-disk spool used by the current transport, or nil.
+// main.go passes it to replacement transports so a swap keeps draining the
+// same spool directory.
+func (s *KafkaSender) Spool() *Spool {
+	s.mu.Lock()
+	t := s.transport
+	s.mu.Unlock()
+	if st, ok := t.(interface{ Spool() *Spool }); ok {
+		return st.Spool()
+	}
+	return nil
+}
+
 // Close closes the Kafka sender and its transport.
 func (s *KafkaSender) Close() error {
 	s.mu.Lock()
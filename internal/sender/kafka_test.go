@@ -2,11 +2,16 @@ package sender
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+
 	"resourceagent/internal/config"
 )
 
@@ -145,3 +150,157 @@ func TestSwapTransport_ConcurrentSendAndSwap(t *testing.T) {
 		t.Errorf("expected 0 send errors, got %d", sendErrors)
 	}
 }
+
+// --- SaramaTransport ack tracking ---
+
+func newMockProducerConfig() *sarama.Config {
+	cfg := mocks.NewTestConfig()
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Return.Errors = true
+	return cfg
+}
+
+// stallingProducer accepts messages but never acks them. AsyncClose fails
+// the buffered messages with flushErr, if set.
+type stallingProducer struct {
+	sarama.AsyncProducer
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+	flushErr  error
+}
+
+func newStallingProducer() *stallingProducer {
+	return &stallingProducer{
+		input:     make(chan *sarama.ProducerMessage, 16),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError),
+	}
+}
+
+func (p *stallingProducer) Input() chan<- *sarama.ProducerMessage     { return p.input }
+func (p *stallingProducer) Successes() <-chan *sarama.ProducerMessage { return p.successes }
+func (p *stallingProducer) Errors() <-chan *sarama.ProducerError      { return p.errors }
+func (p *stallingProducer) AsyncClose() {
+	if p.flushErr != nil {
+		close(p.input)
+		for msg := range p.input {
+			p.errors <- &sarama.ProducerError{Msg: msg, Err: p.flushErr}
+		}
+	}
+	close(p.successes)
+	close(p.errors)
+}
+
+func TestSaramaTransport_AsyncTracksAcksAndErrors(t *testing.T) {
+	mp := mocks.NewAsyncProducer(t, newMockProducerConfig())
+	mp.ExpectInputAndSucceed()
+	mp.ExpectInputAndSucceed()
+	mp.ExpectInputAndFail(sarama.ErrNotLeaderForPartition)
+	tr := newSaramaTransport(mp, false)
+
+	if err := tr.Deliver(context.Background(), "topic", makeTestRecords(3)); err != nil {
+		t.Fatalf("async Deliver returned error: %v", err)
+	}
+	tr.Close()
+
+	acked, failed, inFlight, lastErr, ok := tr.DeliveryStats()
+	if !ok {
+		t.Fatal("DeliveryStats ok = false")
+	}
+	if acked != 2 || failed != 1 || inFlight != 0 {
+		t.Errorf("DeliveryStats = (%d, %d, %d), want (2, 1, 0)", acked, failed, inFlight)
+	}
+	if lastErr != sarama.ErrNotLeaderForPartition.Error() {
+		t.Errorf("lastErr = %q, want %q", lastErr, sarama.ErrNotLeaderForPartition.Error())
+	}
+}
+
+func TestSaramaTransport_CloseReturnsFlushErrors(t *testing.T) {
+	p := newStallingProducer()
+	p.flushErr = sarama.ErrOutOfBrokers
+	tr := newSaramaTransport(p, false)
+
+	if err := tr.Deliver(context.Background(), "topic", makeTestRecords(2)); err != nil {
+		t.Fatalf("async Deliver returned error: %v", err)
+	}
+	err := tr.Close()
+	var perrs sarama.ProducerErrors
+	if !errors.As(err, &perrs) || len(perrs) != 2 {
+		t.Fatalf("Close() = %v, want 2 ProducerErrors", err)
+	}
+	if again := tr.Close(); again == nil {
+		t.Error("second Close() lost the flush error")
+	}
+	if _, failed, inFlight, _, _ := tr.DeliveryStats(); failed != 2 || inFlight != 0 {
+		t.Errorf("failed=%d inFlight=%d after Close, want 2/0", failed, inFlight)
+	}
+
+	clean := mocks.NewAsyncProducer(t, newMockProducerConfig())
+	if err := newSaramaTransport(clean, false).Close(); err != nil {
+		t.Errorf("Close() without failures = %v, want nil", err)
+	}
+}
+
+func TestSaramaTransport_SyncWaitsForAcks(t *testing.T) {
+	mp := mocks.NewAsyncProducer(t, newMockProducerConfig())
+	mp.ExpectInputAndSucceed()
+	mp.ExpectInputAndSucceed()
+	tr := newSaramaTransport(mp, true)
+	defer tr.Close()
+
+	if err := tr.Deliver(context.Background(), "topic", makeTestRecords(2)); err != nil {
+		t.Fatalf("sync Deliver returned error: %v", err)
+	}
+	// Sync mode returns only after the acks were counted.
+	if acked, _, inFlight, _, _ := tr.DeliveryStats(); acked != 2 || inFlight != 0 {
+		t.Errorf("acked=%d inFlight=%d right after sync Deliver, want 2/0", acked, inFlight)
+	}
+}
+
+func TestSaramaTransport_SyncReturnsDeliveryError(t *testing.T) {
+	mp := mocks.NewAsyncProducer(t, newMockProducerConfig())
+	mp.ExpectInputAndSucceed()
+	mp.ExpectInputAndFail(sarama.ErrOutOfBrokers)
+	tr := newSaramaTransport(mp, true)
+	defer tr.Close()
+
+	err := tr.Deliver(context.Background(), "topic", makeTestRecords(2))
+	if !errors.Is(err, sarama.ErrOutOfBrokers) {
+		t.Fatalf("err = %v, want wrapped ErrOutOfBrokers", err)
+	}
+	if !strings.Contains(err.Error(), "1/2 records") {
+		t.Errorf("err = %q, want failed/total count", err)
+	}
+}
+
+func TestSaramaTransport_SyncHonoursContext(t *testing.T) {
+	tr := newSaramaTransport(newStallingProducer(), true)
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := tr.Deliver(ctx, "topic", makeTestRecords(2)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if _, _, inFlight, _, _ := tr.DeliveryStats(); inFlight != 2 {
+		t.Errorf("inFlight = %d, want 2 unacked records", inFlight)
+	}
+}
+
+func TestKafkaSender_DeliveryStats_Delegates(t *testing.T) {
+	mp := mocks.NewAsyncProducer(t, newMockProducerConfig())
+	mp.ExpectInputAndSucceed()
+	tr := newSaramaTransport(mp, true)
+	defer tr.Close()
+
+	s := newTestKafkaSender(tr)
+	tr.Deliver(context.Background(), "topic", makeTestRecords(1))
+	if acked, _, _, _, ok := s.DeliveryStats(); !ok || acked != 1 {
+		t.Errorf("DeliveryStats acked=%d ok=%v, want 1/true", acked, ok)
+	}
+
+	if _, _, _, _, ok := newTestKafkaSender(&spyTransport{}).DeliveryStats(); ok {
+		t.Error("DeliveryStats ok = true for a transport without ack tracking")
+	}
+}
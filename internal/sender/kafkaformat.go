@@ -15,6 +15,11 @@ import (
 // (e.g., no fan sensors detected). Callers should skip sending silently.
 var ErrNoRows = errors.New("no EARS rows produced")
 
+// ErrSkipRow is returned by a RawFormatter for a row its format cannot
+// represent (e.g. NaN in InfluxDB line protocol). PrepareRecords drops the
+// row instead of failing the whole MetricData.
+var ErrSkipRow = errors.New("row not representable in output format")
+
 // RawFormatter formats an EARSRow into the raw string for a KafkaValue.
 type RawFormatter interface {
 	FormatRaw(row EARSRow, process string) (string, error)
@@ -88,6 +93,9 @@ func PrepareRecords(data *collector.MetricData, eqpInfo *config.EqpInfoConfig, t
 
 	for i, row := range rows {
 		raw, err := formatter.FormatRaw(row, eqpInfo.Process)
+		if errors.Is(err, ErrSkipRow) {
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to format raw for row %d: %w", i, err)
 		}
@@ -107,5 +115,8 @@ func PrepareRecords(data *collector.MetricData, eqpInfo *config.EqpInfoConfig, t
 		})
 	}
 
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: metric type %q", ErrNoRows, data.Type)
+	}
 	return records, nil
 }
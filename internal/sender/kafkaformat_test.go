@@ -304,6 +304,18 @@ func TestPrepareRecords_ErrNoRows(t *testing.T) {
 	}
 }
 
+// skipAllFormatter rejects every row, as a format without NaN support would.
+type skipAllFormatter struct{}
+
+func (skipAllFormatter) FormatRaw(EARSRow, string) (string, error) { return "", ErrSkipRow }
+
+func TestPrepareRecords_SkippedRowsYieldErrNoRows(t *testing.T) {
+	_, err := PrepareRecords(newTestCPUMetricData(), newTestEqpInfo(), 0, skipAllFormatter{})
+	if !errors.Is(err, ErrNoRows) {
+		t.Errorf("expected ErrNoRows when every row is skipped, got %v", err)
+	}
+}
+
 func TestPrepareRecords_TimestampPreserved(t *testing.T) {
 	data := newTestCPUMetricData()
 	eqpInfo := newTestEqpInfo()
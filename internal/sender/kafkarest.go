@@ -3,6 +3,7 @@ package sender
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,19 +22,24 @@ import (
 )
 
 const (
-	kafkaRestContentType = "application/vnd.kafka.json.v2+json"
+	kafkaRestContentType       = "application/vnd.kafka.json.v2+json"
+	kafkaRestBinaryContentType = "application/vnd.kafka.binary.v2+json"
+	kafkaRestAvroContentType   = "application/vnd.kafka.avro.v2+json"
 )
 
 // HTTPTransport implements KafkaTransport via the KafkaRest HTTP proxy.
 type HTTPTransport struct {
-	client    *http.Client
-	transport *http.Transport
-	baseURL   string
-	closeOnce sync.Once
+	client     *http.Client
+	transport  *http.Transport
+	baseURL    string
+	encoder    batchEncoder
+	compressor *requestCompressor // nil → uncompressed requests
+	closeOnce  sync.Once
 }
 
 // NewHTTPTransport creates a new HTTP-based Kafka REST transport.
-func NewHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig) (*HTTPTransport, error) {
+// restCfg selects the API version, embedded format and request compression.
+func NewHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig, restCfg config.KafkaRestConfig) (*HTTPTransport, error) {
 	transport, err := network.NewHTTPTransport(socksCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP transport for KafkaRest: %w", err)
@@ -45,29 +51,20 @@ func NewHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig) (*HTTPT
 	}
 
 	return &HTTPTransport{
-		client:    client,
-		transport: transport,
-		baseURL:   ensureHTTPScheme(kafkaRestAddr),
+		client:     client,
+		transport:  transport,
+		baseURL:    ensureHTTPScheme(kafkaRestAddr),
+		encoder:    newKafkaRestEncoder(restCfg),
+		compressor: newRequestCompressor(restCfg),
 	}, nil
 }
 
-// Deliver sends records to the KafkaRest proxy as a KafkaMessageWrapper2 JSON POST.
+// Deliver sends records to the KafkaRest proxy in the configured API
+// version and embedded format (v2 JSON KafkaMessageWrapper2 by default).
+// When the proxy rejects individual records with a retriable error, only
+// those records are retried.
 func (t *HTTPTransport) Deliver(ctx context.Context, topic string, records []KafkaRecord) error {
-	messages := make([]KafkaMessage2, len(records))
-	for i, rec := range records {
-		messages[i] = KafkaMessage2{
-			Key:   rec.Key,
-			Value: rec.Value,
-		}
-	}
-
-	wrapper := KafkaMessageWrapper2{Records: messages}
-	body, err := json.Marshal(wrapper)
-	if err != nil {
-		return fmt.Errorf("failed to marshal KafkaMessageWrapper2: %w", err)
-	}
-
-	url := t.baseURL + "/topics/" + topic
+	url := t.encoder.URL(t.baseURL, topic)
 
 	const defaultRetries = 2
 	const defaultRetryDelay = 500 * time.Millisecond
@@ -82,10 +79,18 @@ func (t *HTTPTransport) Deliver(ctx context.Context, topic string, records []Kaf
 			}
 		}
 
-		lastErr = t.doPost(ctx, url, body)
+		body, err := t.encoder.Encode(records)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s batch: %w", t.encoder.Name(), err)
+		}
+		lastErr = t.doPost(ctx, url, body, records)
 		if lastErr == nil {
 			return nil
 		}
+		var pe *partialBatchError
+		if errors.As(lastErr, &pe) {
+			records = pe.retry
+		}
 
 		log := logger.WithComponent("kafkarest-transport")
 		log.Warn().
@@ -106,31 +111,53 @@ func (t *HTTPTransport) Close() error {
 	return nil
 }
 
-func (t *HTTPTransport) doPost(ctx context.Context, url string, body []byte) error {
+// CompressionStats returns request compression byte counters. ok is false
+// when compression is not configured. Implements sender.CompressionStatsProvider.
+func (t *HTTPTransport) CompressionStats() (rawBytes, wireBytes int64, ok bool) {
+	return t.compressor.stats()
+}
+
+// doPost sends body, compressed when configured. An HTTP 415 for a
+// compressed body disables compression and resends it uncompressed.
+// records are the batch encoded in body, for per-record result parsing.
+func (t *HTTPTransport) doPost(ctx context.Context, url string, body []byte, records []KafkaRecord) error {
+	payload, encoding := t.compressor.compress(body)
+	status, err := t.post(ctx, url, payload, encoding, records)
+	if status == http.StatusUnsupportedMediaType && encoding != "" {
+		t.compressor.disable(t.encoder.Name())
+		payload = body
+		status, err = t.post(ctx, url, payload, "", records)
+	}
+	if err == nil {
+		t.compressor.record(len(body), len(payload))
+	}
+	return err
+}
+
+func (t *HTTPTransport) post(ctx context.Context, url string, body []byte, encoding string, records []KafkaRecord) (int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", kafkaRestContentType)
+	t.encoder.SetHeaders(req.Header)
+	t.compressor.setHeader(req.Header, encoding)
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	io.Copy(io.Discard, resp.Body)
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("KafkaRest returned HTTP %d", resp.StatusCode)
-	}
-
-	return nil
+	// Permanently rejected records are logged by the parser; this unbuffered
+	// transport keeps no drop counter.
+	_, err = readBatchResponse(t.encoder, resp, records)
+	return resp.StatusCode, err
 }
 
 // bufferedEntry holds records along with their target topic.
 type bufferedEntry struct {
-	topic   string
-	records []KafkaRecord
+	topic    string
+	records  []KafkaRecord
+	attempts int // failed flush attempts so far (requeued entries only)
 }
 
 // BufferedHTTPTransport implements KafkaTransport with buffered batch delivery via HTTP.
@@ -139,11 +166,25 @@ type bufferedEntry struct {
 // on the in-memory record count. When exceeded, oldest entries are dropped
 // (FIFO) to keep RSS bounded if KafkaRest becomes unreachable. See
 // docs/runbooks/buffered-http-transport-monitoring.md for diagnosis.
+//
+// Failure handling: a batch whose POST fails is requeued at the head of the
+// buffer and retried on a later flush, up to MaxRetries times. Flushes are
+// gated by a circuit breaker (see circuitBreaker) that applies exponential
+// backoff with jitter between attempts and stops sending entirely while
+// KafkaRest is down.
+//
+// With a Spool attached (NewSpooledHTTPTransport), evicted entries and
+// batches that exhausted MaxRetries are written to disk instead of being
+// dropped, and replayed at Spool.ReplayRate once a POST succeeds again.
 type BufferedHTTPTransport struct {
-	client    *http.Client
-	transport *http.Transport
-	baseURL   string
-	batchCfg  config.BatchConfig
+	client     *http.Client
+	transport  *http.Transport
+	baseURL    string
+	batchCfg   config.BatchConfig
+	encoder    batchEncoder
+	compressor *requestCompressor // nil → uncompressed requests
+	spool      *Spool             // nil → overflow and failed batches are dropped
+	breaker    *circuitBreaker
 
 	mu          sync.Mutex
 	buffer      []bufferedEntry
@@ -168,9 +209,24 @@ type BufferedHTTPTransport struct {
 
 // NewBufferedHTTPTransport creates a new buffered HTTP transport with batch delivery.
 func NewBufferedHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig, batchCfg config.BatchConfig) (*BufferedHTTPTransport, error) {
+	return NewSpooledHTTPTransport(kafkaRestAddr, socksCfg, batchCfg, config.KafkaRestConfig{}, nil)
+}
+
+// NewSpooledHTTPTransport creates a buffered HTTP transport backed by an
+// on-disk spool. spool may be nil, which is equivalent to
+// NewBufferedHTTPTransport. The spool is not owned by the transport and is
+// shared across transports swapped in by the address refresher. restCfg
+// selects request compression.
+func NewSpooledHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig, batchCfg config.BatchConfig, restCfg config.KafkaRestConfig, spool *Spool) (*BufferedHTTPTransport, error) {
+	return newEncodedHTTPTransport(ensureHTTPScheme(kafkaRestAddr), socksCfg, batchCfg, spool, newKafkaRestEncoder(restCfg), newRequestCompressor(restCfg))
+}
+
+// newEncodedHTTPTransport creates a buffered HTTP transport that POSTs
+// batches encoded by enc to baseURL. comp may be nil (no compression).
+func newEncodedHTTPTransport(baseURL string, socksCfg config.SOCKSConfig, batchCfg config.BatchConfig, spool *Spool, enc batchEncoder, comp *requestCompressor) (*BufferedHTTPTransport, error) {
 	transport, err := network.NewHTTPTransport(socksCfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP transport for KafkaRest: %w", err)
+		return nil, fmt.Errorf("failed to create HTTP transport for %s: %w", enc.Name(), err)
 	}
 
 	client := &http.Client{
@@ -184,8 +240,13 @@ func NewBufferedHTTPTransport(kafkaRestAddr string, socksCfg config.SOCKSConfig,
 	t := &BufferedHTTPTransport{
 		client:     client,
 		transport:  transport,
-		baseURL:    ensureHTTPScheme(kafkaRestAddr),
+		baseURL:    baseURL,
 		batchCfg:   batchCfg,
+		encoder:    enc,
+		compressor: comp,
+		spool:      spool,
+		breaker: newCircuitBreaker(defaultClock, batchCfg.BreakerThreshold,
+			batchCfg.BreakerOpenTimeout, batchCfg.RetryBackoff, batchCfg.MaxRetryBackoff),
 		dropLogger: sampled,
 		flushCh:    make(chan struct{}, 1),
 		stopCh:     make(chan struct{}),
@@ -217,12 +278,31 @@ func (t *BufferedHTTPTransport) Deliver(_ context.Context, topic string, records
 	t.buffer = append(t.buffer, bufferedEntry{topic: topic, records: records})
 	t.bufferCount += len(records)
 	t.topic = topic
+	evicted := t.enforceCapLocked()
+	cur := int64(t.bufferCount)
+	flushNeeded := t.bufferCount >= t.batchCfg.FlushMessages
+	t.mu.Unlock()
 
-	var droppedCount int
+	t.spoolOrDrop(evicted, "overflow", cur)
+
+	if flushNeeded {
+		select {
+		case t.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// enforceCapLocked evicts the oldest entries while the buffer exceeds
+// MaxBufferedRecords and refreshes the observability counters. Caller holds mu.
+func (t *BufferedHTTPTransport) enforceCapLocked() []bufferedEntry {
+	var evicted []bufferedEntry
 	cap := t.batchCfg.MaxBufferedRecords
 	if cap > 0 {
 		for t.bufferCount > cap && len(t.buffer) > 0 {
-			droppedCount += len(t.buffer[0].records)
+			evicted = append(evicted, t.buffer[0])
 			t.bufferCount -= len(t.buffer[0].records)
 			t.buffer = t.buffer[1:]
 		}
@@ -233,28 +313,106 @@ func (t *BufferedHTTPTransport) Deliver(_ context.Context, topic string, records
 	if cur > t.bufferHighWaterMark.Load() {
 		t.bufferHighWaterMark.Store(cur)
 	}
+	return evicted
+}
 
-	flushNeeded := t.bufferCount >= t.batchCfg.FlushMessages
-	t.mu.Unlock()
+// spoolOrDrop hands entries that left the buffer without being delivered to
+// the spool, dropping (and accounting for) whatever cannot be spooled.
+// Called outside mu so disk latency never blocks Deliver callers on the lock.
+func (t *BufferedHTTPTransport) spoolOrDrop(entries []bufferedEntry, reason string, bufferCount int64) {
+	var droppedCount int
+	for _, e := range entries {
+		if !t.spoolRecords(e.topic, e.records, reason) {
+			droppedCount += len(e.records)
+		}
+	}
+	if droppedCount == 0 {
+		return
+	}
 
-	if droppedCount > 0 {
-		newTotal := t.droppedTotal.Add(int64(droppedCount))
+	newTotal := t.droppedTotal.Add(int64(droppedCount))
+	if reason == "overflow" {
 		t.dropLogger.Error().
 			Int("dropped_records", droppedCount).
-			Int64("buffer_count", cur).
-			Int("max_buffered_records", cap).
+			Int64("buffer_count", bufferCount).
+			Int("max_buffered_records", t.batchCfg.MaxBufferedRecords).
 			Int64("dropped_total", newTotal).
 			Msg("BUFFER_DROP_OLDEST oldest records dropped due to buffer cap (sampled 1/10)")
+		return
 	}
+	t.dropLogger.Error().
+		Str("reason", reason).
+		Int("dropped_records", droppedCount).
+		Int("max_retries", t.batchCfg.MaxRetries).
+		Int64("dropped_total", newTotal).
+		Msg("BUFFER_DROP_FAILED batch dropped after failed delivery (sampled 1/10)")
+}
 
-	if flushNeeded {
-		select {
-		case t.flushCh <- struct{}{}:
-		default:
+// requeue puts undelivered entries back at the head of the buffer so they
+// are retried first on the next allowed flush. Entries that have used up
+// MaxRetries, and everything when the transport is closing, go to the spool
+// (or are dropped) instead.
+func (t *BufferedHTTPTransport) requeue(entries []bufferedEntry, closing bool) {
+	var keep, giveUp []bufferedEntry
+	for _, e := range entries {
+		if closing || e.attempts > t.batchCfg.MaxRetries {
+			giveUp = append(giveUp, e)
+		} else {
+			keep = append(keep, e)
 		}
 	}
 
-	return nil
+	var evicted []bufferedEntry
+	var cur int64
+	if len(keep) > 0 {
+		t.mu.Lock()
+		requeued := 0
+		for _, e := range keep {
+			requeued += len(e.records)
+		}
+		t.buffer = append(keep, t.buffer...)
+		t.bufferCount += requeued
+		evicted = t.enforceCapLocked()
+		cur = int64(t.bufferCount)
+		t.mu.Unlock()
+	}
+
+	reason := "send_failed"
+	if closing {
+		reason = "close"
+	}
+	t.spoolOrDrop(giveUp, reason, cur)
+	t.spoolOrDrop(evicted, "overflow", cur)
+}
+
+// SpoolStats returns the attached spool's observability counters. ok is
+// false when no spool is configured. Implements sender.SpoolStatsProvider.
+func (t *BufferedHTTPTransport) SpoolStats() (depth, bytes, replayed, expired int64, ok bool) {
+	if t.spool == nil {
+		return 0, 0, 0, 0, false
+	}
+	depth, bytes, replayed, expired = t.spool.SpoolStats()
+	return depth, bytes, replayed, expired, true
+}
+
+// BreakerStats returns the circuit breaker state (0=closed, 1=half-open,
+// 2=open), the cumulative number of times it opened and the current count
+// of consecutive failed flushes. ok is always true for this transport.
+// Implements sender.BreakerStatsProvider.
+func (t *BufferedHTTPTransport) BreakerStats() (state, opens, failures int64, ok bool) {
+	state, opens, failures = t.breaker.Stats()
+	return state, opens, failures, true
+}
+
+// CompressionStats returns request compression byte counters. ok is false
+// when compression is not configured. Implements sender.CompressionStatsProvider.
+func (t *BufferedHTTPTransport) CompressionStats() (rawBytes, wireBytes int64, ok bool) {
+	return t.compressor.stats()
+}
+
+// Spool returns the attached spool (nil if none).
+func (t *BufferedHTTPTransport) Spool() *Spool {
+	return t.spool
 }
 
 // BufferStats returns lock-free observability snapshots for the buffer.
@@ -287,6 +445,15 @@ func (t *BufferedHTTPTransport) flushLoop() {
 	ticker := time.NewTicker(t.batchCfg.FlushFrequency)
 	defer ticker.Stop()
 
+	// Replay runs on its own 1s tick so the spool drains at ReplayRate
+	// independently of FlushFrequency. A nil channel never fires.
+	var replayC <-chan time.Time
+	if t.spool != nil {
+		replayTicker := time.NewTicker(spoolReplayTick)
+		defer replayTicker.Stop()
+		replayC = replayTicker.C
+	}
+
 	for {
 		select {
 		case <-t.stopCh:
@@ -296,11 +463,94 @@ func (t *BufferedHTTPTransport) flushLoop() {
 			t.flush("timer")
 		case <-t.flushCh:
 			t.flush("count")
+		case <-replayC:
+			t.replay()
 		}
 	}
 }
 
+// spoolReplayTick is the replay cadence; each tick sends up to
+// Spool.ReplayRate records. Variable so tests can shorten it.
+var spoolReplayTick = time.Second
+
+// replay sends the next batch of spooled records while the circuit breaker
+// is healthy. Entries are committed only after every record in the
+// batch was accepted, otherwise they are released for the next tick.
+func (t *BufferedHTTPTransport) replay() {
+	if !t.breaker.Healthy() {
+		return
+	}
+
+	batch, ok, err := t.spool.Next(t.spool.ReplayRate())
+	log := logger.WithComponent("kafkarest-spool")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read spool")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	for _, e := range batch.Entries {
+		if _, err := t.sendBatchWithSplit(e.Topic, e.Records); err != nil {
+			t.breaker.Failure()
+			t.spool.Release(batch)
+			log.Warn().
+				Err(err).
+				Int("records", batch.Records).
+				Msg("Spool replay failed, will retry")
+			return
+		}
+	}
+	t.breaker.Success()
+	t.spool.Commit(batch)
+
+	depth, _, replayed, _ := t.spool.SpoolStats()
+	log.Info().
+		Int("records", batch.Records).
+		Int64("spool_depth", depth).
+		Int64("replayed_total", replayed).
+		Msg("SPOOL_REPLAY spooled records delivered")
+}
+
+// spoolRecords writes records to the spool. Returns false when there is no
+// spool or the write failed, in which case the caller must count a drop.
+func (t *BufferedHTTPTransport) spoolRecords(topic string, records []KafkaRecord, reason string) bool {
+	if t.spool == nil {
+		return false
+	}
+	if err := t.spool.Append(topic, records); err != nil {
+		t.dropLogger.Error().
+			Err(err).
+			Str("reason", reason).
+			Int("records", len(records)).
+			Msg("SPOOL_WRITE_FAILED records could not be spooled (sampled 1/10)")
+		return false
+	}
+	log := logger.WithComponent("kafkarest-spool")
+	log.Debug().
+		Str("reason", reason).
+		Int("records", len(records)).
+		Msg("Records spooled to disk")
+	return true
+}
+
 func (t *BufferedHTTPTransport) flush(trigger string) {
+	closing := trigger == "close"
+	log := logger.WithComponent("buffered-kafkarest")
+
+	// While the breaker is open (or the backoff has not elapsed) records stay
+	// in the buffer. On close they are spooled/dropped without a POST that
+	// would only stall shutdown for the HTTP timeout.
+	allowed := t.breaker.Allow()
+	if !allowed && !closing {
+		log.Debug().
+			Str("trigger", trigger).
+			Str("breaker", t.breaker.State().String()).
+			Msg("Flush deferred by backoff/circuit breaker")
+		return
+	}
+
 	t.mu.Lock()
 	if len(t.buffer) == 0 {
 		t.mu.Unlock()
@@ -312,27 +562,66 @@ func (t *BufferedHTTPTransport) flush(trigger string) {
 	t.bufferCountObs.Store(0)
 	t.mu.Unlock()
 
-	// Aggregate all records by topic
-	topicRecords := make(map[string][]KafkaRecord)
+	if !allowed {
+		t.requeue(entries, true)
+		return
+	}
+
+	// Aggregate records by topic, preserving first-seen topic order. A
+	// topic's attempt count is the highest of its entries.
+	var topics []string
+	byTopic := make(map[string]*bufferedEntry)
 	totalRecords := 0
 	for _, entry := range entries {
-		topicRecords[entry.topic] = append(topicRecords[entry.topic], entry.records...)
+		agg, ok := byTopic[entry.topic]
+		if !ok {
+			agg = &bufferedEntry{topic: entry.topic}
+			byTopic[entry.topic] = agg
+			topics = append(topics, entry.topic)
+		}
+		agg.records = append(agg.records, entry.records...)
+		if entry.attempts > agg.attempts {
+			agg.attempts = entry.attempts
+		}
 		totalRecords += len(entry.records)
 	}
 
-	log := logger.WithComponent("buffered-kafkarest")
 	log.Debug().
 		Str("trigger", trigger).
 		Int("records", totalRecords).
-		Int("topics", len(topicRecords)).
+		Int("topics", len(topics)).
 		Msg("Flushing buffered records")
 
-	for topic, records := range topicRecords {
-		t.sendBatchWithSplit(topic, records)
+	for i, topic := range topics {
+		agg := byTopic[topic]
+		unsent, err := t.sendBatchWithSplit(topic, agg.records)
+		if err == nil {
+			t.breaker.Success()
+			continue
+		}
+
+		// KafkaRest is failing: stop sending and requeue this topic's
+		// remainder plus every topic not attempted yet.
+		t.breaker.Failure()
+		failed := []bufferedEntry{{topic: topic, records: unsent, attempts: agg.attempts + 1}}
+		for _, rest := range topics[i+1:] {
+			failed = append(failed, *byTopic[rest])
+		}
+		log.Warn().
+			Err(err).
+			Int("records", len(unsent)).
+			Int("attempt", agg.attempts+1).
+			Str("breaker", t.breaker.State().String()).
+			Msg("Buffered KafkaRest send failed, requeued")
+		t.requeue(failed, closing)
+		return
 	}
 }
 
-func (t *BufferedHTTPTransport) sendBatchWithSplit(topic string, records []KafkaRecord) {
+// sendBatchWithSplit sends records in MaxBatchSize chunks, stopping at the
+// first failure. It returns the records that were not delivered (the failed
+// chunk and everything after it) together with the error.
+func (t *BufferedHTTPTransport) sendBatchWithSplit(topic string, records []KafkaRecord) ([]KafkaRecord, error) {
 	maxSize := t.batchCfg.MaxBatchSize
 	if maxSize <= 0 {
 		maxSize = len(records)
@@ -343,77 +632,243 @@ func (t *BufferedHTTPTransport) sendBatchWithSplit(topic string, records []Kafka
 		if end > len(records) {
 			end = len(records)
 		}
-		t.sendBatch(topic, records[i:end])
-	}
-}
-
-func (t *BufferedHTTPTransport) sendBatch(topic string, records []KafkaRecord) {
-	messages := make([]KafkaMessage2, len(records))
-	for i, rec := range records {
-		messages[i] = KafkaMessage2{
-			Key:   rec.Key,
-			Value: rec.Value,
+		if err := t.sendBatch(topic, records[i:end]); err != nil {
+			var pe *partialBatchError
+			if errors.As(err, &pe) {
+				// The rest of the chunk was accepted or permanently rejected.
+				return append(pe.retry, records[end:]...), err
+			}
+			return records[i:], err
 		}
 	}
+	return nil, nil
+}
 
-	wrapper := KafkaMessageWrapper2{Records: messages}
-	body, err := json.Marshal(wrapper)
+// sendBatch POSTs one batch. Retrying is the caller's job (requeue +
+// breaker backoff), so a dead endpoint never blocks the flush goroutine.
+func (t *BufferedHTTPTransport) sendBatch(topic string, records []KafkaRecord) error {
+	log := logger.WithComponent("buffered-kafkarest")
+	body, err := t.encoder.Encode(records)
 	if err != nil {
-		log := logger.WithComponent("buffered-kafkarest")
-		log.Error().Err(err).Msg("Failed to marshal batch")
-		return
+		newTotal := t.droppedTotal.Add(int64(len(records)))
+		log.Error().
+			Err(err).
+			Str("backend", t.encoder.Name()).
+			Int("dropped_records", len(records)).
+			Int64("dropped_total", newTotal).
+			Msg("Failed to marshal batch, records dropped")
+		return nil // not retryable; spooling would fail the same way
 	}
 
-	url := t.baseURL + "/topics/" + topic
-	log := logger.WithComponent("buffered-kafkarest")
-
-	var lastErr error
-	for attempt := 0; attempt <= t.batchCfg.MaxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(t.batchCfg.RetryBackoff)
-		}
-
-		lastErr = t.doPost(url, body)
-		if lastErr == nil {
-			log.Debug().
-				Int("records", len(records)).
-				Str("topic", topic).
-				Msg("Batch sent successfully")
-			return
-		}
-
-		log.Warn().
-			Err(lastErr).
-			Int("attempt", attempt+1).
-			Int("records", len(records)).
-			Msg("Buffered KafkaRest send failed, retrying")
+	url := t.encoder.URL(t.baseURL, topic)
+	if err := t.doPost(url, body, records); err != nil {
+		return err
 	}
-
-	log.Error().
-		Err(lastErr).
+	log.Debug().
 		Int("records", len(records)).
-		Msg("Buffered KafkaRest send failed after all retries, dropping batch")
+		Str("topic", topic).
+		Str("backend", t.encoder.Name()).
+		Msg("Batch sent successfully")
+	return nil
+}
+
+// doPost sends body, compressed when configured. An HTTP 415 for a
+// compressed body disables compression and resends it uncompressed, so a
+// proxy without Content-Encoding support costs one extra request. records
+// are the batch encoded in body, for per-record result parsing.
+func (t *BufferedHTTPTransport) doPost(url string, body []byte, records []KafkaRecord) error {
+	payload, encoding := t.compressor.compress(body)
+	status, err := t.post(url, payload, encoding, records)
+	if status == http.StatusUnsupportedMediaType && encoding != "" {
+		t.compressor.disable(t.encoder.Name())
+		payload = body
+		status, err = t.post(url, payload, "", records)
+	}
+	if err == nil {
+		t.compressor.record(len(body), len(payload))
+	}
+	return err
 }
 
-func (t *BufferedHTTPTransport) doPost(url string, body []byte) error {
+func (t *BufferedHTTPTransport) post(url string, body []byte, encoding string, records []KafkaRecord) (int, error) {
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", kafkaRestContentType)
+	t.encoder.SetHeaders(req.Header)
+	t.compressor.setHeader(req.Header, encoding)
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	io.Copy(io.Discard, resp.Body)
+	rejected, err := readBatchResponse(t.encoder, resp, records)
+	if rejected > 0 {
+		t.droppedTotal.Add(int64(rejected))
+	}
+	return resp.StatusCode, err
+}
 
+// readBatchResponse drains resp and maps it to an error: HTTP >= 400 fails
+// the whole batch; a 2xx body is handed to encoders that report
+// per-record results (batchResultParser). rejected is the number of
+// records the backend dropped permanently.
+func readBatchResponse(enc batchEncoder, resp *http.Response, records []KafkaRecord) (rejected int, err error) {
+	defer io.Copy(io.Discard, resp.Body)
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("KafkaRest returned HTTP %d", resp.StatusCode)
+		return 0, fmt.Errorf("%s returned HTTP %d", enc.Name(), resp.StatusCode)
+	}
+	if p, ok := enc.(batchResultParser); ok {
+		return p.ParseResults(resp.Body, records)
 	}
+	return 0, nil
+}
 
-	return nil
+// batchResultParser is implemented by encoders whose backend reports a
+// result per record inside a successful response (KafkaRest v3).
+type batchResultParser interface {
+	// ParseResults reads the response body for records. Records rejected
+	// with a retriable error are returned in a *partialBatchError; records
+	// rejected permanently are logged, dropped and counted in rejected.
+	ParseResults(body io.Reader, records []KafkaRecord) (rejected int, err error)
+}
+
+// partialBatchError reports that the backend accepted a batch but failed
+// some records with a retriable error. Only retry is requeued.
+type partialBatchError struct {
+	backend string
+	retry   []KafkaRecord
+	total   int
+	first   string // first per-record error message
+}
+
+func (e *partialBatchError) Error() string {
+	return fmt.Sprintf("%s failed %d of %d records: %s", e.backend, len(e.retry), e.total, e.first)
+}
+
+// batchEncoder defines the wire format of BufferedHTTPTransport. Buffering,
+// retry, breaker and spool are format-agnostic; the encoder decides where a
+// batch is POSTed and how it is serialized.
+type batchEncoder interface {
+	// Name identifies the backend in errors and logs ("KafkaRest", "OTLP").
+	Name() string
+	// URL returns the POST target for a batch of topic.
+	URL(baseURL, topic string) string
+	// Encode serializes one batch into a request body.
+	Encode(records []KafkaRecord) ([]byte, error)
+	// SetHeaders sets Content-Type and any backend-specific headers.
+	SetHeaders(h http.Header)
+}
+
+// newKafkaRestEncoder selects the KafkaRest wire format for
+// cfg.APIVersion and cfg.EmbeddedFormat. The zero config is v2 JSON.
+func newKafkaRestEncoder(cfg config.KafkaRestConfig) batchEncoder {
+	format := strings.ToLower(cfg.EmbeddedFormat)
+	if format == "" {
+		format = "json"
+	}
+	if strings.EqualFold(cfg.APIVersion, "v3") {
+		return kafkaRestV3Encoder{
+			clusterID:     cfg.ClusterID,
+			format:        format,
+			keySchemaID:   cfg.KeySchemaID,
+			valueSchemaID: cfg.ValueSchemaID,
+		}
+	}
+	return kafkaRestEncoder{
+		format:        format,
+		keySchemaID:   cfg.KeySchemaID,
+		valueSchemaID: cfg.ValueSchemaID,
+	}
+}
+
+// kafkaRestEncoder is the KafkaRest v2 encoder (POST /topics/{topic}).
+// format is the embedded format: "json" (KafkaMessageWrapper2), "binary"
+// (base64 key and JSON value) or "avro" (value validated against
+// valueSchemaID; the key is sent only with keySchemaID).
+type kafkaRestEncoder struct {
+	format        string
+	keySchemaID   int
+	valueSchemaID int
+}
+
+// kafkaRestBinaryMessage2 is a v2 binary embedded-format record.
+type kafkaRestBinaryMessage2 struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// kafkaRestAvroWrapper2 is the v2 Avro embedded-format request.
+type kafkaRestAvroWrapper2 struct {
+	KeySchemaID   int                     `json:"key_schema_id,omitempty"`
+	ValueSchemaID int                     `json:"value_schema_id"`
+	Records       []kafkaRestAvroMessage2 `json:"records"`
+}
+
+type kafkaRestAvroMessage2 struct {
+	Key   *string    `json:"key,omitempty"`
+	Value KafkaValue `json:"value"`
+}
+
+func (kafkaRestEncoder) Name() string { return "KafkaRest" }
+
+func (kafkaRestEncoder) URL(baseURL, topic string) string {
+	return baseURL + "/topics/" + topic
+}
+
+func (e kafkaRestEncoder) Encode(records []KafkaRecord) ([]byte, error) {
+	switch e.format {
+	case "binary":
+		messages := make([]kafkaRestBinaryMessage2, len(records))
+		for i, rec := range records {
+			value, err := json.Marshal(rec.Value)
+			if err != nil {
+				return nil, err
+			}
+			messages[i] = kafkaRestBinaryMessage2{
+				Key:   base64.StdEncoding.EncodeToString([]byte(rec.Key)),
+				Value: base64.StdEncoding.EncodeToString(value),
+			}
+		}
+		return json.Marshal(struct {
+			Records []kafkaRestBinaryMessage2 `json:"records"`
+		}{messages})
+	case "avro":
+		wrapper := kafkaRestAvroWrapper2{
+			KeySchemaID:   e.keySchemaID,
+			ValueSchemaID: e.valueSchemaID,
+			Records:       make([]kafkaRestAvroMessage2, len(records)),
+		}
+		for i, rec := range records {
+			wrapper.Records[i].Value = rec.Value
+			if e.keySchemaID > 0 {
+				key := rec.Key
+				wrapper.Records[i].Key = &key
+			}
+		}
+		return json.Marshal(wrapper)
+	default:
+		messages := make([]KafkaMessage2, len(records))
+		for i, rec := range records {
+			messages[i] = KafkaMessage2{
+				Key:   rec.Key,
+				Value: rec.Value,
+			}
+		}
+		return json.Marshal(KafkaMessageWrapper2{Records: messages})
+	}
+}
+
+func (e kafkaRestEncoder) SetHeaders(h http.Header) {
+	switch e.format {
+	case "binary":
+		h.Set("Content-Type", kafkaRestBinaryContentType)
+	case "avro":
+		h.Set("Content-Type", kafkaRestAvroContentType)
+	default:
+		h.Set("Content-Type", kafkaRestContentType)
+	}
 }
 
 func ensureHTTPScheme(addr string) string {
@@ -2,6 +2,7 @@ package sender
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,7 +32,7 @@ func newTestHTTPSender(t *testing.T, handler http.HandlerFunc) (*KafkaSender, *h
 		Index:    "42",
 	}
 
-	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{})
+	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{}, config.KafkaRestConfig{})
 	if err != nil {
 		t.Fatalf("failed to create HTTPTransport: %v", err)
 	}
@@ -88,6 +89,74 @@ func TestHTTPTransport_Send_Success(t *testing.T) {
 	}
 }
 
+func TestKafkaRestEncoder_BinaryFormat(t *testing.T) {
+	enc := newKafkaRestEncoder(config.KafkaRestConfig{EmbeddedFormat: "binary"})
+	h := http.Header{}
+	enc.SetHeaders(h)
+	if ct := h.Get("Content-Type"); ct != "application/vnd.kafka.binary.v2+json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	body, err := enc.Encode(makeTestRecords(1))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var wrapper struct {
+		Records []kafkaRestBinaryMessage2 `json:"records"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil || len(wrapper.Records) != 1 {
+		t.Fatalf("body = %s, err = %v", body, err)
+	}
+	key, _ := base64.StdEncoding.DecodeString(wrapper.Records[0].Key)
+	value, _ := base64.StdEncoding.DecodeString(wrapper.Records[0].Value)
+	var kv KafkaValue
+	if string(key) != "KEY0" || json.Unmarshal(value, &kv) != nil || kv.Raw != "raw-0" {
+		t.Errorf("decoded key=%q value=%q, want KEY0 and KafkaValue JSON", key, value)
+	}
+}
+
+func TestKafkaRestEncoder_AvroFormat(t *testing.T) {
+	enc := newKafkaRestEncoder(config.KafkaRestConfig{EmbeddedFormat: "avro", ValueSchemaID: 21})
+	h := http.Header{}
+	enc.SetHeaders(h)
+	if ct := h.Get("Content-Type"); ct != "application/vnd.kafka.avro.v2+json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	body, _ := enc.Encode(makeTestRecords(2))
+	var got map[string]interface{}
+	json.Unmarshal(body, &got)
+	if got["value_schema_id"] != float64(21) {
+		t.Errorf("value_schema_id = %v, want 21", got["value_schema_id"])
+	}
+	if _, ok := got["key_schema_id"]; ok {
+		t.Error("key_schema_id must be omitted without KeySchemaID")
+	}
+	if strings.Contains(string(body), `"key"`) {
+		t.Errorf("records must not carry keys without KeySchemaID: %s", body)
+	}
+
+	enc = newKafkaRestEncoder(config.KafkaRestConfig{EmbeddedFormat: "avro", ValueSchemaID: 21, KeySchemaID: 20})
+	body, _ = enc.Encode(makeTestRecords(1))
+	var wrapper kafkaRestAvroWrapper2
+	json.Unmarshal(body, &wrapper)
+	if wrapper.KeySchemaID != 20 || wrapper.Records[0].Key == nil || *wrapper.Records[0].Key != "KEY0" {
+		t.Errorf("wrapper = %+v, want key_schema_id 20 and key KEY0", wrapper)
+	}
+	if wrapper.Records[0].Value.ESID != "esid-0" {
+		t.Errorf("value = %+v, want KafkaValue record", wrapper.Records[0].Value)
+	}
+}
+
+func TestNewKafkaRestEncoder_Selection(t *testing.T) {
+	if _, ok := newKafkaRestEncoder(config.KafkaRestConfig{}).(kafkaRestEncoder); !ok {
+		t.Error("zero config must select the v2 encoder")
+	}
+	if _, ok := newKafkaRestEncoder(config.KafkaRestConfig{APIVersion: "V3", ClusterID: "c"}).(kafkaRestV3Encoder); !ok {
+		t.Error(`APIVersion "V3" must select the v3 encoder`)
+	}
+}
+
 func TestHTTPTransport_Send_TopicInURL(t *testing.T) {
 	var receivedPath string
 
@@ -903,7 +972,7 @@ func TestBufferedHTTPTransport_Close_ClosesIdleConnections(t *testing.T) {
 
 // T13: HTTPTransport.Close()도 idempotent해야 함
 func TestHTTPTransport_Close_Idempotent(t *testing.T) {
-	transport, err := NewHTTPTransport("http://example.invalid", config.SOCKSConfig{})
+	transport, err := NewHTTPTransport("http://example.invalid", config.SOCKSConfig{}, config.KafkaRestConfig{})
 	if err != nil {
 		t.Fatalf("failed to create HTTPTransport: %v", err)
 	}
@@ -939,7 +1008,7 @@ func TestHTTPTransport_Close_ClosesIdleConnections(t *testing.T) {
 	server.Start()
 	defer server.Close()
 
-	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{})
+	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{}, config.KafkaRestConfig{})
 	if err != nil {
 		t.Fatalf("failed to create HTTPTransport: %v", err)
 	}
@@ -1192,3 +1261,290 @@ func TestBufferedHTTPTransport_ZeroCapDisablesEnforcement(t *testing.T) {
 		t.Errorf("expected count=%d, got %d", total, count)
 	}
 }
+
+// --- Spool integration ---
+
+func newTestSpooledTransport(t *testing.T, handler http.HandlerFunc, batchCfg config.BatchConfig) (*BufferedHTTPTransport, *Spool, *httptest.Server) {
+	t.Helper()
+	orig := spoolReplayTick
+	spoolReplayTick = 20 * time.Millisecond
+	t.Cleanup(func() { spoolReplayTick = orig })
+
+	spool := openTestSpool(t, newTestSpoolConfig(t.TempDir()))
+	server := httptest.NewServer(handler)
+	transport, err := NewSpooledHTTPTransport(server.URL, config.SOCKSConfig{}, batchCfg, config.KafkaRestConfig{}, spool)
+	if err != nil {
+		t.Fatalf("failed to create spooled transport: %v", err)
+	}
+	return transport, spool, server
+}
+
+// TestBufferedHTTPTransport_FailedBatchSpooledAndReplayed verifies that a
+// batch which exhausted MaxRetries lands in the spool instead of being
+// dropped, and is replayed once KafkaRest answers again.
+func TestBufferedHTTPTransport_FailedBatchSpooledAndReplayed(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		healthy  bool
+		received []string
+	)
+
+	batchCfg := newTestBatchConfig()
+	batchCfg.FlushFrequency = 50 * time.Millisecond
+	batchCfg.MaxRetries = 0
+
+	transport, spool, server := newTestSpooledTransport(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var wrapper KafkaMessageWrapper2
+		_ = json.Unmarshal(body, &wrapper)
+		for _, msg := range wrapper.Records {
+			received = append(received, msg.Value.Raw)
+		}
+		w.WriteHeader(http.StatusOK)
+	}, batchCfg)
+	defer server.Close()
+	defer transport.Close()
+
+	transport.Deliver(context.Background(), "test-topic", makeTestRecordsTagged("outage", 5))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if depth, _, _, _ := spool.SpoolStats(); depth == 5 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if depth, _, _, _ := spool.SpoolStats(); depth != 5 {
+		t.Fatalf("spool depth = %d, want 5 after failed flush", depth)
+	}
+	if _, dropped, _ := transport.BufferStats(); dropped != 0 {
+		t.Errorf("dropped = %d, want 0 when spool is attached", dropped)
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	// A successful live POST re-arms replay.
+	transport.Deliver(context.Background(), "test-topic", makeTestRecordsTagged("live", 1))
+
+	deadline = time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, _, replayed, _ := spool.SpoolStats(); replayed == 5 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, _, replayed, _ := spool.SpoolStats()
+	if replayed != 5 {
+		t.Fatalf("replayed = %d, want 5", replayed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	outage := 0
+	for _, raw := range received {
+		if strings.HasPrefix(raw, "outage-") {
+			outage++
+		}
+	}
+	if outage != 5 {
+		t.Errorf("server received %d spooled records, want 5", outage)
+	}
+}
+
+// TestBufferedHTTPTransport_OverflowSpooledNotDropped verifies that entries
+// evicted by MaxBufferedRecords go to the spool when one is attached.
+func TestBufferedHTTPTransport_OverflowSpooledNotDropped(t *testing.T) {
+	block := make(chan struct{})
+
+	batchCfg := newTestBatchConfig()
+	batchCfg.FlushFrequency = 10 * time.Second
+	batchCfg.FlushMessages = 100000
+	batchCfg.MaxBufferedRecords = 100
+
+	transport, _, server := newTestSpooledTransport(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}, batchCfg)
+	defer server.Close()
+	defer transport.Close()
+	defer close(block)
+
+	for i := 0; i < 3; i++ {
+		transport.Deliver(context.Background(), "test-topic", makeTestRecords(50))
+	}
+
+	count, dropped, _ := transport.BufferStats()
+	depth, _, _, _, ok := transport.SpoolStats()
+	if !ok {
+		t.Fatal("SpoolStats ok = false, want true with spool attached")
+	}
+	if dropped != 0 {
+		t.Errorf("dropped = %d, want 0", dropped)
+	}
+	if count+depth != 150 {
+		t.Errorf("accounting mismatch: buffer(%d) + spool(%d) != 150", count, depth)
+	}
+}
+
+// --- Backoff / circuit breaker ---
+
+// TestBufferedHTTPTransport_FailedBatchRequeuedAndRetried verifies that a
+// failed flush puts the records back at the head of the buffer and a later
+// flush delivers them ahead of newer records, without dropping anything.
+func TestBufferedHTTPTransport_FailedBatchRequeuedAndRetried(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		calls    int
+		received []string
+	)
+
+	batchCfg := newTestBatchConfig()
+	batchCfg.FlushFrequency = 20 * time.Millisecond
+	batchCfg.MaxRetries = 3
+
+	transport, server := newTestBufferedTransport(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var wrapper KafkaMessageWrapper2
+		_ = json.Unmarshal(body, &wrapper)
+		for _, msg := range wrapper.Records {
+			received = append(received, msg.Value.Raw)
+		}
+		w.WriteHeader(http.StatusOK)
+	}, batchCfg)
+	defer server.Close()
+
+	transport.Deliver(context.Background(), "test-topic", makeTestRecordsTagged("old", 2))
+	time.Sleep(30 * time.Millisecond)
+	transport.Deliver(context.Background(), "test-topic", makeTestRecordsTagged("new", 1))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	transport.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "old-raw-0,old-raw-1,new-raw-0"
+	if got := strings.Join(received, ","); got != want {
+		t.Errorf("received = %s, want %s", got, want)
+	}
+	if _, dropped, _ := transport.BufferStats(); dropped != 0 {
+		t.Errorf("dropped = %d, want 0", dropped)
+	}
+	if _, _, failures, ok := transport.BreakerStats(); !ok || failures != 0 {
+		t.Errorf("BreakerStats failures = %d ok = %v, want 0/true after recovery", failures, ok)
+	}
+}
+
+// TestBufferedHTTPTransport_OpenBreakerStopsPosting verifies that once the
+// breaker opens, flushes no longer hit KafkaRest and records stay buffered.
+func TestBufferedHTTPTransport_OpenBreakerStopsPosting(t *testing.T) {
+	var calls atomic.Int32
+
+	batchCfg := newTestBatchConfig()
+	batchCfg.FlushFrequency = 10 * time.Millisecond
+	batchCfg.RetryBackoff = time.Millisecond
+	batchCfg.MaxRetryBackoff = time.Millisecond
+	batchCfg.MaxRetries = 100
+	batchCfg.BreakerThreshold = 2
+	batchCfg.BreakerOpenTimeout = time.Hour
+
+	transport, server := newTestBufferedTransport(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, batchCfg)
+	defer server.Close()
+
+	transport.Deliver(context.Background(), "test-topic", makeTestRecords(4))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if state, _, _, _ := transport.BreakerStats(); state == int64(breakerOpen) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	state, opens, failures, _ := transport.BreakerStats()
+	if state != int64(breakerOpen) || opens != 1 || failures != 2 {
+		t.Fatalf("BreakerStats = (%d, %d, %d), want (2, 1, 2)", state, opens, failures)
+	}
+
+	before := calls.Load()
+	time.Sleep(80 * time.Millisecond)
+	if after := calls.Load(); after != before {
+		t.Errorf("open breaker still posted: calls %d -> %d", before, after)
+	}
+	if count, _, _ := transport.BufferStats(); count != 4 {
+		t.Errorf("buffer count = %d, want 4 records held while open", count)
+	}
+
+	// Close must not POST through an open breaker; records are dropped
+	// (no spool attached) and accounted for.
+	transport.Close()
+	if after := calls.Load(); after != before {
+		t.Errorf("Close posted through an open breaker: calls %d -> %d", before, after)
+	}
+	if _, dropped, _ := transport.BufferStats(); dropped != 4 {
+		t.Errorf("dropped = %d, want 4", dropped)
+	}
+}
+
+// failingEncoder is a batchEncoder whose Encode always fails.
+type failingEncoder struct {
+	batchEncoder
+}
+
+func (failingEncoder) Encode([]KafkaRecord) ([]byte, error) {
+	return nil, fmt.Errorf("unencodable")
+}
+
+// TestBufferedHTTPTransport_EncodeFailureCountsDropped checks that records
+// which cannot be encoded show up in the dropped total instead of vanishing.
+func TestBufferedHTTPTransport_EncodeFailureCountsDropped(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	transport, err := NewSpooledHTTPTransport(server.URL, config.SOCKSConfig{}, newTestBatchConfig(), config.KafkaRestConfig{}, nil)
+	if err != nil {
+		t.Fatalf("NewSpooledHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+	transport.encoder = failingEncoder{transport.encoder}
+
+	if err := transport.sendBatch("tp", makeTestRecords(3)); err != nil {
+		t.Fatalf("sendBatch: %v, want nil (not retryable)", err)
+	}
+	if _, dropped, _ := transport.BufferStats(); dropped != 3 {
+		t.Errorf("dropped = %d, want 3", dropped)
+	}
+	if n := posts.Load(); n != 0 {
+		t.Errorf("posts = %d, want 0", n)
+	}
+}
@@ -0,0 +1,161 @@
+package sender
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"resourceagent/internal/logger"
+)
+
+const kafkaRestV3ContentType = "application/json"
+
+// kafkaRestV3Encoder targets the KafkaRest v3 produce API
+// (POST /v3/clusters/{cluster_id}/topics/{topic}/records).
+//
+// A batch is submitted as a stream of concatenated record objects in one
+// request. The proxy answers with one result object per record, in order,
+// each carrying its own error_code, so a batch can partially fail; see
+// ParseResults.
+type kafkaRestV3Encoder struct {
+	clusterID     string
+	format        string // "json", "binary" or "avro"
+	keySchemaID   int
+	valueSchemaID int
+}
+
+// kafkaRestV3Record is one v3 ProduceRequest.
+type kafkaRestV3Record struct {
+	Key   *kafkaRestV3Data `json:"key,omitempty"`
+	Value kafkaRestV3Data  `json:"value"`
+}
+
+// kafkaRestV3Data is a v3 ProduceRequestData. Avro data carries schema_id
+// instead of type.
+type kafkaRestV3Data struct {
+	Type     string      `json:"type,omitempty"`
+	SchemaID int         `json:"schema_id,omitempty"`
+	Data     interface{} `json:"data"`
+}
+
+// kafkaRestV3Result is one v3 ProduceResponse. Successful records report
+// error_code 200.
+type kafkaRestV3Result struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func (kafkaRestV3Encoder) Name() string { return "KafkaRest" }
+
+func (e kafkaRestV3Encoder) URL(baseURL, topic string) string {
+	return baseURL + "/v3/clusters/" + url.PathEscape(e.clusterID) +
+		"/topics/" + url.PathEscape(topic) + "/records"
+}
+
+func (kafkaRestV3Encoder) SetHeaders(h http.Header) {
+	h.Set("Content-Type", kafkaRestV3ContentType)
+}
+
+func (e kafkaRestV3Encoder) Encode(records []KafkaRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf) // newline-separated record stream
+	for _, rec := range records {
+		r, err := e.record(rec)
+		if err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(r); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (e kafkaRestV3Encoder) record(rec KafkaRecord) (kafkaRestV3Record, error) {
+	switch e.format {
+	case "binary":
+		value, err := json.Marshal(rec.Value)
+		if err != nil {
+			return kafkaRestV3Record{}, err
+		}
+		return kafkaRestV3Record{
+			Key:   &kafkaRestV3Data{Type: "BINARY", Data: base64.StdEncoding.EncodeToString([]byte(rec.Key))},
+			Value: kafkaRestV3Data{Type: "BINARY", Data: base64.StdEncoding.EncodeToString(value)},
+		}, nil
+	case "avro":
+		r := kafkaRestV3Record{Value: kafkaRestV3Data{SchemaID: e.valueSchemaID, Data: rec.Value}}
+		if e.keySchemaID > 0 {
+			r.Key = &kafkaRestV3Data{SchemaID: e.keySchemaID, Data: rec.Key}
+		}
+		return r, nil
+	default:
+		return kafkaRestV3Record{
+			Key:   &kafkaRestV3Data{Type: "JSON", Data: rec.Key},
+			Value: kafkaRestV3Data{Type: "JSON", Data: rec.Value},
+		}, nil
+	}
+}
+
+// ParseResults reads the per-record result stream. Records whose result
+// is missing (stream cut short) or carries a retriable error_code (408,
+// 429, 5xx) are returned for retry in a *partialBatchError; records
+// rejected with any other code (e.g. 400 schema mismatch, 404 unknown
+// topic) would fail the same way again and are dropped with a WARN; their
+// count is returned as rejected for the caller's dropped total.
+func (kafkaRestV3Encoder) ParseResults(body io.Reader, records []KafkaRecord) (rejected int, err error) {
+	dec := json.NewDecoder(body)
+	var (
+		retry []KafkaRecord
+		first string
+	)
+	for i, rec := range records {
+		var res kafkaRestV3Result
+		if err := dec.Decode(&res); err != nil {
+			if first == "" {
+				first = resultStreamError(err, len(records)-i)
+			}
+			retry = append(retry, records[i:]...)
+			break
+		}
+		if res.ErrorCode == http.StatusOK {
+			continue
+		}
+		if first == "" {
+			first = fmt.Sprintf("error_code %d: %s", res.ErrorCode, res.Message)
+		}
+		if retriableRecordError(res.ErrorCode) {
+			retry = append(retry, rec)
+		} else {
+			rejected++
+		}
+	}
+
+	if rejected > 0 {
+		log := logger.WithComponent("kafkarest-v3")
+		log.Warn().
+			Int("rejected", rejected).
+			Int("records", len(records)).
+			Str("first_error", first).
+			Msg("KAFKAREST_RECORD_REJECTED: records dropped by the proxy with a non-retriable error")
+	}
+	if len(retry) == 0 {
+		return rejected, nil
+	}
+	return rejected, &partialBatchError{backend: "KafkaRest", retry: retry, total: len(records), first: first}
+}
+
+func retriableRecordError(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+func resultStreamError(err error, missing int) string {
+	if errors.Is(err, io.EOF) {
+		return fmt.Sprintf("result stream ended with %d records unanswered", missing)
+	}
+	return fmt.Sprintf("invalid result stream: %v", err)
+}
@@ -0,0 +1,261 @@
+package sender
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"resourceagent/internal/config"
+)
+
+func decodeV3Stream(t *testing.T, body []byte) []map[string]json.RawMessage {
+	t.Helper()
+	var out []map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for dec.More() {
+		var rec map[string]json.RawMessage
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("body is not a record stream: %v\n%s", err, body)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestKafkaRestV3Encoder_URL(t *testing.T) {
+	enc := kafkaRestV3Encoder{clusterID: "lkc-abc/1"}
+	got := enc.URL("http://proxy:8082", "tp_all_all_resource")
+	if want := "http://proxy:8082/v3/clusters/lkc-abc%2F1/topics/tp_all_all_resource/records"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+}
+
+func TestKafkaRestV3Encoder_JSONStream(t *testing.T) {
+	body, err := kafkaRestV3Encoder{clusterID: "c1", format: "json"}.Encode(makeTestRecords(3))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if n := bytes.Count(body, []byte("\n")); n != 3 {
+		t.Errorf("expected 3 newline-separated records, got %d:\n%s", n, body)
+	}
+	recs := decodeV3Stream(t, body)
+	if len(recs) != 3 {
+		t.Fatalf("records = %d, want 3", len(recs))
+	}
+	var key, value kafkaRestV3Data
+	json.Unmarshal(recs[1]["key"], &key)
+	json.Unmarshal(recs[1]["value"], &value)
+	if key.Type != "JSON" || key.Data != "KEY1" {
+		t.Errorf("key = %+v, want JSON KEY1", key)
+	}
+	if v, ok := value.Data.(map[string]interface{}); value.Type != "JSON" || !ok || v["raw"] != "raw-1" {
+		t.Errorf("value = %+v, want JSON KafkaValue", value)
+	}
+}
+
+func TestKafkaRestV3Encoder_BinaryAndAvro(t *testing.T) {
+	records := makeTestRecords(1)
+
+	body, _ := kafkaRestV3Encoder{format: "binary"}.Encode(records)
+	var bin kafkaRestV3Record
+	json.Unmarshal(body, &bin)
+	raw, _ := base64.StdEncoding.DecodeString(bin.Value.Data.(string))
+	if bin.Value.Type != "BINARY" || !strings.Contains(string(raw), `"raw":"raw-0"`) {
+		t.Errorf("binary value = %+v (%s)", bin.Value, raw)
+	}
+
+	body, _ = kafkaRestV3Encoder{format: "avro", valueSchemaID: 7}.Encode(records)
+	recs := decodeV3Stream(t, body)
+	if _, hasKey := recs[0]["key"]; hasKey {
+		t.Error("avro record without KeySchemaID must not carry a key")
+	}
+	var value kafkaRestV3Data
+	json.Unmarshal(recs[0]["value"], &value)
+	if value.SchemaID != 7 || value.Type != "" {
+		t.Errorf("avro value = %+v, want schema_id 7 and no type", value)
+	}
+
+	body, _ = kafkaRestV3Encoder{format: "avro", keySchemaID: 3, valueSchemaID: 7}.Encode(records)
+	var key kafkaRestV3Data
+	json.Unmarshal(decodeV3Stream(t, body)[0]["key"], &key)
+	if key.SchemaID != 3 || key.Data != "KEY0" {
+		t.Errorf("avro key = %+v, want schema_id 3 KEY0", key)
+	}
+}
+
+func TestKafkaRestV3Encoder_ParseResults(t *testing.T) {
+	records := makeTestRecords(4)
+	enc := kafkaRestV3Encoder{}
+
+	ok := `{"error_code":200,"offset":1}` + "\n"
+	if rejected, err := enc.ParseResults(strings.NewReader(strings.Repeat(ok, 4)), records); err != nil || rejected != 0 {
+		t.Errorf("all accepted: rejected = %d, err = %v", rejected, err)
+	}
+
+	stream := ok +
+		`{"error_code":503,"message":"broker unavailable"}` + "\n" +
+		`{"error_code":400,"message":"schema mismatch"}` + "\n" +
+		ok
+	rejected, err := enc.ParseResults(strings.NewReader(stream), records)
+	if rejected != 1 {
+		t.Errorf("rejected = %d, want 1 (the 400)", rejected)
+	}
+	var pe *partialBatchError
+	if !errors.As(err, &pe) {
+		t.Fatalf("err = %v, want *partialBatchError", err)
+	}
+	if len(pe.retry) != 1 || pe.retry[0].Key != "KEY1" {
+		t.Errorf("retry = %+v, want only the 503 record (400 is dropped)", pe.retry)
+	}
+	if !strings.Contains(pe.Error(), "1 of 4") || !strings.Contains(pe.Error(), "broker unavailable") {
+		t.Errorf("Error() = %q", pe.Error())
+	}
+
+	// Only permanent rejections: nothing to retry.
+	stream = ok + `{"error_code":404,"message":"unknown topic"}` + "\n" + ok + ok
+	if rejected, err := enc.ParseResults(strings.NewReader(stream), records); err != nil || rejected != 1 {
+		t.Errorf("permanent rejection only: rejected = %d, err = %v; want 1, nil", rejected, err)
+	}
+
+	// Stream cut short: unanswered records are retried.
+	_, err = enc.ParseResults(strings.NewReader(ok+ok), records)
+	if !errors.As(err, &pe) || len(pe.retry) != 2 || pe.retry[0].Key != "KEY2" {
+		t.Errorf("truncated stream: err = %v, want KEY2, KEY3 retried", err)
+	}
+}
+
+// v3Proxy answers each streamed record; fail decides a record's error code.
+type v3Proxy struct {
+	mu    sync.Mutex
+	paths []string
+	keys  [][]string
+	fail  func(call int, key string) int
+}
+
+func (p *v3Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	call := len(p.paths)
+	p.paths = append(p.paths, r.URL.Path)
+	var keys []string
+	p.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	sc := bufio.NewScanner(r.Body)
+	for sc.Scan() {
+		var rec kafkaRestV3Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		key := fmt.Sprint(rec.Key.Data)
+		keys = append(keys, key)
+		code := http.StatusOK
+		if p.fail != nil {
+			code = p.fail(call, key)
+		}
+		enc.Encode(kafkaRestV3Result{ErrorCode: code, Message: http.StatusText(code)})
+	}
+	p.mu.Lock()
+	p.keys = append(p.keys, keys)
+	p.mu.Unlock()
+}
+
+func TestBufferedHTTPTransport_V3PartialFailureRequeuesFailedRecords(t *testing.T) {
+	proxy := &v3Proxy{fail: func(call int, key string) int {
+		if call == 0 && key == "KEY1" {
+			return http.StatusTooManyRequests
+		}
+		return http.StatusOK
+	}}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	batchCfg := newTestBatchConfig()
+	batchCfg.MaxBatchSize = 3
+	restCfg := config.KafkaRestConfig{APIVersion: "v3", ClusterID: "c1"}
+	transport, err := NewSpooledHTTPTransport(server.URL, config.SOCKSConfig{}, batchCfg, restCfg, nil)
+	if err != nil {
+		t.Fatalf("NewSpooledHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+
+	unsent, err := transport.sendBatchWithSplit("tp", makeTestRecords(5))
+	if err == nil {
+		t.Fatal("expected a partial failure")
+	}
+	var keys []string
+	for _, r := range unsent {
+		keys = append(keys, r.Key)
+	}
+	// KEY1 failed in the first chunk; KEY3, KEY4 were never sent.
+	if got := strings.Join(keys, ","); got != "KEY1,KEY3,KEY4" {
+		t.Errorf("unsent = %s, want KEY1,KEY3,KEY4", got)
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if len(proxy.paths) != 1 || proxy.paths[0] != "/v3/clusters/c1/topics/tp/records" {
+		t.Errorf("paths = %v, want one v3 records POST", proxy.paths)
+	}
+}
+
+func TestBufferedHTTPTransport_V3RejectedRecordsCountAsDropped(t *testing.T) {
+	proxy := &v3Proxy{fail: func(call int, key string) int {
+		if key == "KEY1" || key == "KEY2" {
+			return http.StatusBadRequest
+		}
+		return http.StatusOK
+	}}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	restCfg := config.KafkaRestConfig{APIVersion: "v3", ClusterID: "c1"}
+	transport, err := NewSpooledHTTPTransport(server.URL, config.SOCKSConfig{}, newTestBatchConfig(), restCfg, nil)
+	if err != nil {
+		t.Fatalf("NewSpooledHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+
+	if err := transport.sendBatch("tp", makeTestRecords(4)); err != nil {
+		t.Fatalf("sendBatch: %v, want nil (rejections are not retried)", err)
+	}
+	if _, dropped, _ := transport.BufferStats(); dropped != 2 {
+		t.Errorf("dropped = %d, want 2", dropped)
+	}
+}
+
+func TestHTTPTransport_V3RetriesOnlyFailedRecords(t *testing.T) {
+	proxy := &v3Proxy{fail: func(call int, key string) int {
+		if call == 0 && key == "KEY0" {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	restCfg := config.KafkaRestConfig{APIVersion: "v3", ClusterID: "c1"}
+	transport, err := NewHTTPTransport(server.URL, config.SOCKSConfig{}, restCfg)
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+
+	if err := transport.Deliver(context.Background(), "tp", makeTestRecords(3)); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if len(proxy.keys) != 2 || len(proxy.keys[1]) != 1 || proxy.keys[1][0] != "KEY0" {
+		t.Errorf("requests = %v, want the retry to carry only KEY0", proxy.keys)
+	}
+}
@@ -0,0 +1,406 @@
+package sender
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+	"resourceagent/internal/network"
+)
+
+const (
+	mqttStatusOnline  = "online"
+	mqttStatusOffline = "offline"
+
+	// mqttDisconnectQuiesce is how long Disconnect waits for in-flight work.
+	// paho takes it in milliseconds.
+	mqttDisconnectQuiesce = 250 * time.Millisecond
+	// mqttFlushTimeout bounds publishing one drained buffer batch.
+	mqttFlushTimeout = 10 * time.Second
+	// mqttStatusTimeout bounds the best-effort "offline" publish on Close.
+	mqttStatusTimeout = 2 * time.Second
+)
+
+// MQTTSender publishes EARS rows to an MQTT broker (SenderType "mqtt").
+//
+// Each row is one message whose payload is the RawFormatter output (Grok
+// text or ParsedDataList JSON), published to TopicTemplate expanded with
+// the equipment info and MetricData.Type. A retained status message on
+// StatusTopic reports "online" after every (re)connect and "offline" on
+// Close; the same "offline" is registered as last will, so the broker
+// publishes it if the agent dies without closing (like the heartbeat's
+// SHUTDOWN status, but also covering crashes).
+//
+// The client reconnects on its own. While the connection is down, or after
+// a publish fails, messages are kept in a FIFO buffer capped at
+// Batch.MaxBufferedRecords (oldest dropped). The buffer is flushed in order
+// after reconnect, and by the next Send that finds it non-empty while
+// connected.
+type MQTTSender struct {
+	client       mqtt.Client
+	eqpInfo      *config.EqpInfoConfig
+	timeDiffFunc func() int64
+	formatter    RawFormatter
+	topicTmpl    string
+	statusTopic  string
+	qos          byte
+	maxBuffered  int
+
+	mu       sync.Mutex
+	pending  []mqttMessage
+	flushing bool // drain is running; new messages queue behind it
+	closed   bool
+
+	// Lock-free observability (see BufferStats).
+	bufferCountObs      atomic.Int64
+	droppedTotal        atomic.Int64
+	bufferHighWaterMark atomic.Int64
+
+	dropLogger zerolog.Logger
+}
+
+type mqttMessage struct {
+	topic   string
+	payload string
+}
+
+// NewMQTTSender creates the sender and starts connecting in the background;
+// it does not wait for the broker, so an unreachable broker at startup only
+// means messages are buffered.
+func NewMQTTSender(cfg *config.Config, timeDiffFunc func() int64) (*MQTTSender, error) {
+	mc := cfg.MQTT
+	var formatter RawFormatter = GrokRawFormatter{}
+	if strings.EqualFold(mc.Format, "json") {
+		formatter = JSONRawFormatter{}
+	}
+
+	base := logger.WithComponent("mqtt-sender")
+	s := &MQTTSender{
+		eqpInfo:      cfg.EqpInfo,
+		timeDiffFunc: timeDiffFunc,
+		formatter:    formatter,
+		topicTmpl:    mc.TopicTemplate,
+		statusTopic:  expandMQTTTopic(mc.StatusTopic, cfg.EqpInfo, "status"),
+		qos:          byte(mc.QoS),
+		maxBuffered:  cfg.Batch.MaxBufferedRecords,
+		dropLogger:   base.Sample(&zerolog.BasicSampler{N: 10}),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(mc.Broker).
+		SetClientID(mqttClientID(mc.ClientID, cfg.EqpInfo)).
+		SetUsername(mc.Username).
+		SetPassword(mc.Password).
+		SetCleanSession(true).
+		SetKeepAlive(mc.KeepAlive).
+		SetConnectTimeout(10*time.Second).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetMaxReconnectInterval(mc.MaxReconnectInterval).
+		SetWill(s.statusTopic, mqttStatusOffline, s.qos, true).
+		SetOnConnectHandler(s.onConnect).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log := logger.WithComponent("mqtt-sender")
+			log.Warn().Err(err).Msg("MQTT connection lost, buffering until reconnect")
+		})
+
+	var tlsCfg *tls.Config
+	if mc.EnableTLS {
+		var err error
+		tlsCfg, err = createTLSConfig(mc.TLSCertFile, mc.TLSKeyFile, mc.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MQTT TLS config: %w", err)
+		}
+		opts.SetTLSConfig(tlsCfg)
+	}
+
+	dial, err := network.DialerFunc(cfg.SOCKSProxy.Host, cfg.SOCKSProxy.Port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOCKS dialer for MQTT: %w", err)
+	}
+	if dial != nil {
+		opts.SetCustomOpenConnectionFn(func(uri *url.URL, _ mqtt.ClientOptions) (net.Conn, error) {
+			return dialMQTT(dial, uri, tlsCfg)
+		})
+	}
+
+	s.client = mqtt.NewClient(opts)
+	s.client.Connect() // ConnectRetry: keeps trying in the background
+
+	base.Info().
+		Str("broker", mc.Broker).
+		Str("topic_template", mc.TopicTemplate).
+		Str("status_topic", s.statusTopic).
+		Int("qos", mc.QoS).
+		Bool("tls", mc.EnableTLS).
+		Msg("MQTT sender started")
+	return s, nil
+}
+
+// dialMQTT opens a broker connection through dial (SOCKS), adding TLS for
+// ssl://, tls:// and mqtts:// brokers.
+func dialMQTT(dial func(network, addr string) (net.Conn, error), uri *url.URL, tlsCfg *tls.Config) (net.Conn, error) {
+	conn, err := dial("tcp", uri.Host)
+	if err != nil {
+		return nil, err
+	}
+	switch uri.Scheme {
+	case "ssl", "tls", "mqtts", "tcps":
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if tlsCfg != nil {
+			cfg = tlsCfg.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = uri.Hostname()
+		}
+		tc := tls.Client(conn, cfg)
+		if err := tc.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tc, nil
+	}
+	return conn, nil
+}
+
+// mqttClientID returns id, or "resourceagent-<eqpid>" when empty.
+func mqttClientID(id string, info *config.EqpInfoConfig) string {
+	if id != "" {
+		return id
+	}
+	if info != nil && info.EqpID != "" {
+		return "resourceagent-" + info.EqpID
+	}
+	return "resourceagent"
+}
+
+// expandMQTTTopic replaces {process}, {model}, {eqpid}, {line} and {type}.
+// Values are made safe as single topic levels ('/', '+', '#' become '_',
+// empty becomes "_").
+func expandMQTTTopic(tmpl string, info *config.EqpInfoConfig, metricType string) string {
+	if info == nil {
+		info = &config.EqpInfoConfig{}
+	}
+	return strings.NewReplacer(
+		"{process}", mqttTopicLevel(info.Process),
+		"{model}", mqttTopicLevel(info.EqpModel),
+		"{eqpid}", mqttTopicLevel(info.EqpID),
+		"{line}", mqttTopicLevel(info.Line),
+		"{type}", mqttTopicLevel(metricType),
+	).Replace(tmpl)
+}
+
+func mqttTopicLevel(v string) string {
+	if v == "" {
+		return "_"
+	}
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(v)
+}
+
+// Send publishes every EARS row of data.
+func (s *MQTTSender) Send(ctx context.Context, data *collector.MetricData) error {
+	if data == nil {
+		return nil
+	}
+	return s.SendBatch(ctx, []*collector.MetricData{data})
+}
+
+// SendBatch publishes the rows of every item, in order.
+func (s *MQTTSender) SendBatch(ctx context.Context, data []*collector.MetricData) error {
+	var msgs []mqttMessage
+	for _, d := range data {
+		if d == nil {
+			continue
+		}
+		records, err := PrepareRecords(d, s.eqpInfo, s.timeDiffFunc(), s.formatter)
+		if err != nil {
+			if errors.Is(err, ErrNoRows) {
+				continue
+			}
+			return fmt.Errorf("failed to prepare records: %w", err)
+		}
+		topic := expandMQTTTopic(s.topicTmpl, s.eqpInfo, d.Type)
+		for _, rec := range records {
+			msgs = append(msgs, mqttMessage{topic: topic, payload: rec.Value.Raw})
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return fmt.Errorf("sender is closed")
+	}
+	connected := s.client.IsConnectionOpen()
+	if !connected || s.flushing || len(s.pending) > 0 {
+		s.bufferLocked(msgs)
+		// A publish failed earlier while connected: nothing else would
+		// retry the buffer until the next reconnect.
+		if connected && !s.flushing {
+			s.flushing = true
+			go s.drain()
+		}
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	if rest, err := s.publish(ctx, msgs); err != nil {
+		s.mu.Lock()
+		s.bufferLocked(rest)
+		s.mu.Unlock()
+		log := logger.WithComponent("mqtt-sender")
+		log.Debug().Err(err).Int("buffered", len(rest)).Msg("MQTT publish failed, buffered for retry")
+	}
+	return nil
+}
+
+// publish sends msgs in order, waiting for each token (PUBACK for QoS 1).
+// On failure it returns the unsent messages, starting with the failed one.
+func (s *MQTTSender) publish(ctx context.Context, msgs []mqttMessage) ([]mqttMessage, error) {
+	for i, m := range msgs {
+		token := s.client.Publish(m.topic, s.qos, false, m.payload)
+		select {
+		case <-token.Done():
+			if err := token.Error(); err != nil {
+				return msgs[i:], err
+			}
+		case <-ctx.Done():
+			return msgs[i:], ctx.Err()
+		}
+	}
+	return nil, nil
+}
+
+// bufferLocked appends msgs, dropping the oldest beyond maxBuffered.
+// Caller holds s.mu.
+func (s *MQTTSender) bufferLocked(msgs []mqttMessage) {
+	s.pending = append(s.pending, msgs...)
+	s.trimLocked()
+}
+
+// requeueLocked puts msgs from an interrupted flush back in front of the
+// messages buffered during it, within the same cap. Caller holds s.mu.
+func (s *MQTTSender) requeueLocked(msgs []mqttMessage) {
+	s.pending = append(msgs, s.pending...)
+	s.trimLocked()
+}
+
+// trimLocked drops the oldest messages beyond maxBuffered and updates the
+// buffer gauges. Caller holds s.mu.
+func (s *MQTTSender) trimLocked() {
+	if s.maxBuffered > 0 && len(s.pending) > s.maxBuffered {
+		drop := len(s.pending) - s.maxBuffered
+		s.pending = append(s.pending[:0:0], s.pending[drop:]...)
+		newTotal := s.droppedTotal.Add(int64(drop))
+		s.dropLogger.Error().
+			Int("dropped", drop).
+			Int64("dropped_total", newTotal).
+			Int("max_buffered", s.maxBuffered).
+			Msg("MQTT_BUFFER_DROP_OLDEST broker unreachable, oldest messages dropped (sampled 1/10)")
+	}
+	n := int64(len(s.pending))
+	s.bufferCountObs.Store(n)
+	if n > s.bufferHighWaterMark.Load() {
+		s.bufferHighWaterMark.Store(n)
+	}
+}
+
+// onConnect runs on every (re)connect: announce "online", then drain the
+// buffer in order. New Sends queue behind the drain to keep ordering.
+func (s *MQTTSender) onConnect(c mqtt.Client) {
+	log := logger.WithComponent("mqtt-sender")
+	c.Publish(s.statusTopic, s.qos, true, mqttStatusOnline)
+
+	s.mu.Lock()
+	if s.flushing || s.closed {
+		s.mu.Unlock()
+		log.Info().Msg("MQTT connected")
+		return
+	}
+	s.flushing = true
+	s.mu.Unlock()
+
+	if sent, err := s.drain(); err == nil {
+		log.Info().Int("flushed", sent).Msg("MQTT connected")
+	}
+}
+
+// drain publishes the buffer in order until it is empty or a publish
+// fails, then clears flushing. The caller sets flushing first.
+func (s *MQTTSender) drain() (sent int, err error) {
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 || s.closed {
+			s.flushing = false
+			s.mu.Unlock()
+			return sent, nil
+		}
+		batch := s.pending
+		s.pending = nil
+		s.bufferCountObs.Store(0)
+		s.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mqttFlushTimeout)
+		rest, err := s.publish(ctx, batch)
+		cancel()
+		sent += len(batch) - len(rest)
+
+		if err != nil {
+			s.mu.Lock()
+			s.requeueLocked(rest)
+			s.flushing = false
+			s.mu.Unlock()
+			log := logger.WithComponent("mqtt-sender")
+			log.Warn().Err(err).Int("remaining", len(rest)).Msg("MQTT buffer flush interrupted")
+			return sent, err
+		}
+	}
+}
+
+// BufferStats returns the reconnect buffer's current size, cumulative
+// dropped messages and high-water mark.
+func (s *MQTTSender) BufferStats() (count, dropped, hwm int64) {
+	return s.bufferCountObs.Load(), s.droppedTotal.Load(), s.bufferHighWaterMark.Load()
+}
+
+// Close publishes the retained "offline" status (best effort) and
+// disconnects. Messages still buffered are dropped.
+func (s *MQTTSender) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	pending := len(s.pending)
+	s.mu.Unlock()
+
+	log := logger.WithComponent("mqtt-sender")
+	if s.client.IsConnectionOpen() {
+		token := s.client.Publish(s.statusTopic, s.qos, true, mqttStatusOffline)
+		if !token.WaitTimeout(mqttStatusTimeout) {
+			log.Warn().Msg("Timed out publishing MQTT offline status")
+		}
+	}
+	if pending > 0 {
+		log.Warn().Int("pending", pending).Msg("Closing MQTT sender with buffered messages")
+	}
+	s.client.Disconnect(uint(mqttDisconnectQuiesce.Milliseconds()))
+	return nil
+}
@@ -0,0 +1,322 @@
+package sender
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/rs/zerolog"
+
+	"resourceagent/internal/config"
+)
+
+// fakeBroker is a minimal MQTT 3.1.1 broker: it acks CONNECT, PUBLISH
+// (QoS 1) and PINGREQ and records what clients send.
+type fakeBroker struct {
+	ln     net.Listener
+	refuse atomic.Bool // close new connections immediately (broker down)
+	noAck  atomic.Bool // record publishes without PUBACK (publish times out)
+
+	mu       sync.Mutex
+	connects []*packets.ConnectPacket
+	conns    []net.Conn
+
+	pubs chan *packets.PublishPacket
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	b := &fakeBroker{ln: ln, pubs: make(chan *packets.PublishPacket, 100)}
+	go b.serve()
+	t.Cleanup(func() {
+		ln.Close()
+		b.dropConnections()
+	})
+	return b
+}
+
+func (b *fakeBroker) url() string { return "tcp://" + b.ln.Addr().String() }
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		if b.refuse.Load() {
+			conn.Close()
+			continue
+		}
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.mu.Unlock()
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			b.mu.Lock()
+			b.connects = append(b.connects, p)
+			b.mu.Unlock()
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			ack.Write(conn)
+		case *packets.PublishPacket:
+			b.pubs <- p
+			if p.Qos == 1 && !b.noAck.Load() {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				ack.Write(conn)
+			}
+		case *packets.PingreqPacket:
+			packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) dropConnections() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.conns {
+		c.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) lastConnect() *packets.ConnectPacket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.connects) == 0 {
+		return nil
+	}
+	return b.connects[len(b.connects)-1]
+}
+
+// waitPublish returns the next publish on topic, skipping others.
+func (b *fakeBroker) waitPublish(t *testing.T, topic string, timeout time.Duration) *packets.PublishPacket {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case p := <-b.pubs:
+			if p.TopicName == topic {
+				return p
+			}
+		case <-deadline:
+			t.Fatalf("no publish on %q within %v", topic, timeout)
+			return nil
+		}
+	}
+}
+
+func newTestMQTTConfig(broker string) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.SenderType = "mqtt"
+	cfg.MQTT.Broker = broker
+	cfg.MQTT.QoS = 1
+	cfg.MQTT.MaxReconnectInterval = 200 * time.Millisecond
+	cfg.Batch.MaxBufferedRecords = 100
+	cfg.EqpInfo = newTestEqpInfo()
+	return cfg
+}
+
+func TestExpandMQTTTopic(t *testing.T) {
+	info := &config.EqpInfoConfig{Process: "P/1", EqpModel: "M+", EqpID: "EQP#1", Line: ""}
+	got := expandMQTTTopic("ears/{process}/{model}/{eqpid}/{line}/{type}", info, "CPU")
+	if want := "ears/P_1/M_/EQP_1/_/CPU"; got != want {
+		t.Errorf("expandMQTTTopic = %q, want %q", got, want)
+	}
+}
+
+func TestMQTTClientID(t *testing.T) {
+	if got := mqttClientID("", newTestEqpInfo()); got != "resourceagent-EQP001" {
+		t.Errorf("default client ID = %q", got)
+	}
+	if got := mqttClientID("custom", newTestEqpInfo()); got != "custom" {
+		t.Errorf("configured client ID = %q", got)
+	}
+}
+
+func TestMQTTSender_BufferDropsOldest(t *testing.T) {
+	s := &MQTTSender{maxBuffered: 3, dropLogger: zerolog.Nop()}
+	for i := 0; i < 5; i++ {
+		s.bufferLocked([]mqttMessage{{topic: "t", payload: string(rune('a' + i))}})
+	}
+	if len(s.pending) != 3 || s.pending[0].payload != "c" || s.pending[2].payload != "e" {
+		t.Errorf("pending = %+v, want c,d,e", s.pending)
+	}
+	count, dropped, hwm := s.BufferStats()
+	if count != 3 || dropped != 2 || hwm != 3 {
+		t.Errorf("BufferStats = (%d, %d, %d), want (3, 2, 3)", count, dropped, hwm)
+	}
+}
+
+func TestMQTTSender_RequeueKeepsCap(t *testing.T) {
+	s := &MQTTSender{maxBuffered: 3, dropLogger: zerolog.Nop()}
+	s.bufferLocked([]mqttMessage{{topic: "t", payload: "d"}, {topic: "t", payload: "e"}})
+	s.requeueLocked([]mqttMessage{{topic: "t", payload: "a"}, {topic: "t", payload: "b"}, {topic: "t", payload: "c"}})
+	if len(s.pending) != 3 || s.pending[0].payload != "c" || s.pending[2].payload != "e" {
+		t.Errorf("pending = %+v, want c,d,e", s.pending)
+	}
+	if count, dropped, _ := s.BufferStats(); count != 3 || dropped != 2 {
+		t.Errorf("BufferStats = (%d, %d), want (3, 2)", count, dropped)
+	}
+}
+
+func TestMQTTSender_PublishStatusAndRows(t *testing.T) {
+	broker := newFakeBroker(t)
+	cfg := newTestMQTTConfig(broker.url())
+
+	s, err := NewMQTTSender(cfg, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewMQTTSender: %v", err)
+	}
+
+	statusTopic := "ears/PROCESS1/MODEL1/EQP001/status"
+	online := broker.waitPublish(t, statusTopic, 5*time.Second)
+	if string(online.Payload) != "online" || !online.Retain {
+		t.Errorf("status = %q retain=%v, want retained online", online.Payload, online.Retain)
+	}
+
+	cp := broker.lastConnect()
+	if cp.ClientIdentifier != "resourceagent-EQP001" {
+		t.Errorf("client ID = %q", cp.ClientIdentifier)
+	}
+	if !cp.WillFlag || !cp.WillRetain || cp.WillTopic != statusTopic || string(cp.WillMessage) != "offline" {
+		t.Errorf("last will = flag:%v retain:%v topic:%q msg:%q, want retained offline on status topic",
+			cp.WillFlag, cp.WillRetain, cp.WillTopic, cp.WillMessage)
+	}
+
+	if err := s.Send(context.Background(), newCPUData()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	row := broker.waitPublish(t, "ears/PROCESS1/MODEL1/EQP001/CPU", 5*time.Second)
+	if row.Qos != 1 || row.Retain {
+		t.Errorf("row qos=%d retain=%v, want qos 1 not retained", row.Qos, row.Retain)
+	}
+	if !strings.Contains(string(row.Payload), "category:cpu,pid:0,proc:@system,metric:total_used_pct,value:50") {
+		t.Errorf("payload = %q, want Grok row", row.Payload)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	offline := broker.waitPublish(t, statusTopic, 5*time.Second)
+	if string(offline.Payload) != "offline" || !offline.Retain {
+		t.Errorf("status on close = %q retain=%v, want retained offline", offline.Payload, offline.Retain)
+	}
+}
+
+func TestMQTTSender_BuffersWhileDisconnected(t *testing.T) {
+	broker := newFakeBroker(t)
+	cfg := newTestMQTTConfig(broker.url())
+	cfg.MQTT.Format = "json"
+
+	s, err := NewMQTTSender(cfg, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewMQTTSender: %v", err)
+	}
+	defer s.Close()
+	statusTopic := "ears/PROCESS1/MODEL1/EQP001/status"
+	broker.waitPublish(t, statusTopic, 5*time.Second)
+
+	broker.refuse.Store(true)
+	broker.dropConnections()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.client.IsConnectionOpen() {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not notice the dropped connection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := s.Send(context.Background(), newCPUData()); err != nil {
+		t.Fatalf("Send while disconnected must buffer, got: %v", err)
+	}
+	if count, _, _ := s.BufferStats(); count != 1 {
+		t.Fatalf("buffered = %d, want 1", count)
+	}
+
+	broker.refuse.Store(false)
+	broker.waitPublish(t, statusTopic, 10*time.Second)
+	row := broker.waitPublish(t, "ears/PROCESS1/MODEL1/EQP001/CPU", 5*time.Second)
+	if !strings.Contains(string(row.Payload), `"EARS_CATEGORY"`) {
+		t.Errorf("payload = %q, want ParsedDataList JSON", row.Payload)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for {
+		if count, _, _ := s.BufferStats(); count == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("buffer not drained after reconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMQTTSender_RetriesBufferWhileConnected(t *testing.T) {
+	broker := newFakeBroker(t)
+	cfg := newTestMQTTConfig(broker.url())
+
+	s, err := NewMQTTSender(cfg, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewMQTTSender: %v", err)
+	}
+	defer s.Close()
+	broker.waitPublish(t, "ears/PROCESS1/MODEL1/EQP001/status", 5*time.Second)
+
+	// The publish goes out but is never acked: it fails and is buffered
+	// while the connection stays up.
+	rowTopic := "ears/PROCESS1/MODEL1/EQP001/CPU"
+	broker.noAck.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = s.Send(ctx, newCPUData())
+	cancel()
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	broker.waitPublish(t, rowTopic, 5*time.Second)
+	if count, _, _ := s.BufferStats(); count != 1 {
+		t.Fatalf("buffered = %d after failed publish, want 1", count)
+	}
+	if !s.client.IsConnectionOpen() {
+		t.Fatal("connection dropped; test needs it to stay up")
+	}
+
+	// The next Send must flush the buffer and itself, not just queue.
+	broker.noAck.Store(false)
+	if err := s.Send(context.Background(), newCPUData()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	broker.waitPublish(t, rowTopic, 5*time.Second)
+	broker.waitPublish(t, rowTopic, 5*time.Second)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if count, _, _ := s.BufferStats(); count == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("buffer not drained while connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
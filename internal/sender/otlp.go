@@ -0,0 +1,281 @@
+package sender
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	otlpMetricsPath  = "/v1/metrics"
+	otlpContentType  = "application/json"
+	otlpScopeName    = "resourceagent"
+	otlpMetricPrefix = "resourceagent."
+
+	// otlpTemporalityCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+	otlpTemporalityCumulative = 2
+)
+
+// otlpCounterStart is the start time of the agent's "_total" counters: they
+// count from process start, so a restart is a counter reset.
+var otlpCounterStart = time.Now()
+
+// OTLPSender exports metrics to an OpenTelemetry collector over OTLP/HTTP
+// (JSON encoding, SenderType "otlp").
+//
+// It is a KafkaSender with an OTLP formatter and a BufferedHTTPTransport
+// using the OTLP encoder, so batching, requeue, circuit breaker and spool
+// behave exactly as for KafkaRest. It is a distinct type so the address
+// refresher, which swaps Kafka-family transports, never touches it.
+type OTLPSender struct {
+	*KafkaSender
+}
+
+// NewOTLPSender creates an OTLP sender for cfg.OTLP, reusing cfg.Batch and
+// cfg.SOCKSProxy. spool may be nil.
+func NewOTLPSender(cfg *config.Config, spool *Spool, timeDiffFunc func() int64) (*OTLPSender, error) {
+	endpoint, err := otlpMetricsURL(cfg.OTLP.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	enc := otlpEncoder{headers: cfg.OTLP.Headers}
+	transport, err := newEncodedHTTPTransport(endpoint, cfg.SOCKSProxy, cfg.Batch, spool, enc, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OTLPSender{
+		KafkaSender: NewKafkaSender(transport, "", cfg.EqpInfo, timeDiffFunc, OTLPRawFormatter{}),
+	}, nil
+}
+
+// otlpMetricsURL appends the standard metrics path to a bare collector URL.
+func otlpMetricsURL(endpoint string) (string, error) {
+	u, err := url.Parse(ensureHTTPScheme(endpoint))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpMetricsPath
+	}
+	return u.String(), nil
+}
+
+// otlpPoint is the Raw payload of an OTLP record: one data point, kept as
+// JSON so buffered and spooled records stay self-describing.
+type otlpPoint struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Proc     string  `json:"proc"`
+	PID      int     `json:"pid"`
+	Value    float64 `json:"value"`
+	TimeNano int64   `json:"t"`
+	// StartNano is set for "_total" counters only. It travels with the
+	// point so a record spooled before a restart keeps its own start time.
+	StartNano int64 `json:"st,omitempty"`
+}
+
+// OTLPRawFormatter produces otlpPoint JSON for the OTLP encoder.
+type OTLPRawFormatter struct{}
+
+func (f OTLPRawFormatter) FormatRaw(row EARSRow, _ string) (string, error) {
+	p := otlpPoint{
+		Name:     otlpMetricPrefix + sanitizeName(row.Category) + "." + sanitizeName(row.Metric),
+		Category: row.Category,
+		Proc:     sanitizeName(row.ProcName),
+		PID:      row.PID,
+		Value:    row.Value,
+		TimeNano: row.Timestamp.UnixNano(),
+	}
+	if isOTLPCounter(p.Name) {
+		p.StartNano = otlpCounterStart.UnixNano()
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal OTLP point: %w", err)
+	}
+	return string(b), nil
+}
+
+// OTLP/JSON wire types (opentelemetry-proto ExportMetricsServiceRequest).
+// 64-bit integers are strings per the protobuf JSON mapping.
+
+type otlpExportRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name  string     `json:"name"`
+	Gauge *otlpGauge `json:"gauge,omitempty"`
+	Sum   *otlpSum   `json:"sum,omitempty"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          float64        `json:"asDouble"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+// isOTLPCounter reports whether a metric is exported as a cumulative sum.
+func isOTLPCounter(name string) bool {
+	return strings.HasSuffix(name, "_total")
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int64) otlpKeyValue {
+	s := strconv.FormatInt(value, 10)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &s}}
+}
+
+// otlpEncoder assembles buffered otlpPoint records into one
+// ExportMetricsServiceRequest. Records are grouped into one resource per
+// equipment (process, model, eqpid, line) and one metric per name. Metrics
+// ending in "_total" become monotonic cumulative sums whose start time is
+// the agent start, the rest gauges. Point timestamps are shifted by
+// KafkaValue.Diff (local - server ms) so they are on server time like the
+// EARS pipeline.
+type otlpEncoder struct {
+	headers map[string]string
+}
+
+func (otlpEncoder) Name() string { return "OTLP" }
+
+func (otlpEncoder) URL(baseURL, _ string) string { return baseURL }
+
+func (e otlpEncoder) SetHeaders(h http.Header) {
+	h.Set("Content-Type", otlpContentType)
+	for k, v := range e.headers {
+		h.Set(k, v)
+	}
+}
+
+func (otlpEncoder) Encode(records []KafkaRecord) ([]byte, error) {
+	type resourceKey struct{ process, model, eqpID, line string }
+
+	var (
+		resources []resourceKey
+		metrics   = make(map[resourceKey]map[string]*otlpMetric)
+		log       = logger.WithComponent("otlp-encoder")
+	)
+	for _, rec := range records {
+		var p otlpPoint
+		if err := json.Unmarshal([]byte(rec.Value.Raw), &p); err != nil {
+			log.Warn().Err(err).Str("esid", rec.Value.ESID).Msg("Skipping record that is not an OTLP point")
+			continue
+		}
+		rk := resourceKey{rec.Value.Process, rec.Value.Model, rec.Value.EqpID, rec.Value.Line}
+		byName, ok := metrics[rk]
+		if !ok {
+			byName = make(map[string]*otlpMetric)
+			metrics[rk] = byName
+			resources = append(resources, rk)
+		}
+
+		m, ok := byName[p.Name]
+		if !ok {
+			m = &otlpMetric{Name: p.Name}
+			if isOTLPCounter(p.Name) {
+				m.Sum = &otlpSum{AggregationTemporality: otlpTemporalityCumulative, IsMonotonic: true}
+			} else {
+				m.Gauge = &otlpGauge{}
+			}
+			byName[p.Name] = m
+		}
+		diffNano := rec.Value.Diff * int64(time.Millisecond)
+		dp := otlpNumberDataPoint{
+			Attributes: []otlpKeyValue{
+				otlpString("category", p.Category),
+				otlpString("proc", p.Proc),
+				otlpInt("pid", int64(p.PID)),
+			},
+			TimeUnixNano: strconv.FormatInt(p.TimeNano-diffNano, 10),
+			AsDouble:     p.Value,
+		}
+		if m.Sum != nil {
+			if p.StartNano > 0 {
+				dp.StartTimeUnixNano = strconv.FormatInt(p.StartNano-diffNano, 10)
+			}
+			m.Sum.DataPoints = append(m.Sum.DataPoints, dp)
+		} else {
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, dp)
+		}
+	}
+
+	req := otlpExportRequest{ResourceMetrics: make([]otlpResourceMetrics, 0, len(resources))}
+	for _, rk := range resources {
+		byName := metrics[rk]
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		ms := make([]otlpMetric, 0, len(names))
+		for _, name := range names {
+			ms = append(ms, *byName[name])
+		}
+
+		req.ResourceMetrics = append(req.ResourceMetrics, otlpResourceMetrics{
+			Resource: otlpResource{Attributes: []otlpKeyValue{
+				otlpString("service.name", otlpScopeName),
+				otlpString("process", rk.process),
+				otlpString("model", rk.model),
+				otlpString("eqpid", rk.eqpID),
+				otlpString("line", rk.line),
+			}},
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: otlpScopeName},
+				Metrics: ms,
+			}},
+		})
+	}
+	return json.Marshal(req)
+}
@@ -0,0 +1,235 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+func otlpTestRecord(t *testing.T, row EARSRow, diff int64) KafkaRecord {
+	t.Helper()
+	raw, err := OTLPRawFormatter{}.FormatRaw(row, "PROCESS1")
+	if err != nil {
+		t.Fatalf("FormatRaw: %v", err)
+	}
+	return KafkaRecord{
+		Key: "EQP001",
+		Value: KafkaValue{
+			Process: "PROCESS1",
+			Line:    "LINE1",
+			EqpID:   "EQP001",
+			Model:   "MODEL1",
+			Diff:    diff,
+			Raw:     raw,
+		},
+		Timestamp: row.Timestamp,
+	}
+}
+
+func attrMap(kvs []otlpKeyValue) map[string]string {
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		switch {
+		case kv.Value.StringValue != nil:
+			m[kv.Key] = *kv.Value.StringValue
+		case kv.Value.IntValue != nil:
+			m[kv.Key] = *kv.Value.IntValue
+		}
+	}
+	return m
+}
+
+func TestOTLPEncoder_GaugesSumsAndResource(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []KafkaRecord{
+		otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "cpu", ProcName: "@system", Metric: "total_used_pct", Value: 12.5}, 1500),
+		otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "cpu", ProcName: "@system", Metric: "core_0_used_pct", Value: 20}, 1500),
+		otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "agent", ProcName: "@self", PID: 42, Metric: "buffer_dropped_total", Value: 7}, 1500),
+		otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "cpu", ProcName: "java", PID: 9, Metric: "total_used_pct", Value: 3}, 1500),
+	}
+
+	body, err := otlpEncoder{}.Encode(records)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var req otlpExportRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("body is not an ExportMetricsServiceRequest: %v", err)
+	}
+
+	if len(req.ResourceMetrics) != 1 {
+		t.Fatalf("expected 1 resource (same equipment), got %d", len(req.ResourceMetrics))
+	}
+	rm := req.ResourceMetrics[0]
+	res := attrMap(rm.Resource.Attributes)
+	want := map[string]string{"service.name": "resourceagent", "process": "PROCESS1", "model": "MODEL1", "eqpid": "EQP001", "line": "LINE1"}
+	for k, v := range want {
+		if res[k] != v {
+			t.Errorf("resource attribute %s = %q, want %q", k, res[k], v)
+		}
+	}
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 metrics, got %d: %+v", len(metrics), metrics)
+	}
+	byName := make(map[string]otlpMetric)
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+
+	used, ok := byName["resourceagent.cpu.total_used_pct"]
+	if !ok || used.Gauge == nil || used.Sum != nil {
+		t.Fatalf("cpu.total_used_pct must be a gauge: %+v", used)
+	}
+	if len(used.Gauge.DataPoints) != 2 {
+		t.Fatalf("expected 2 data points (system + java), got %d", len(used.Gauge.DataPoints))
+	}
+	dp := used.Gauge.DataPoints[1]
+	attrs := attrMap(dp.Attributes)
+	if attrs["category"] != "cpu" || attrs["proc"] != "java" || attrs["pid"] != "9" {
+		t.Errorf("data point attributes = %v", attrs)
+	}
+	if dp.AsDouble != 3 {
+		t.Errorf("AsDouble = %v, want 3", dp.AsDouble)
+	}
+	wantTime := strconv.FormatInt(ts.Add(-1500*time.Millisecond).UnixNano(), 10)
+	if dp.TimeUnixNano != wantTime {
+		t.Errorf("TimeUnixNano = %s, want %s (shifted by Diff)", dp.TimeUnixNano, wantTime)
+	}
+
+	dropped := byName["resourceagent.agent.buffer_dropped_total"]
+	if dropped.Sum == nil || dropped.Gauge != nil {
+		t.Fatalf("_total metric must be a sum: %+v", dropped)
+	}
+	if !dropped.Sum.IsMonotonic || dropped.Sum.AggregationTemporality != otlpTemporalityCumulative {
+		t.Errorf("sum = %+v, want monotonic cumulative", dropped.Sum)
+	}
+	wantStart := strconv.FormatInt(otlpCounterStart.Add(-1500*time.Millisecond).UnixNano(), 10)
+	if got := dropped.Sum.DataPoints[0].StartTimeUnixNano; got != wantStart {
+		t.Errorf("StartTimeUnixNano = %s, want agent start %s (shifted by Diff)", got, wantStart)
+	}
+	if st := used.Gauge.DataPoints[0].StartTimeUnixNano; st != "" {
+		t.Errorf("gauge StartTimeUnixNano = %s, want unset", st)
+	}
+}
+
+func TestOTLPEncoder_GroupsByEquipment(t *testing.T) {
+	ts := time.Now()
+	a := otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "cpu", Metric: "total_used_pct"}, 0)
+	b := otlpTestRecord(t, EARSRow{Timestamp: ts, Category: "cpu", Metric: "total_used_pct"}, 0)
+	b.Value.EqpID = "EQP002"
+
+	body, err := otlpEncoder{}.Encode([]KafkaRecord{a, b})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var req otlpExportRequest
+	json.Unmarshal(body, &req)
+	if len(req.ResourceMetrics) != 2 {
+		t.Errorf("expected 2 resources, got %d", len(req.ResourceMetrics))
+	}
+}
+
+func TestOTLPMetricsURL(t *testing.T) {
+	tests := []struct {
+		endpoint, want string
+	}{
+		{"http://otel:4318", "http://otel:4318/v1/metrics"},
+		{"http://otel:4318/", "http://otel:4318/v1/metrics"},
+		{"otel:4318", "http://otel:4318/v1/metrics"},
+		{"https://gw.example.com/otlp/v1/metrics", "https://gw.example.com/otlp/v1/metrics"},
+	}
+	for _, tt := range tests {
+		got, err := otlpMetricsURL(tt.endpoint)
+		if err != nil {
+			t.Errorf("otlpMetricsURL(%q): %v", tt.endpoint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("otlpMetricsURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestOTLPSender_PostsBufferedBatch(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		path    string
+		ctype   string
+		apiKey  string
+		request otlpExportRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		path = r.URL.Path
+		ctype = r.Header.Get("Content-Type")
+		apiKey = r.Header.Get("X-Api-Key")
+		json.Unmarshal(body, &request)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Batch = newTestBatchConfig()
+	cfg.OTLP = config.OTLPConfig{Endpoint: server.URL, Headers: map[string]string{"X-Api-Key": "secret"}}
+	cfg.EqpInfo = &config.EqpInfoConfig{Process: "PROCESS1", EqpModel: "MODEL1", EqpID: "EQP001", Line: "LINE1"}
+
+	s, err := NewOTLPSender(cfg, nil, func() int64 { return 0 })
+	if err != nil {
+		t.Fatalf("NewOTLPSender: %v", err)
+	}
+	if err := s.Send(context.Background(), newCPUData()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := s.Close(); err != nil { // Close flushes the buffer
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/v1/metrics" {
+		t.Errorf("path = %q, want /v1/metrics", path)
+	}
+	if ctype != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ctype)
+	}
+	if apiKey != "secret" {
+		t.Errorf("X-Api-Key = %q, want configured header", apiKey)
+	}
+	if len(request.ResourceMetrics) != 1 || len(request.ResourceMetrics[0].ScopeMetrics[0].Metrics) == 0 {
+		t.Fatalf("expected CPU metrics in export request, got %+v", request)
+	}
+	if eqp := attrMap(request.ResourceMetrics[0].Resource.Attributes)["eqpid"]; eqp != "EQP001" {
+		t.Errorf("resource eqpid = %q, want EQP001", eqp)
+	}
+}
+
+func TestOTLPSender_ErrorNamesBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	url, _ := otlpMetricsURL(server.URL)
+	transport, err := newEncodedHTTPTransport(url, config.SOCKSConfig{}, newTestBatchConfig(), nil, otlpEncoder{}, nil)
+	if err != nil {
+		t.Fatalf("newEncodedHTTPTransport: %v", err)
+	}
+	defer transport.Close()
+
+	err = transport.sendBatch("", []KafkaRecord{otlpTestRecord(t, EARSRow{Timestamp: time.Now(), Category: "cpu", Metric: "x"}, 0)})
+	if err == nil || err.Error() != "OTLP returned HTTP 400" {
+		t.Errorf("err = %v, want \"OTLP returned HTTP 400\"", err)
+	}
+}
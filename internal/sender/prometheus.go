@@ -0,0 +1,238 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+// promNamespace prefixes every exported metric family.
+const promNamespace = "resourceagent"
+
+// PrometheusExporter keeps the latest MetricData per collector and serves it
+// at /metrics in the Prometheus text exposition format (version 0.0.4).
+//
+// It implements Sender so it can run next to the configured sender (see
+// TeeSender): Send only swaps a map entry and never blocks. Samples are
+// derived from ConvertToEARSRows, one family per (category, metric):
+//
+//	resourceagent_<category>_<metric>{category="cpu",proc="@system",pid="0"} 12.5
+//
+// Metrics ending in "_total" are typed counter, everything else gauge.
+// Rows that map to the same series (e.g. several partitions of one device,
+// a plugin repeating a metric, names differing only in characters Prometheus
+// does not allow) are written once, with the last value winning.
+type PrometheusExporter struct {
+	addr string
+
+	mu     sync.RWMutex
+	latest map[string]*collector.MetricData // by MetricData.Type
+
+	srv      *http.Server
+	listener net.Listener
+	done     chan struct{}
+}
+
+// NewPrometheusExporter creates an exporter for cfg. Call Start to listen.
+func NewPrometheusExporter(cfg config.PrometheusConfig) *PrometheusExporter {
+	return &PrometheusExporter{
+		addr:   cfg.ListenAddress,
+		latest: make(map[string]*collector.MetricData),
+	}
+}
+
+// Start binds the listen address and serves /metrics in the background.
+// Bind errors are returned synchronously.
+func (e *PrometheusExporter) Start() error {
+	ln, err := net.Listen("tcp", e.addr)
+	if err != nil {
+		return fmt.Errorf("prometheus exporter listen on %s: %w", e.addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	e.srv = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	e.listener = ln
+	e.done = make(chan struct{})
+
+	log := logger.WithComponent("prometheus-exporter")
+	go func() {
+		defer close(e.done)
+		if err := e.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Prometheus exporter stopped")
+		}
+	}()
+	log.Info().Str("addr", ln.Addr().String()).Msg("Prometheus exporter listening")
+	return nil
+}
+
+// Addr returns the bound address (useful when configured with port 0).
+func (e *PrometheusExporter) Addr() string {
+	if e.listener == nil {
+		return e.addr
+	}
+	return e.listener.Addr().String()
+}
+
+// Send records data as the latest snapshot of its collector.
+func (e *PrometheusExporter) Send(_ context.Context, data *collector.MetricData) error {
+	if data == nil {
+		return nil
+	}
+	e.mu.Lock()
+	e.latest[data.Type] = data
+	e.mu.Unlock()
+	return nil
+}
+
+// SendBatch records every item (later items of the same type win).
+func (e *PrometheusExporter) SendBatch(ctx context.Context, data []*collector.MetricData) error {
+	for _, d := range data {
+		e.Send(ctx, d)
+	}
+	return nil
+}
+
+// Close shuts the HTTP server down.
+func (e *PrometheusExporter) Close() error {
+	if e.srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := e.srv.Shutdown(ctx)
+	<-e.done
+	return err
+}
+
+// ServeHTTP renders the current snapshot.
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteTo(w)
+}
+
+// promSample is one exposition line of a family.
+type promSample struct {
+	category, proc string
+	pid            int
+	value          float64
+}
+
+// promSeries identifies a sample: family name plus label values.
+type promSeries struct {
+	name, category, proc string
+	pid                  int
+}
+
+// WriteTo writes the exposition text for the current snapshot to w.
+func (e *PrometheusExporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.RLock()
+	snapshot := make([]*collector.MetricData, 0, len(e.latest))
+	for _, d := range e.latest {
+		snapshot = append(snapshot, d)
+	}
+	e.mu.RUnlock()
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Type < snapshot[j].Type })
+
+	// Samples of a family must be contiguous, so group before writing.
+	// A series may appear only once per scrape: a repeat overwrites the
+	// earlier sample in place.
+	families := make(map[string][]promSample)
+	seen := make(map[promSeries]int) // index into families[name]
+	for _, data := range snapshot {
+		for _, row := range ConvertToEARSRows(data) {
+			name := promNamespace + "_" + promName(row.Category) + "_" + promName(row.Metric)
+			s := promSample{
+				category: row.Category,
+				proc:     sanitizeName(row.ProcName),
+				pid:      row.PID,
+				value:    row.Value,
+			}
+			key := promSeries{name: name, category: s.category, proc: s.proc, pid: s.pid}
+			if i, ok := seen[key]; ok {
+				families[name][i] = s
+				continue
+			}
+			seen[key] = len(families[name])
+			families[name] = append(families[name], s)
+		}
+	}
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		typ := "gauge"
+		if strings.HasSuffix(name, "_total") {
+			typ = "counter"
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, typ)
+		for _, s := range families[name] {
+			fmt.Fprintf(&b, "%s{category=\"%s\",proc=\"%s\",pid=\"%d\"} %s\n",
+				name, promLabelValue(s.category), promLabelValue(s.proc), s.pid,
+				strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+
+	// Per-collector freshness so stale snapshots (collector disabled or
+	// failing) can be alerted on.
+	name := promNamespace + "_collector_last_timestamp_seconds"
+	fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+	for _, data := range snapshot {
+		fmt.Fprintf(&b, "%s{collector=\"%s\"} %d\n", name, promLabelValue(data.Type), data.Timestamp.Unix())
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// promName maps an EARS category/metric to the Prometheus name charset
+// [a-zA-Z0-9_], collapsing runs of invalid characters to one underscore.
+func promName(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	lastUnderscore := false
+	for _, r := range s {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !valid {
+			r = '_'
+		}
+		if r == '_' {
+			if lastUnderscore {
+				continue
+			}
+			lastUnderscore = true
+		} else {
+			lastUnderscore = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// promLabelValue escapes backslash, double quote and newline.
+func promLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,163 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/config"
+)
+
+func renderExporter(t *testing.T, e *PrometheusExporter) string {
+	t.Helper()
+	var b strings.Builder
+	if _, err := e.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	return b.String()
+}
+
+func TestPrometheusExporter_RendersEARSRows(t *testing.T) {
+	e := NewPrometheusExporter(config.PrometheusConfig{})
+	e.Send(context.Background(), &collector.MetricData{
+		Type:      "CPU",
+		Timestamp: testTimestamp,
+		Data:      collector.CPUData{UsagePercent: 12.5, PerCore: []float64{10, 15}},
+	})
+	e.Send(context.Background(), &collector.MetricData{
+		Type:      "CPUProcess",
+		Timestamp: testTimestamp,
+		Data: collector.ProcessCPUData{Processes: []collector.ProcessCPU{
+			{PID: 1234, Name: "python.exe", CPUPercent: 3.5},
+		}},
+	})
+	e.Send(context.Background(), &collector.MetricData{
+		Type:      "SelfMetrics",
+		Timestamp: testTimestamp,
+		Data:      collector.SelfMetricsData{BufferDroppedTotal: 5},
+	})
+
+	out := renderExporter(t, e)
+	for _, want := range []string{
+		"# TYPE resourceagent_cpu_total_used_pct gauge\n",
+		`resourceagent_cpu_total_used_pct{category="cpu",proc="@system",pid="0"} 12.5`,
+		`resourceagent_cpu_core_1_used_pct{category="cpu",proc="@system",pid="0"} 15`,
+		`resourceagent_cpu_used_pct{category="cpu",proc="python.exe",pid="1234"} 3.5`,
+		"# TYPE resourceagent_agent_buffer_dropped_total counter\n",
+		`resourceagent_agent_buffer_dropped_total{category="agent",proc="@system",pid="0"} 5`,
+		`resourceagent_collector_last_timestamp_seconds{collector="CPU"} `,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestPrometheusExporter_KeepsLatestPerCollector(t *testing.T) {
+	e := NewPrometheusExporter(config.PrometheusConfig{})
+	for _, v := range []float64{10, 20} {
+		e.Send(context.Background(), &collector.MetricData{
+			Type: "CPU", Timestamp: testTimestamp, Data: collector.CPUData{UsagePercent: v},
+		})
+	}
+
+	out := renderExporter(t, e)
+	if strings.Count(out, "resourceagent_cpu_total_used_pct{") != 1 {
+		t.Errorf("expected a single sample for the latest snapshot:\n%s", out)
+	}
+	if !strings.Contains(out, "} 20\n") {
+		t.Errorf("expected latest value 20:\n%s", out)
+	}
+}
+
+func TestPrometheusExporter_DedupesSeries(t *testing.T) {
+	e := NewPrometheusExporter(config.PrometheusConfig{})
+	e.Send(context.Background(), &collector.MetricData{
+		Type:      "ModbusPlugin",
+		Timestamp: testTimestamp,
+		Data: collector.PluginData{Plugin: "modbus", Metrics: []collector.PluginMetric{
+			{Name: "temp-c", Value: 40, Proc: "pump"},
+			{Name: "temp_c", Value: 41, Proc: "pump"}, // same Prometheus name
+			{Name: "temp_c", Value: 42, Proc: "pump"}, // repeated metric
+			{Name: "temp_c", Value: 7, Proc: "valve"},
+		}},
+	})
+
+	out := renderExporter(t, e)
+	if n := strings.Count(out, `resourceagent_plugin_temp_c{category="plugin",proc="pump",pid="0"}`); n != 1 {
+		t.Fatalf("pump series written %d times, want 1:\n%s", n, out)
+	}
+	for _, want := range []string{
+		`resourceagent_plugin_temp_c{category="plugin",proc="pump",pid="0"} 42` + "\n",
+		`resourceagent_plugin_temp_c{category="plugin",proc="valve",pid="0"} 7` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "# TYPE resourceagent_plugin_temp_c gauge\n"); n != 1 {
+		t.Errorf("TYPE line written %d times, want 1", n)
+	}
+}
+
+func TestPromName(t *testing.T) {
+	tests := map[string]string{
+		"total_used_pct":     "total_used_pct",
+		"CPU Package (Tctl)": "CPU_Package_Tctl",
+		"nvme0n1.read-bytes": "nvme0n1_read_bytes",
+		"__already__":        "already",
+	}
+	for in, want := range tests {
+		if got := promName(in); got != want {
+			t.Errorf("promName(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := promLabelValue(`a"b\c`); got != `a\"b\\c` {
+		t.Errorf("promLabelValue = %q", got)
+	}
+}
+
+func TestPrometheusExporter_ServesHTTP(t *testing.T) {
+	e := NewPrometheusExporter(config.PrometheusConfig{ListenAddress: "127.0.0.1:0"})
+	if err := e.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Close()
+	e.Send(context.Background(), &collector.MetricData{
+		Type: "CPU", Timestamp: testTimestamp, Data: collector.CPUData{UsagePercent: 1},
+	})
+
+	resp, err := http.Get("http://" + e.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(string(body), "resourceagent_cpu_total_used_pct") {
+		t.Errorf("body missing cpu metric:\n%s", body)
+	}
+}
+
+func TestPrometheusExporter_StartBindError(t *testing.T) {
+	e := NewPrometheusExporter(config.PrometheusConfig{ListenAddress: "127.0.0.1:0"})
+	if err := e.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	dup := NewPrometheusExporter(config.PrometheusConfig{ListenAddress: e.Addr()})
+	if err := dup.Start(); err == nil {
+		dup.Close()
+		t.Fatal("expected bind error on an address already in use")
+	}
+}
@@ -27,3 +27,35 @@ type Sender interface {
 type BufferStatsProvider interface {
 	BufferStats() (count, dropped, hwm int64)
 }
+
+// SpoolStatsProvider exposes on-disk spool observability. Implemented by
+// BufferedHTTPTransport and surfaced through KafkaSender; ok is false when
+// no Spool is attached. Mirrored by collector.SpoolStatsProvider (duck typing).
+type SpoolStatsProvider interface {
+	SpoolStats() (depth, bytes, replayed, expired int64, ok bool)
+}
+
+// BreakerStatsProvider exposes the KafkaRest circuit breaker state
+// (0=closed, 1=half-open, 2=open). Implemented by BufferedHTTPTransport and
+// surfaced through KafkaSender; ok is false for transports without a
+// breaker. Mirrored by collector.BreakerStatsProvider (duck typing).
+type BreakerStatsProvider interface {
+	BreakerStats() (state, opens, failures int64, ok bool)
+}
+
+// CompressionStatsProvider exposes KafkaRest request compression byte
+// counters: rawBytes before and wireBytes after Content-Encoding, over all
+// delivered request bodies. Implemented by the KafkaRest transports and
+// surfaced through KafkaSender; ok is false when compression is off.
+// Mirrored by collector.CompressionStatsProvider (duck typing).
+type CompressionStatsProvider interface {
+	CompressionStats() (rawBytes, wireBytes int64, ok bool)
+}
+
+// DeliveryStatsProvider exposes per-record delivery acknowledgements.
+// Implemented by SaramaTransport and surfaced through KafkaSender; ok is
+// false for transports that do not track acks. Mirrored by
+// collector.DeliveryStatsProvider (duck typing).
+type DeliveryStatsProvider interface {
+	DeliveryStats() (acked, failed, inFlight int64, lastErr string, ok bool)
+}
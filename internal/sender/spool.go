@@ -0,0 +1,449 @@
+package sender
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"resourceagent/internal/config"
+	"resourceagent/internal/logger"
+)
+
+const (
+	spoolSegmentExt  = ".seg"
+	spoolCursorFile  = "cursor.json"
+	spoolSegmentName = "%020d" + spoolSegmentExt
+)
+
+// ErrSpoolEntryTooLarge is returned by Append when a single entry exceeds
+// the spool's total size cap and can never be stored.
+var ErrSpoolEntryTooLarge = errors.New("spool entry larger than MaxSizeMB")
+
+// spoolEntry is one line in a segment file: the records of a single
+// failed or evicted Deliver/flush, together with their target topic.
+type spoolEntry struct {
+	Topic   string        `json:"topic"`
+	Records []KafkaRecord `json:"records"`
+}
+
+// spoolSegment is the in-memory index of one segment file.
+type spoolSegment struct {
+	seq     uint64
+	bytes   int64
+	records int64
+	modTime time.Time
+}
+
+// spoolCursor is the persisted replay position (segment + byte offset)
+// so that a restart does not resend records that were already replayed.
+type spoolCursor struct {
+	Seq    uint64 `json:"seq"`
+	Offset int64  `json:"offset"`
+}
+
+// SpoolBatch is a contiguous run of entries read from the head segment.
+// It must be passed back to Commit (sent successfully) or Release (send
+// failed, keep for the next attempt).
+type SpoolBatch struct {
+	Entries []spoolEntry
+	Records int
+
+	seq       uint64
+	endOffset int64
+}
+
+// Spool is a size-capped, append-only, on-disk FIFO of KafkaRecords.
+//
+// Entries are JSON lines appended to numbered segment files under Dir. The
+// active (newest) segment is rolled once it reaches SegmentSizeMB; when the
+// total size would exceed MaxSizeMB the oldest segments are evicted, and
+// segments older than MaxAge are discarded. Replay reads from the oldest
+// segment and advances a persisted cursor, so the spool survives restarts
+// and is drained in the order it was written (at-least-once: a crash
+// between a successful POST and the cursor write may resend one batch).
+//
+// File handles are opened per operation and never held between calls, so
+// a single Spool can be shared by the transports that the address
+// refresher swaps in and out.
+type Spool struct {
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+	maxAge       time.Duration
+	replayRate   int
+
+	mu         sync.Mutex
+	segments   []*spoolSegment // oldest first
+	active     *spoolSegment   // nil → next Append starts a new segment
+	nextSeq    uint64
+	readSeq    uint64 // segment the cursor points into
+	readOffset int64
+	inflight   bool // a SpoolBatch is outstanding
+
+	// Lock-free observability counters (see SpoolStats).
+	depth    atomic.Int64
+	bytes    atomic.Int64
+	replayed atomic.Int64
+	expired  atomic.Int64
+}
+
+// OpenSpool opens (or creates) the spool directory described by cfg and
+// rebuilds the segment index from the files already on disk.
+func OpenSpool(cfg config.SpoolConfig) (*Spool, error) {
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	s := &Spool{
+		dir:          cfg.Dir,
+		maxBytes:     int64(cfg.MaxSizeMB) * 1024 * 1024,
+		segmentBytes: int64(cfg.SegmentSizeMB) * 1024 * 1024,
+		maxAge:       cfg.MaxAge,
+		replayRate:   cfg.ReplayRecordsPerSec,
+		nextSeq:      1,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	log := logger.WithComponent("kafkarest-spool")
+	log.Info().
+		Str("dir", s.dir).
+		Int("segments", len(s.segments)).
+		Int64("depth", s.depth.Load()).
+		Int64("bytes", s.bytes.Load()).
+		Msg("Spool opened")
+
+	return s, nil
+}
+
+// ReplayRate returns the configured replay limit in records per second.
+func (s *Spool) ReplayRate() int {
+	return s.replayRate
+}
+
+// SpoolStats returns lock-free observability snapshots for the spool.
+//
+//	depth    — records currently waiting on disk
+//	bytes    — total size of all segment files
+//	replayed — cumulative records replayed successfully since process start
+//	expired  — cumulative records discarded by MaxAge or MaxSizeMB eviction
+func (s *Spool) SpoolStats() (depth, bytes, replayed, expired int64) {
+	return s.depth.Load(), s.bytes.Load(), s.replayed.Load(), s.expired.Load()
+}
+
+// Append writes one entry to the active segment, rolling and evicting
+// segments as needed to honour SegmentSizeMB and MaxSizeMB.
+func (s *Spool) Append(topic string, records []KafkaRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(spoolEntry{Topic: topic, Records: records})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spool entry: %w", err)
+	}
+	line = append(line, '\n')
+	n := int64(len(line))
+	if n > s.maxBytes {
+		return ErrSpoolEntryTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.expireLocked(now)
+	for s.bytes.Load()+n > s.maxBytes && len(s.segments) > 0 {
+		s.evictOldestLocked("size")
+	}
+
+	if s.active == nil || s.active.bytes+n > s.segmentBytes {
+		s.active = &spoolSegment{seq: s.nextSeq, modTime: now}
+		s.nextSeq++
+		s.segments = append(s.segments, s.active)
+	}
+
+	f, err := os.OpenFile(s.segmentPath(s.active.seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	_, writeErr := f.Write(line)
+	closeErr := f.Close()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write spool segment: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close spool segment: %w", closeErr)
+	}
+
+	s.active.bytes += n
+	s.active.records += int64(len(records))
+	s.active.modTime = now
+	s.bytes.Add(n)
+	s.depth.Add(int64(len(records)))
+	return nil
+}
+
+// Next reads up to maxRecords records (at least one entry) from the head
+// of the spool. ok is false when the spool is empty or another batch is
+// still outstanding. The returned batch must be passed to Commit or Release.
+func (s *Spool) Next(maxRecords int) (batch *SpoolBatch, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inflight {
+		return nil, false, nil
+	}
+	s.expireLocked(time.Now())
+
+	for len(s.segments) > 0 {
+		head := s.segments[0]
+		if s.readSeq != head.seq {
+			s.readSeq, s.readOffset = head.seq, 0
+		}
+		if s.readOffset >= head.bytes {
+			if head == s.active {
+				return nil, false, nil
+			}
+			s.removeHeadLocked()
+			continue
+		}
+		// Seal the active segment so the reader never races a writer.
+		if head == s.active {
+			s.active = nil
+		}
+
+		b, readErr := s.readLocked(head, maxRecords)
+		if readErr != nil {
+			return nil, false, readErr
+		}
+		if len(b.Entries) == 0 {
+			// Only undecodable lines were left; skip past them.
+			s.readOffset = b.endOffset
+			continue
+		}
+		s.inflight = true
+		return b, true, nil
+	}
+	return nil, false, nil
+}
+
+// Commit marks a batch as delivered: the cursor advances past it and the
+// head segment is deleted once fully consumed.
+func (s *Spool) Commit(b *SpoolBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inflight = false
+	if len(s.segments) == 0 || s.segments[0].seq != b.seq {
+		// Segment was evicted while the batch was in flight.
+		return
+	}
+	head := s.segments[0]
+	s.readOffset = b.endOffset
+	head.records -= int64(b.Records)
+	s.depth.Add(-int64(b.Records))
+	s.replayed.Add(int64(b.Records))
+
+	if s.readOffset >= head.bytes && head != s.active {
+		s.removeHeadLocked()
+	}
+	s.writeCursorLocked()
+}
+
+// Release returns an undelivered batch; it will be read again by Next.
+func (s *Spool) Release(_ *SpoolBatch) {
+	s.mu.Lock()
+	s.inflight = false
+	s.mu.Unlock()
+}
+
+func (s *Spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf(spoolSegmentName, seq))
+}
+
+// readLocked decodes entries from seg starting at the cursor until
+// maxRecords is reached. Undecodable (e.g. torn by a crash) lines are
+// skipped.
+func (s *Spool) readLocked(seg *spoolSegment, maxRecords int) (*SpoolBatch, error) {
+	f, err := os.Open(s.segmentPath(seg.seq))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(s.readOffset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek spool segment: %w", err)
+	}
+
+	b := &SpoolBatch{seq: seg.seq, endOffset: s.readOffset}
+	r := bufio.NewReader(f)
+	for b.Records == 0 || b.Records < maxRecords {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			b.endOffset += int64(len(line))
+			var e spoolEntry
+			if err := json.Unmarshal(line, &e); err == nil && len(e.Records) > 0 {
+				b.Entries = append(b.Entries, e)
+				b.Records += len(e.Records)
+			}
+		}
+		if readErr != nil {
+			if readErr == io.EOF {
+				// A trailing partial line (torn write) counts as consumed
+				// so the segment can still be retired.
+				b.endOffset += int64(len(line))
+				break
+			}
+			return nil, fmt.Errorf("failed to read spool segment: %w", readErr)
+		}
+	}
+	return b, nil
+}
+
+// expireLocked drops sealed segments whose last write is older than maxAge.
+func (s *Spool) expireLocked(now time.Time) {
+	if s.maxAge <= 0 {
+		return
+	}
+	for len(s.segments) > 0 {
+		head := s.segments[0]
+		if head == s.active || now.Sub(head.modTime) < s.maxAge {
+			return
+		}
+		s.evictOldestLocked("age")
+	}
+}
+
+// evictOldestLocked discards the head segment and counts its unread
+// records as expired.
+func (s *Spool) evictOldestLocked(reason string) {
+	head := s.segments[0]
+	lost := head.records
+	if head == s.active {
+		s.active = nil
+	}
+	s.removeHeadLocked()
+	s.expired.Add(lost)
+
+	log := logger.WithComponent("kafkarest-spool")
+	log.Warn().
+		Str("reason", reason).
+		Uint64("segment", head.seq).
+		Int64("records", lost).
+		Int64("expired_total", s.expired.Load()).
+		Msg("SPOOL_EXPIRED spool segment discarded")
+}
+
+// removeHeadLocked deletes the head segment file and updates counters.
+// head.records must already reflect only unread records.
+func (s *Spool) removeHeadLocked() {
+	head := s.segments[0]
+	if err := os.Remove(s.segmentPath(head.seq)); err != nil && !os.IsNotExist(err) {
+		log := logger.WithComponent("kafkarest-spool")
+		log.Warn().Err(err).Uint64("segment", head.seq).Msg("Failed to remove spool segment")
+	}
+	s.segments = s.segments[1:]
+	s.bytes.Add(-head.bytes)
+	s.depth.Add(-head.records)
+	if s.readSeq == head.seq {
+		s.readSeq, s.readOffset = 0, 0
+	}
+	s.writeCursorLocked()
+}
+
+func (s *Spool) writeCursorLocked() {
+	b, _ := json.Marshal(spoolCursor{Seq: s.readSeq, Offset: s.readOffset})
+	tmp := filepath.Join(s.dir, spoolCursorFile+".tmp")
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, filepath.Join(s.dir, spoolCursorFile))
+}
+
+// load rebuilds the segment index and cursor from disk.
+func (s *Spool) load() error {
+	names, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	var cur spoolCursor
+	if b, err := os.ReadFile(filepath.Join(s.dir, spoolCursorFile)); err == nil {
+		_ = json.Unmarshal(b, &cur)
+	}
+
+	for _, de := range names {
+		name := de.Name()
+		if de.IsDir() || !strings.HasSuffix(name, spoolSegmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		seg := &spoolSegment{seq: seq, bytes: info.Size(), modTime: info.ModTime()}
+		s.segments = append(s.segments, seg)
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].seq < s.segments[j].seq })
+
+	for _, seg := range s.segments {
+		var from int64
+		if seg.seq == cur.Seq && cur.Offset <= seg.bytes {
+			from = cur.Offset
+			s.readSeq, s.readOffset = cur.Seq, cur.Offset
+		} else if seg.seq < cur.Seq {
+			from = seg.bytes // fully replayed before restart, retired below
+		}
+		seg.records = countSpoolRecords(s.segmentPath(seg.seq), from)
+		s.bytes.Add(seg.bytes)
+		s.depth.Add(seg.records)
+	}
+	for len(s.segments) > 0 && s.segments[0].records == 0 {
+		s.removeHeadLocked()
+	}
+	return nil
+}
+
+// countSpoolRecords returns the number of records in path after offset.
+func countSpoolRecords(path string, offset int64) int64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0
+	}
+
+	var n int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var e spoolEntry
+			if json.Unmarshal(line, &e) == nil {
+				n += int64(len(e.Records))
+			}
+		}
+		if err != nil {
+			return n
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package sender
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"resourceagent/internal/config"
+)
+
+func newTestSpoolConfig(dir string) config.SpoolConfig {
+	return config.SpoolConfig{
+		Enabled:             true,
+		Dir:                 dir,
+		MaxSizeMB:           1,
+		SegmentSizeMB:       1,
+		MaxAge:              time.Hour,
+		ReplayRecordsPerSec: 1000,
+	}
+}
+
+func openTestSpool(t *testing.T, cfg config.SpoolConfig) *Spool {
+	t.Helper()
+	s, err := OpenSpool(cfg)
+	if err != nil {
+		t.Fatalf("OpenSpool failed: %v", err)
+	}
+	return s
+}
+
+// drainSpool commits every batch and returns the Raw values in replay order.
+func drainSpool(t *testing.T, s *Spool) []string {
+	t.Helper()
+	var raws []string
+	for {
+		b, ok, err := s.Next(1000)
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if !ok {
+			return raws
+		}
+		for _, e := range b.Entries {
+			for _, r := range e.Records {
+				raws = append(raws, r.Value.Raw)
+			}
+		}
+		s.Commit(b)
+	}
+}
+
+func TestSpool_AppendAndReplayInOrder(t *testing.T) {
+	s := openTestSpool(t, newTestSpoolConfig(t.TempDir()))
+
+	s.Append("topic-a", makeTestRecordsTagged("first", 3))
+	s.Append("topic-a", makeTestRecordsTagged("second", 2))
+
+	depth, bytes, _, _ := s.SpoolStats()
+	if depth != 5 {
+		t.Errorf("depth = %d, want 5", depth)
+	}
+	if bytes <= 0 {
+		t.Errorf("bytes = %d, want > 0", bytes)
+	}
+
+	got := drainSpool(t, s)
+	want := []string{"first-raw-0", "first-raw-1", "first-raw-2", "second-raw-0", "second-raw-1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("replay order = %v, want %v", got, want)
+	}
+
+	depth, _, replayed, _ := s.SpoolStats()
+	if depth != 0 {
+		t.Errorf("depth after drain = %d, want 0", depth)
+	}
+	if replayed != 5 {
+		t.Errorf("replayed = %d, want 5", replayed)
+	}
+}
+
+func TestSpool_ReleaseKeepsBatch(t *testing.T) {
+	s := openTestSpool(t, newTestSpoolConfig(t.TempDir()))
+	s.Append("topic-a", makeTestRecordsTagged("x", 2))
+
+	b, ok, err := s.Next(1000)
+	if err != nil || !ok {
+		t.Fatalf("Next: ok=%v err=%v", ok, err)
+	}
+	if _, ok, _ := s.Next(1000); ok {
+		t.Fatal("second Next must not return a batch while one is in flight")
+	}
+	s.Release(b)
+
+	if got := drainSpool(t, s); len(got) != 2 {
+		t.Errorf("released batch should be replayed again, got %v", got)
+	}
+}
+
+func TestSpool_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newTestSpoolConfig(dir)
+
+	s := openTestSpool(t, cfg)
+	s.Append("topic-a", makeTestRecordsTagged("a", 1))
+	s.Append("topic-a", makeTestRecordsTagged("b", 1))
+
+	// Replay only the first entry before "restarting".
+	b, ok, _ := s.Next(1)
+	if !ok || b.Records != 1 {
+		t.Fatalf("expected a 1-record batch, got ok=%v", ok)
+	}
+	s.Commit(b)
+
+	reopened := openTestSpool(t, cfg)
+	depth, _, _, _ := reopened.SpoolStats()
+	if depth != 1 {
+		t.Errorf("depth after restart = %d, want 1", depth)
+	}
+	got := drainSpool(t, reopened)
+	if len(got) != 1 || got[0] != "b-raw-0" {
+		t.Errorf("replay after restart = %v, want [b-raw-0]", got)
+	}
+
+	// Fully drained segments are removed from disk.
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentExt))
+	if len(matches) != 0 {
+		t.Errorf("expected no segment files after drain, found %v", matches)
+	}
+}
+
+func TestSpool_SizeCapEvictsOldest(t *testing.T) {
+	cfg := newTestSpoolConfig(t.TempDir())
+	s := openTestSpool(t, cfg)
+	// Shrink limits below the MB granularity of the config.
+	s.maxBytes = 4096
+	s.segmentBytes = 1024
+
+	for i := 0; i < 40; i++ {
+		if err := s.Append("topic-a", makeTestRecordsTagged("r", 1)); err != nil {
+			t.Fatalf("Append %d failed: %v", i, err)
+		}
+	}
+
+	depth, bytes, _, expired := s.SpoolStats()
+	if bytes > 4096 {
+		t.Errorf("bytes = %d exceeds cap 4096", bytes)
+	}
+	if expired == 0 {
+		t.Error("expected oldest segments to be evicted")
+	}
+	if depth+expired != 40 {
+		t.Errorf("accounting mismatch: depth(%d) + expired(%d) != 40", depth, expired)
+	}
+}
+
+func TestSpool_MaxAgeExpiresSealedSegments(t *testing.T) {
+	cfg := newTestSpoolConfig(t.TempDir())
+	s := openTestSpool(t, cfg)
+	s.Append("topic-a", makeTestRecords(3))
+
+	// Age the segment and seal it so it becomes eligible.
+	s.segments[0].modTime = time.Now().Add(-2 * time.Hour)
+	s.active = nil
+
+	if _, ok, _ := s.Next(1000); ok {
+		t.Fatal("expired segment must not be replayed")
+	}
+	depth, bytes, _, expired := s.SpoolStats()
+	if depth != 0 || bytes != 0 {
+		t.Errorf("depth=%d bytes=%d, want 0/0 after expiry", depth, bytes)
+	}
+	if expired != 3 {
+		t.Errorf("expired = %d, want 3", expired)
+	}
+}
+
+func TestSpool_SkipsTornTrailingLine(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newTestSpoolConfig(dir)
+	s := openTestSpool(t, cfg)
+	s.Append("topic-a", makeTestRecordsTagged("ok", 1))
+
+	// Simulate a crash mid-write.
+	f, err := os.OpenFile(s.segmentPath(s.segments[0].seq), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"topic":"topic-a","records":[{"Key":`)
+	f.Close()
+
+	reopened := openTestSpool(t, cfg)
+	got := drainSpool(t, reopened)
+	if len(got) != 1 || got[0] != "ok-raw-0" {
+		t.Errorf("replay = %v, want [ok-raw-0]", got)
+	}
+}
@@ -0,0 +1,59 @@
+package sender
+
+import (
+	"context"
+	"errors"
+
+	"resourceagent/internal/collector"
+	"resourceagent/internal/logger"
+)
+
+// TeeSender forwards everything to a primary sender and additionally to
+// observers such as PrometheusExporter. Only the primary's result is
+// returned, so the scheduler's health tracking is unchanged; observers must
+// not block (their errors are logged at debug level).
+type TeeSender struct {
+	primary   Sender
+	observers []Sender
+}
+
+// NewTeeSender wraps primary with observers.
+func NewTeeSender(primary Sender, observers ...Sender) *TeeSender {
+	return &TeeSender{primary: primary, observers: observers}
+}
+
+// Send delivers data to the primary and every observer.
+func (t *TeeSender) Send(ctx context.Context, data *collector.MetricData) error {
+	for _, o := range t.observers {
+		if err := o.Send(ctx, data); err != nil {
+			log := logger.WithComponent("tee-sender")
+			log.Debug().Err(err).Msg("Observer send failed")
+		}
+	}
+	return t.primary.Send(ctx, data)
+}
+
+// SendBatch delivers data to the primary and every observer.
+func (t *TeeSender) SendBatch(ctx context.Context, data []*collector.MetricData) error {
+	for _, o := range t.observers {
+		if err := o.SendBatch(ctx, data); err != nil {
+			log := logger.WithComponent("tee-sender")
+			log.Debug().Err(err).Msg("Observer send failed")
+		}
+	}
+	return t.primary.SendBatch(ctx, data)
+}
+
+// Senders returns the primary followed by the observers (see Unwrap).
+func (t *TeeSender) Senders() []Sender {
+	return append([]Sender{t.primary}, t.observers...)
+}
+
+// Close closes the primary and every observer.
+func (t *TeeSender) Close() error {
+	errs := []error{t.primary.Close()}
+	for _, o := range t.observers {
+		errs = append(errs, o.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,42 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTeeSender_ReturnsPrimaryResultOnly(t *testing.T) {
+	primary := &recordingSender{err: errors.New("primary down")}
+	observer := &recordingSender{}
+	tee := NewTeeSender(primary, observer)
+
+	if err := tee.Send(context.Background(), metric("CPU")); err == nil {
+		t.Error("expected the primary's error")
+	}
+	primary.err = nil
+	observer.err = errors.New("observer down")
+	if err := tee.Send(context.Background(), metric("Memory")); err != nil {
+		t.Errorf("observer error leaked: %v", err)
+	}
+
+	if got := observer.received(); len(got) != 2 {
+		t.Errorf("observer received %v, want both items", got)
+	}
+	if err := tee.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !primary.closed || !observer.closed {
+		t.Error("Close must close primary and observers")
+	}
+}
+
+func TestUnwrap_ThroughTee(t *testing.T) {
+	a, b, obs := &recordingSender{}, &recordingSender{}, &recordingSender{}
+	tee := NewTeeSender(NewTeeSender(a, b), obs)
+
+	got := Unwrap(tee)
+	if len(got) != 3 || got[0] != a || got[1] != b || got[2] != obs {
+		t.Errorf("Unwrap(tee) = %v, want [a b obs]", got)
+	}
+}